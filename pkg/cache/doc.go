@@ -0,0 +1,8 @@
+// Copyright 2020 Lingfei Kong <colin404@foxmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// Package cache implements a thread-safe in-memory store of objects keyed by
+// InstanceID, with secondary indices. It is meant to back list endpoints and
+// controllers which would otherwise query the database.
+package cache // import "github.com/HappyLadySauce/component-base/pkg/cache"
@@ -0,0 +1,63 @@
+// Copyright 2020 Lingfei Kong <colin404@foxmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package cache
+
+import (
+	"strings"
+
+	metav1 "github.com/HappyLadySauce/component-base/pkg/meta/v1"
+	"github.com/HappyLadySauce/component-base/pkg/util/sets"
+)
+
+// IndexFunc knows how to compute the set of indexed values for an object.
+// IndexFunc用来计算对象的索引值。
+type IndexFunc func(obj metav1.Object) ([]string, error)
+
+// Indexers maps a name to an IndexFunc.
+// Indexers是索引名称到IndexFunc的映射。
+type Indexers map[string]IndexFunc
+
+// Index maps the indexed value to a set of keys in the store that match on that value.
+type Index map[string]sets.String
+
+// Indices maps a name to an Index.
+type Indices map[string]Index
+
+// IndexByLabel returns an IndexFunc which indexes objects by the value of the given label.
+// Objects without the label are not indexed.
+// IndexByLabel用来创建按标签值索引对象的IndexFunc。
+func IndexByLabel(key string) IndexFunc {
+	return func(obj metav1.Object) ([]string, error) {
+		if value, ok := obj.GetLabels()[key]; ok {
+			return []string{value}, nil
+		}
+
+		return nil, nil
+	}
+}
+
+// IndexByOwner indexes objects by the InstanceID of each of their owners.
+// IndexByOwner用来按拥有者的InstanceID索引对象。
+func IndexByOwner(obj metav1.Object) ([]string, error) {
+	refs := obj.GetOwnerReferences()
+	owners := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		owners = append(owners, ref.InstanceID)
+	}
+
+	return owners, nil
+}
+
+// IndexByNamePrefix returns an IndexFunc which indexes objects by the part of
+// their name before the first separator. Names without separator are indexed
+// as a whole.
+// IndexByNamePrefix用来创建按名称前缀索引对象的IndexFunc。
+func IndexByNamePrefix(separator string) IndexFunc {
+	return func(obj metav1.Object) ([]string, error) {
+		prefix := strings.SplitN(obj.GetName(), separator, 2)[0]
+
+		return []string{prefix}, nil
+	}
+}
@@ -0,0 +1,321 @@
+// Copyright 2020 Lingfei Kong <colin404@foxmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package cache
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/HappyLadySauce/component-base/pkg/labels"
+	metav1 "github.com/HappyLadySauce/component-base/pkg/meta/v1"
+	"github.com/HappyLadySauce/component-base/pkg/util/sets"
+)
+
+// ErrEmptyInstanceID is returned when storing an object without InstanceID.
+var ErrEmptyInstanceID = errors.New("object has no instanceID")
+
+// Indexer is a thread-safe store of objects keyed by InstanceID, which also
+// maintains a set of indices over the stored objects. Stored objects must not
+// be modified in place: update a copy and store it with Update.
+// Indexer是以InstanceID为键、支持索引的线程安全存储。
+type Indexer interface {
+	// Add inserts obj, replacing any object with the same InstanceID.
+	Add(obj metav1.Object) error
+	// Update replaces the stored object with the same InstanceID as obj.
+	Update(obj metav1.Object) error
+	// Delete removes the object with the same InstanceID as obj.
+	Delete(obj metav1.Object) error
+	// Get returns the object with the given InstanceID.
+	Get(instanceID string) (metav1.Object, bool)
+	// List returns the objects matching selector, sorted by InstanceID.
+	// A nil selector matches everything.
+	List(selector labels.Selector) []metav1.Object
+	// ListKeys returns the InstanceIDs of all the stored objects, sorted.
+	ListKeys() []string
+	// Replace deletes the contents of the store and stores objs instead. It is
+	// used to resync the store with its source of truth.
+	Replace(objs []metav1.Object) error
+	// Index returns the stored objects which share an indexed value with obj.
+	Index(indexName string, obj metav1.Object) ([]metav1.Object, error)
+	// IndexKeys returns the InstanceIDs of the objects whose indexed values contain indexedValue.
+	IndexKeys(indexName, indexedValue string) ([]string, error)
+	// ListIndexFuncValues returns all the values of the given index.
+	ListIndexFuncValues(indexName string) []string
+	// ByIndex returns the objects whose indexed values contain indexedValue, sorted by InstanceID.
+	ByIndex(indexName, indexedValue string) ([]metav1.Object, error)
+	// AddIndexers adds more indexers to the store. The new indices are built
+	// from the objects already stored.
+	AddIndexers(newIndexers Indexers) error
+}
+
+// threadSafeStore implements Indexer.
+type threadSafeStore struct {
+	lock  sync.RWMutex
+	items map[string]metav1.Object
+
+	indexers Indexers
+	indices  Indices
+}
+
+var _ Indexer = &threadSafeStore{}
+
+// NewIndexer returns an empty Indexer using the given indexers.
+// NewIndexer用来创建Indexer。
+func NewIndexer(indexers Indexers) Indexer {
+	copied := Indexers{}
+	for name, indexFunc := range indexers {
+		copied[name] = indexFunc
+	}
+
+	return &threadSafeStore{
+		items:    map[string]metav1.Object{},
+		indexers: copied,
+		indices:  Indices{},
+	}
+}
+
+func (c *threadSafeStore) Add(obj metav1.Object) error {
+	return c.Update(obj)
+}
+
+func (c *threadSafeStore) Update(obj metav1.Object) error {
+	key := obj.GetInstanceID()
+	if key == "" {
+		return ErrEmptyInstanceID
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	old := c.items[key]
+	if err := c.updateIndices(old, obj, key); err != nil {
+		return err
+	}
+	c.items[key] = obj
+
+	return nil
+}
+
+func (c *threadSafeStore) Delete(obj metav1.Object) error {
+	key := obj.GetInstanceID()
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if old, ok := c.items[key]; ok {
+		if err := c.updateIndices(old, nil, key); err != nil {
+			return err
+		}
+		delete(c.items, key)
+	}
+
+	return nil
+}
+
+func (c *threadSafeStore) Get(instanceID string) (metav1.Object, bool) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	obj, ok := c.items[instanceID]
+
+	return obj, ok
+}
+
+func (c *threadSafeStore) List(selector labels.Selector) []metav1.Object {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	list := make([]metav1.Object, 0, len(c.items))
+	for _, key := range sets.StringKeySet(c.items).List() {
+		obj := c.items[key]
+		if selector == nil || selector.Matches(labels.Set(obj.GetLabels())) {
+			list = append(list, obj)
+		}
+	}
+
+	return list
+}
+
+func (c *threadSafeStore) ListKeys() []string {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	return sets.StringKeySet(c.items).List()
+}
+
+func (c *threadSafeStore) Replace(objs []metav1.Object) error {
+	items := make(map[string]metav1.Object, len(objs))
+	for _, obj := range objs {
+		key := obj.GetInstanceID()
+		if key == "" {
+			return ErrEmptyInstanceID
+		}
+		items[key] = obj
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	indices := Indices{}
+	for key, obj := range items {
+		if err := addToIndices(c.indexers, indices, obj, key); err != nil {
+			return err
+		}
+	}
+	c.items, c.indices = items, indices
+
+	return nil
+}
+
+func (c *threadSafeStore) Index(indexName string, obj metav1.Object) ([]metav1.Object, error) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	indexFunc, ok := c.indexers[indexName]
+	if !ok {
+		return nil, fmt.Errorf("index with name %s does not exist", indexName)
+	}
+	values, err := indexFunc(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := sets.NewString()
+	for _, value := range values {
+		keys = keys.Union(c.indices[indexName][value])
+	}
+
+	return c.objects(keys), nil
+}
+
+func (c *threadSafeStore) IndexKeys(indexName, indexedValue string) ([]string, error) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	if _, ok := c.indexers[indexName]; !ok {
+		return nil, fmt.Errorf("index with name %s does not exist", indexName)
+	}
+
+	return c.indices[indexName][indexedValue].List(), nil
+}
+
+func (c *threadSafeStore) ListIndexFuncValues(indexName string) []string {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	return sets.StringKeySet(c.indices[indexName]).List()
+}
+
+func (c *threadSafeStore) ByIndex(indexName, indexedValue string) ([]metav1.Object, error) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	if _, ok := c.indexers[indexName]; !ok {
+		return nil, fmt.Errorf("index with name %s does not exist", indexName)
+	}
+
+	return c.objects(c.indices[indexName][indexedValue]), nil
+}
+
+func (c *threadSafeStore) AddIndexers(newIndexers Indexers) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	for name := range newIndexers {
+		if _, ok := c.indexers[name]; ok {
+			return fmt.Errorf("indexer conflict: %s", name)
+		}
+	}
+
+	indices := Indices{}
+	for key, obj := range c.items {
+		if err := addToIndices(newIndexers, indices, obj, key); err != nil {
+			return err
+		}
+	}
+	for name, indexFunc := range newIndexers {
+		c.indexers[name] = indexFunc
+		if index, ok := indices[name]; ok {
+			c.indices[name] = index
+		}
+	}
+
+	return nil
+}
+
+// objects returns the stored objects with the given keys, sorted by key.
+// The caller must hold the lock.
+func (c *threadSafeStore) objects(keys sets.String) []metav1.Object {
+	list := make([]metav1.Object, 0, len(keys))
+	for _, key := range keys.List() {
+		list = append(list, c.items[key])
+	}
+
+	return list
+}
+
+// updateIndices replaces the entries of oldObj by those of newObj. Either may be nil.
+// The indices are left untouched if an IndexFunc fails. The caller must hold the lock.
+func (c *threadSafeStore) updateIndices(oldObj, newObj metav1.Object, key string) error {
+	newValues := map[string][]string{}
+	if newObj != nil {
+		for name, indexFunc := range c.indexers {
+			values, err := indexFunc(newObj)
+			if err != nil {
+				return fmt.Errorf("unable to calculate an index entry for key %q on index %q: %w", key, name, err)
+			}
+			newValues[name] = values
+		}
+	}
+
+	if oldObj != nil {
+		for name, indexFunc := range c.indexers {
+			// The old values were computed successfully when oldObj was stored.
+			values, _ := indexFunc(oldObj)
+			index := c.indices[name]
+			for _, value := range values {
+				index[value].Delete(key)
+				if index[value].Len() == 0 {
+					delete(index, value)
+				}
+			}
+		}
+	}
+
+	for name, values := range newValues {
+		insertIndex(c.indices, name, values, key)
+	}
+
+	return nil
+}
+
+func addToIndices(indexers Indexers, indices Indices, obj metav1.Object, key string) error {
+	for name, indexFunc := range indexers {
+		values, err := indexFunc(obj)
+		if err != nil {
+			return fmt.Errorf("unable to calculate an index entry for key %q on index %q: %w", key, name, err)
+		}
+		insertIndex(indices, name, values, key)
+	}
+
+	return nil
+}
+
+func insertIndex(indices Indices, name string, values []string, key string) {
+	index := indices[name]
+	if index == nil {
+		index = Index{}
+		indices[name] = index
+	}
+	for _, value := range values {
+		set := index[value]
+		if set == nil {
+			set = sets.NewString()
+			index[value] = set
+		}
+		set.Insert(key)
+	}
+}
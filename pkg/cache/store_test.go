@@ -0,0 +1,157 @@
+// Copyright 2020 Lingfei Kong <colin404@foxmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package cache
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/HappyLadySauce/component-base/pkg/labels"
+	metav1 "github.com/HappyLadySauce/component-base/pkg/meta/v1"
+)
+
+func newObject(id, name string, lbls map[string]string, owners ...string) *metav1.ObjectMeta {
+	obj := &metav1.ObjectMeta{InstanceID: id, Name: name, Labels: lbls}
+	for _, owner := range owners {
+		obj.OwnerReferences = append(obj.OwnerReferences, metav1.OwnerReference{InstanceID: owner})
+	}
+
+	return obj
+}
+
+func keys(objs []metav1.Object) []string {
+	out := []string{}
+	for _, obj := range objs {
+		out = append(out, obj.GetInstanceID())
+	}
+
+	return out
+}
+
+func TestIndexer(t *testing.T) {
+	store := NewIndexer(Indexers{
+		"app":    IndexByLabel("app"),
+		"owner":  IndexByOwner,
+		"prefix": IndexByNamePrefix("-"),
+	})
+
+	objs := []*metav1.ObjectMeta{
+		newObject("secret-1", "web-token", map[string]string{"app": "web"}, "app-1"),
+		newObject("secret-2", "web-cert", map[string]string{"app": "web"}, "app-1"),
+		newObject("secret-3", "db-password", map[string]string{"app": "db"}, "app-2"),
+		newObject("policy-1", "admin", nil, "app-1", "app-2"),
+	}
+	for _, obj := range objs {
+		if err := store.Add(obj); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if err := store.Add(&metav1.ObjectMeta{}); err != ErrEmptyInstanceID {
+		t.Errorf("expected ErrEmptyInstanceID, got %v", err)
+	}
+
+	tests := []struct {
+		index, value string
+		expected     []string
+	}{
+		{"app", "web", []string{"secret-1", "secret-2"}},
+		{"owner", "app-2", []string{"policy-1", "secret-3"}},
+		{"prefix", "web", []string{"secret-1", "secret-2"}},
+		{"prefix", "admin", []string{"policy-1"}},
+		{"app", "none", []string{}},
+	}
+	for _, test := range tests {
+		got, err := store.ByIndex(test.index, test.value)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(keys(got), test.expected) {
+			t.Errorf("ByIndex(%s, %s): expected %v, got %v", test.index, test.value, test.expected, keys(got))
+		}
+	}
+	if _, err := store.ByIndex("missing", "x"); err == nil {
+		t.Errorf("expected an error for a missing index")
+	}
+
+	// Updating an object moves it between index values.
+	if err := store.Update(newObject("secret-2", "db-cert", map[string]string{"app": "db"}, "app-2")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got, _ := store.IndexKeys("app", "web"); !reflect.DeepEqual(got, []string{"secret-1"}) {
+		t.Errorf("unexpected keys after update: %v", got)
+	}
+	if got, _ := store.Index("owner", newObject("x", "x", nil, "app-2")); !reflect.DeepEqual(
+		keys(got), []string{"policy-1", "secret-2", "secret-3"}) {
+		t.Errorf("unexpected Index result: %v", keys(got))
+	}
+
+	if err := store.Delete(objs[0]); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if values := store.ListIndexFuncValues("app"); !reflect.DeepEqual(values, []string{"db"}) {
+		t.Errorf("unexpected index values: %v", values)
+	}
+	if _, ok := store.Get("secret-1"); ok {
+		t.Errorf("secret-1 was not deleted")
+	}
+}
+
+func TestIndexerListAndReplace(t *testing.T) {
+	store := NewIndexer(Indexers{"app": IndexByLabel("app")})
+	_ = store.Add(newObject("secret-1", "a", map[string]string{"app": "web", "tier": "front"}))
+	_ = store.Add(newObject("secret-2", "b", map[string]string{"app": "db"}))
+
+	selector, _ := labels.Parse("app in (web,db),tier!=front")
+	if got := keys(store.List(selector)); !reflect.DeepEqual(got, []string{"secret-2"}) {
+		t.Errorf("unexpected List result: %v", got)
+	}
+	if got := keys(store.List(nil)); !reflect.DeepEqual(got, []string{"secret-1", "secret-2"}) {
+		t.Errorf("unexpected List result: %v", got)
+	}
+
+	err := store.Replace([]metav1.Object{
+		newObject("secret-3", "c", map[string]string{"app": "web"}),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := store.ListKeys(); !reflect.DeepEqual(got, []string{"secret-3"}) {
+		t.Errorf("unexpected keys after Replace: %v", got)
+	}
+	if got, _ := store.IndexKeys("app", "db"); len(got) != 0 {
+		t.Errorf("stale index entries after Replace: %v", got)
+	}
+
+	if err := store.AddIndexers(Indexers{"prefix": IndexByNamePrefix("-")}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got, _ := store.IndexKeys("prefix", "c"); !reflect.DeepEqual(got, []string{"secret-3"}) {
+		t.Errorf("new index was not built from the stored objects: %v", got)
+	}
+	if err := store.AddIndexers(Indexers{"app": IndexByLabel("app")}); err == nil {
+		t.Errorf("expected an indexer conflict")
+	}
+}
+
+func TestIndexerConcurrency(t *testing.T) {
+	store := NewIndexer(Indexers{"app": IndexByLabel("app")})
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			obj := newObject(string(rune('a'+i)), "x", map[string]string{"app": "web"})
+			_ = store.Add(obj)
+			_, _ = store.ByIndex("app", "web")
+			_ = store.List(labels.Everything())
+		}(i)
+	}
+	wg.Wait()
+
+	if got, _ := store.IndexKeys("app", "web"); len(got) != 10 {
+		t.Errorf("expected 10 keys, got %v", got)
+	}
+}
@@ -0,0 +1,374 @@
+// Copyright 2020 Lingfei Kong <colin404@foxmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// Package fieldmanager implements server-side apply. It tracks which manager
+// set which field of an object in ObjectMeta.ManagedFields, merges partial
+// configurations and reports conflicts between managers.
+package fieldmanager
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/ghodss/yaml"
+
+	"github.com/HappyLadySauce/component-base/pkg/json"
+	metav1 "github.com/HappyLadySauce/component-base/pkg/meta/v1"
+	"github.com/HappyLadySauce/component-base/pkg/patch"
+	"github.com/HappyLadySauce/component-base/pkg/util/clock"
+	"github.com/HappyLadySauce/component-base/pkg/validation/field"
+)
+
+// ignoredFields are never owned by a manager: they are either populated by the
+// system or describe the type of the object rather than its state.
+var ignoredFields = []string{
+	"/apiVersion",
+	"/kind",
+	"/metadata/id",
+	"/metadata/instanceID",
+	"/metadata/createdAt",
+	"/metadata/updatedAt",
+	"/metadata/deletionTimestamp",
+	"/metadata/managedFields",
+}
+
+// Conflict is a field which is owned by another manager with a different value.
+// Conflict表示一个被其他管理者以不同的值所拥有的字段。
+type Conflict struct {
+	Manager string
+	Field   string
+}
+
+// Conflicts is returned by Apply when the configuration sets fields owned by
+// other managers and Force is not set.
+// Conflicts是Apply在字段冲突时返回的错误。
+type Conflicts []Conflict
+
+// Error implements the error interface.
+func (c Conflicts) Error() string {
+	msgs := make([]string, 0, len(c))
+	for _, conflict := range c {
+		msgs = append(msgs, fmt.Sprintf("conflict with %q: %s", conflict.Manager, conflict.Field))
+	}
+
+	return fmt.Sprintf("Apply failed with %d conflict(s): %s", len(c), strings.Join(msgs, "; "))
+}
+
+// IsConflict returns true if err is caused by conflicting field owners.
+// IsConflict用来判断错误是否为字段冲突。
+func IsConflict(err error) bool {
+	_, ok := err.(Conflicts)
+
+	return ok
+}
+
+// FieldManager updates the managed fields of objects on apply and update.
+// FieldManager是用来维护对象字段管理记录的结构体。
+type FieldManager struct {
+	clock clock.PassiveClock
+}
+
+// New creates a FieldManager which uses the real clock.
+// New用来创建FieldManager。
+func New() *FieldManager {
+	return NewWithClock(clock.RealClock{})
+}
+
+// NewWithClock creates a FieldManager which uses the given clock to timestamp entries.
+// NewWithClock用来创建使用指定时钟的FieldManager。
+func NewWithClock(c clock.PassiveClock) *FieldManager {
+	return &FieldManager{clock: c}
+}
+
+// Apply merges the partial configuration (YAML or JSON) into obj on behalf of manager.
+// Fields which manager applied before but left out of config are removed unless
+// another manager owns them too. Setting a field owned by another manager to a
+// different value is a conflict: Apply returns Conflicts and leaves obj untouched,
+// unless force is true, in which case manager takes the fields over.
+// obj must be a non-nil pointer to an object with ObjectMeta.
+// Apply用来以manager的身份合并部分配置，force为true时会强制接管冲突的字段。
+func (f *FieldManager) Apply(obj interface{}, config []byte, manager string, force bool) error {
+	if manager == "" {
+		return fmt.Errorf("field manager is required")
+	}
+
+	accessor, err := metaAccessor(obj)
+	if err != nil {
+		return err
+	}
+
+	live, err := toDocument(obj)
+	if err != nil {
+		return err
+	}
+
+	data, err := yaml.YAMLToJSON(config)
+	if err != nil {
+		return fmt.Errorf("invalid apply configuration: %w", err)
+	}
+	applied, err := patch.Decode(data)
+	if err != nil {
+		return fmt.Errorf("invalid apply configuration: %w", err)
+	}
+	if _, ok := applied.(map[string]interface{}); !ok {
+		return fmt.Errorf("apply configuration must be an object")
+	}
+
+	managed := copyManagedFields(accessor.GetManagedFields())
+	fields := leafFields(applied)
+
+	var conflicts Conflicts
+	for i := range managed {
+		entry := &managed[i]
+		if entry.Manager == manager {
+			continue
+		}
+
+		var kept []string
+		for _, owned := range entry.Fields {
+			conflicting := false
+			for _, fld := range fields {
+				liveValue, _ := lookup(live, fld)
+				appliedValue, _ := lookup(applied, fld)
+				if overlaps(owned, fld) && !patch.Equal(liveValue, appliedValue) {
+					conflicts = append(conflicts, Conflict{Manager: entry.Manager, Field: fld})
+					conflicting = true
+				}
+			}
+			if !conflicting {
+				kept = append(kept, owned)
+			}
+		}
+		entry.Fields = kept
+	}
+	if len(conflicts) > 0 && !force {
+		return dedupe(conflicts)
+	}
+
+	// Remove the fields this manager applied before and no longer sets, as long
+	// as nobody else owns them.
+	if previous, ok := accessor.GetManagedFields().Find(manager, metav1.ManagedFieldsOperationApply); ok {
+		for _, fld := range previous.Fields {
+			if containsOverlap(fields, fld) || ownedByOthers(managed, manager, fld) {
+				continue
+			}
+			live = removeField(live, fld)
+		}
+	}
+
+	live = merge(live, applied)
+	managed = setEntry(managed, metav1.ManagedFieldsEntry{
+		Manager:   manager,
+		Operation: metav1.ManagedFieldsOperationApply,
+		Time:      f.clock.Now(),
+		Fields:    fields,
+	})
+
+	return fromDocument(obj, live, managed)
+}
+
+// Update records manager as the owner of every field that differs between
+// oldObj and newObj, taking those fields away from other managers. It is meant
+// to be called for create, update and non-apply patch requests; oldObj may be
+// nil on create. The managed fields are set on newObj.
+// Update用来记录manager在更新中修改的字段。
+func (f *FieldManager) Update(oldObj, newObj interface{}, manager string) error {
+	if manager == "" {
+		return fmt.Errorf("field manager is required")
+	}
+
+	newAccessor, err := metaAccessor(newObj)
+	if err != nil {
+		return err
+	}
+
+	newDoc, err := toDocument(newObj)
+	if err != nil {
+		return err
+	}
+
+	var oldDoc interface{} = map[string]interface{}{}
+	managed := copyManagedFields(newAccessor.GetManagedFields())
+	if oldObj != nil {
+		oldAccessor, err := metaAccessor(oldObj)
+		if err != nil {
+			return err
+		}
+		if oldDoc, err = toDocument(oldObj); err != nil {
+			return err
+		}
+		managed = copyManagedFields(oldAccessor.GetManagedFields())
+	}
+
+	var changed []string
+	for _, fld := range leafFields(newDoc) {
+		oldValue, _ := lookup(oldDoc, fld)
+		newValue, _ := lookup(newDoc, fld)
+		if !patch.Equal(oldValue, newValue) {
+			changed = append(changed, fld)
+		}
+	}
+	for _, fld := range leafFields(oldDoc) {
+		if _, ok := lookup(newDoc, fld); !ok {
+			changed = append(changed, fld)
+		}
+	}
+
+	for i := range managed {
+		var kept []string
+		for _, owned := range managed[i].Fields {
+			if !containsOverlap(changed, owned) || (managed[i].Manager == manager &&
+				managed[i].Operation == metav1.ManagedFieldsOperationUpdate) {
+				kept = append(kept, owned)
+			}
+		}
+		managed[i].Fields = kept
+	}
+
+	var owned []string
+	if entry, ok := managed.Find(manager, metav1.ManagedFieldsOperationUpdate); ok {
+		for _, fld := range entry.Fields {
+			if _, ok := lookup(newDoc, fld); ok {
+				owned = append(owned, fld)
+			}
+		}
+	}
+	for _, fld := range changed {
+		if _, ok := lookup(newDoc, fld); ok && !contains(owned, fld) {
+			owned = append(owned, fld)
+		}
+	}
+	sort.Strings(owned)
+
+	managed = setEntry(managed, metav1.ManagedFieldsEntry{
+		Manager:   manager,
+		Operation: metav1.ManagedFieldsOperationUpdate,
+		Time:      f.clock.Now(),
+		Fields:    owned,
+	})
+	newAccessor.SetManagedFields(pruneEntries(managed))
+
+	return nil
+}
+
+func metaAccessor(obj interface{}) (metav1.Object, error) {
+	accessor, ok := obj.(metav1.ObjectMetaAccessor)
+	if !ok {
+		return nil, fmt.Errorf("%T does not have ObjectMeta", obj)
+	}
+
+	return accessor.GetObjectMeta(), nil
+}
+
+func toDocument(obj interface{}) (interface{}, error) {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	return patch.Decode(data)
+}
+
+// fromDocument decodes doc into a fresh value of obj's type, checks that the
+// read-only metadata was preserved and then stores it, with managed, into obj.
+func fromDocument(obj interface{}, doc interface{}, managed metav1.ManagedFields) error {
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+
+	v := reflect.ValueOf(obj)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return fmt.Errorf("apply target must be a non-nil pointer, got %T", obj)
+	}
+	out := reflect.New(v.Elem().Type())
+	if err := json.Unmarshal(data, out.Interface()); err != nil {
+		return fmt.Errorf("applied object is invalid: %w", err)
+	}
+
+	oldMeta, _ := metaAccessor(obj)
+	newMeta, err := metaAccessor(out.Interface())
+	if err != nil {
+		return err
+	}
+	if errs := metav1.ValidateObjectMetaUpdate(newMeta, oldMeta, field.NewPath("metadata")); len(errs) > 0 {
+		return errs.ToAggregate()
+	}
+	newMeta.SetManagedFields(pruneEntries(managed))
+
+	v.Elem().Set(out.Elem())
+
+	return nil
+}
+
+// setEntry replaces the entry with the same manager and operation, or appends it.
+func setEntry(managed metav1.ManagedFields, entry metav1.ManagedFieldsEntry) metav1.ManagedFields {
+	if existing, ok := managed.Find(entry.Manager, entry.Operation); ok {
+		*existing = entry
+
+		return managed
+	}
+
+	return append(managed, entry)
+}
+
+// pruneEntries drops entries which no longer own any field.
+func pruneEntries(managed metav1.ManagedFields) metav1.ManagedFields {
+	out := managed[:0]
+	for _, entry := range managed {
+		if len(entry.Fields) > 0 {
+			out = append(out, entry)
+		}
+	}
+	if len(out) == 0 {
+		return nil
+	}
+
+	return out
+}
+
+func copyManagedFields(managed metav1.ManagedFields) metav1.ManagedFields {
+	out := make(metav1.ManagedFields, len(managed))
+	for i, entry := range managed {
+		out[i] = entry
+		out[i].Fields = append([]string(nil), entry.Fields...)
+	}
+
+	return out
+}
+
+func ownedByOthers(managed metav1.ManagedFields, manager, fld string) bool {
+	for _, entry := range managed {
+		if entry.Manager != manager && containsOverlap(entry.Fields, fld) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func dedupe(conflicts Conflicts) Conflicts {
+	seen := map[Conflict]bool{}
+	out := Conflicts{}
+	for _, c := range conflicts {
+		if !seen[c] {
+			seen[c] = true
+			out = append(out, c)
+		}
+	}
+
+	return out
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+
+	return false
+}
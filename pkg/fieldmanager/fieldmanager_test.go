@@ -0,0 +1,112 @@
+// Copyright 2020 Lingfei Kong <colin404@foxmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package fieldmanager
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	metav1 "github.com/HappyLadySauce/component-base/pkg/meta/v1"
+	"github.com/HappyLadySauce/component-base/pkg/util/clock"
+)
+
+type policy struct {
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Description string            `json:"description,omitempty"`
+	Replicas    int               `json:"replicas,omitempty"`
+	Tags        map[string]string `json:"tags,omitempty"`
+}
+
+func fieldsOf(obj *policy, manager string, operation metav1.ManagedFieldsOperationType) []string {
+	entry, ok := obj.ManagedFields.Find(manager, operation)
+	if !ok {
+		return nil
+	}
+
+	return entry.Fields
+}
+
+func TestApplyAndUpdate(t *testing.T) {
+	fm := NewWithClock(clock.NewFakeClock(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)))
+	obj := &policy{ObjectMeta: metav1.ObjectMeta{ID: 1, InstanceID: "policy-1", Name: "p"}}
+
+	config := []byte("description: managed by git\nreplicas: 2\ntags:\n  env: prod\n  team: a\n")
+	if err := fm.Apply(obj, config, "gitops", false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if obj.Description != "managed by git" || obj.Replicas != 2 || obj.Tags["env"] != "prod" {
+		t.Fatalf("config was not applied: %#v", obj)
+	}
+	expected := []string{"/description", "/replicas", "/tags/env", "/tags/team"}
+	if got := fieldsOf(obj, "gitops", metav1.ManagedFieldsOperationApply); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected gitops to own %v, got %v", expected, got)
+	}
+
+	// The UI scales the policy with a regular update.
+	updated := *obj
+	updated.Replicas = 5
+	if err := fm.Update(obj, &updated, "ui"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	obj = &updated
+	if got := fieldsOf(obj, "ui", metav1.ManagedFieldsOperationUpdate); !reflect.DeepEqual(got, []string{"/replicas"}) {
+		t.Errorf("expected ui to own /replicas, got %v", got)
+	}
+	expected = []string{"/description", "/tags/env", "/tags/team"}
+	if got := fieldsOf(obj, "gitops", metav1.ManagedFieldsOperationApply); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected gitops to own %v, got %v", expected, got)
+	}
+
+	// Re-applying the same value for a field the UI owns is not a conflict.
+	if err := fm.Apply(obj, []byte(`{"replicas":5,"description":"managed by git"}`), "gitops", false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Dropping the tags from the configuration removes them from the object.
+	if len(obj.Tags) != 0 {
+		t.Errorf("expected tags to be removed, got %v", obj.Tags)
+	}
+
+	// Applying a different value conflicts with the UI.
+	before := *obj
+	err := fm.Apply(obj, []byte(`{"replicas":3,"description":"managed by git"}`), "gitops", false)
+	if !IsConflict(err) {
+		t.Fatalf("expected a conflict, got %v", err)
+	}
+	conflicts, _ := err.(Conflicts)
+	if !reflect.DeepEqual(conflicts, Conflicts{{Manager: "ui", Field: "/replicas"}}) {
+		t.Errorf("unexpected conflicts: %v", conflicts)
+	}
+	if !reflect.DeepEqual(*obj, before) {
+		t.Errorf("object was modified by a conflicting apply")
+	}
+
+	// Forcing takes the field over.
+	if err := fm.Apply(obj, []byte(`{"replicas":3,"description":"managed by git"}`), "gitops", true); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if obj.Replicas != 3 {
+		t.Errorf("expected replicas 3, got %d", obj.Replicas)
+	}
+	if _, ok := obj.ManagedFields.Find("ui", metav1.ManagedFieldsOperationUpdate); ok {
+		t.Errorf("expected ui to lose all its fields: %v", obj.ManagedFields)
+	}
+}
+
+func TestApplyReadOnlyFields(t *testing.T) {
+	fm := New()
+	obj := &policy{ObjectMeta: metav1.ObjectMeta{ID: 1, InstanceID: "policy-1", Name: "p"}}
+
+	if err := fm.Apply(obj, []byte(`{"metadata":{"instanceID":"other"}}`), "gitops", false); err == nil {
+		t.Errorf("expected an error when applying a read-only field")
+	}
+	if err := fm.Apply(obj, []byte(`[1, 2]`), "gitops", false); err == nil {
+		t.Errorf("expected an error when applying a non-object configuration")
+	}
+	if err := fm.Apply(obj, []byte(`{}`), "", false); err == nil {
+		t.Errorf("expected an error when the field manager is empty")
+	}
+}
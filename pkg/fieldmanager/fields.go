@@ -0,0 +1,136 @@
+// Copyright 2020 Lingfei Kong <colin404@foxmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package fieldmanager
+
+import (
+	"sort"
+	"strings"
+)
+
+// Fields are identified by RFC 6901 JSON pointers. Objects are walked
+// recursively while arrays and scalars are owned as a whole.
+
+var pointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+var pointerUnescaper = strings.NewReplacer("~1", "/", "~0", "~")
+
+// leafFields returns the sorted pointers of all leaf fields set in doc.
+func leafFields(doc interface{}) []string {
+	var fields []string
+	var walk func(node interface{}, prefix string)
+	walk = func(node interface{}, prefix string) {
+		if isIgnored(prefix) {
+			return
+		}
+		m, ok := node.(map[string]interface{})
+		if !ok {
+			if prefix != "" {
+				fields = append(fields, prefix)
+			}
+
+			return
+		}
+		for k, v := range m {
+			walk(v, prefix+"/"+pointerEscaper.Replace(k))
+		}
+	}
+	walk(doc, "")
+	sort.Strings(fields)
+
+	return fields
+}
+
+func isIgnored(fld string) bool {
+	for _, ignored := range ignoredFields {
+		if fld == ignored || strings.HasPrefix(fld, ignored+"/") {
+			return true
+		}
+	}
+
+	return false
+}
+
+func tokens(fld string) []string {
+	if fld == "" {
+		return nil
+	}
+	parts := strings.Split(fld[1:], "/")
+	for i := range parts {
+		parts[i] = pointerUnescaper.Replace(parts[i])
+	}
+
+	return parts
+}
+
+// lookup returns the value of fld in doc.
+func lookup(doc interface{}, fld string) (interface{}, bool) {
+	node := doc
+	for _, token := range tokens(fld) {
+		m, ok := node.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if node, ok = m[token]; !ok {
+			return nil, false
+		}
+	}
+
+	return node, true
+}
+
+// removeField deletes fld from doc if it is present.
+func removeField(doc interface{}, fld string) interface{} {
+	path := tokens(fld)
+	if len(path) == 0 {
+		return doc
+	}
+
+	node := doc
+	for _, token := range path[:len(path)-1] {
+		m, ok := node.(map[string]interface{})
+		if !ok {
+			return doc
+		}
+		node = m[token]
+	}
+	if m, ok := node.(map[string]interface{}); ok {
+		delete(m, path[len(path)-1])
+	}
+
+	return doc
+}
+
+// merge sets every field of applied onto live. Objects are merged recursively,
+// any other value replaces the live one.
+func merge(live, applied interface{}) interface{} {
+	a, ok := applied.(map[string]interface{})
+	if !ok {
+		return applied
+	}
+	l, ok := live.(map[string]interface{})
+	if !ok {
+		l = make(map[string]interface{}, len(a))
+	}
+	for k, v := range a {
+		l[k] = merge(l[k], v)
+	}
+
+	return l
+}
+
+// overlaps returns true if a and b are the same field or one contains the other.
+func overlaps(a, b string) bool {
+	return a == b || strings.HasPrefix(b, a+"/") || strings.HasPrefix(a, b+"/")
+}
+
+func containsOverlap(fields []string, fld string) bool {
+	for _, f := range fields {
+		if overlaps(f, fld) {
+			return true
+		}
+	}
+
+	return false
+}
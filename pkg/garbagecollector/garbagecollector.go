@@ -0,0 +1,238 @@
+// Copyright 2020 Lingfei Kong <colin404@foxmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// Package garbagecollector deletes objects whose owners are gone and carries out
+// foreground and orphan deletions requested through DeleteOptions.
+//
+// Owners are matched by InstanceID, which is unique across resource kinds.
+package garbagecollector
+
+import (
+	"time"
+
+	utilerrors "github.com/marmotedu/errors"
+
+	metav1 "github.com/HappyLadySauce/component-base/pkg/meta/v1"
+	"github.com/HappyLadySauce/component-base/pkg/util/clock"
+	utilruntime "github.com/HappyLadySauce/component-base/pkg/util/runtime"
+	"github.com/HappyLadySauce/component-base/pkg/util/wait"
+)
+
+// Store gives the garbage collector access to the objects it manages. List must
+// return the objects of every kind which can own or depend on each other.
+// Store是垃圾回收器访问对象的接口。
+type Store interface {
+	// List returns all the objects managed by the garbage collector.
+	List() ([]metav1.Object, error)
+	// Update persists the owner references, finalizers and deletion timestamp of obj.
+	Update(obj metav1.Object) error
+	// Delete removes obj from storage.
+	Delete(obj metav1.Object) error
+}
+
+// GarbageCollector cascades deletions from owners to their dependents.
+// GarbageCollector是用来级联删除依赖对象的垃圾回收器。
+type GarbageCollector struct {
+	store Store
+	clock clock.PassiveClock
+}
+
+// New creates a GarbageCollector working on store.
+// New用来创建GarbageCollector。
+func New(store Store) *GarbageCollector {
+	return NewWithClock(store, clock.RealClock{})
+}
+
+// NewWithClock creates a GarbageCollector which uses the given clock to set deletion timestamps.
+// NewWithClock用来创建使用指定时钟的GarbageCollector。
+func NewWithClock(store Store, c clock.PassiveClock) *GarbageCollector {
+	return &GarbageCollector{store: store, clock: c}
+}
+
+// Run collects garbage every period until stopCh is closed.
+// Run用来周期性地执行垃圾回收，直到stopCh被关闭。
+func (gc *GarbageCollector) Run(period time.Duration, stopCh <-chan struct{}) {
+	wait.Until(func() {
+		if err := gc.Sync(); err != nil {
+			utilruntime.HandleError(err)
+		}
+	}, period, stopCh)
+}
+
+// Sync runs a single garbage collection pass:
+//   - objects being deleted with the orphan finalizer release their dependents;
+//   - objects being deleted with the foreground finalizer get their dependents
+//     deleted in the foreground, and lose the finalizer when none are left;
+//     dependents which have another live owner are only released, as in the
+//     background;
+//   - objects being deleted without finalizers are removed from storage;
+//   - objects whose owners are all gone are deleted in the background.
+//
+// Cascades deeper than one level complete over successive passes.
+// Sync用来执行一次垃圾回收。
+func (gc *GarbageCollector) Sync() error {
+	objects, err := gc.store.List()
+	if err != nil {
+		return err
+	}
+
+	byID := make(map[string]metav1.Object, len(objects))
+	dependents := map[string][]metav1.Object{}
+	for _, obj := range objects {
+		byID[obj.GetInstanceID()] = obj
+		for _, ref := range obj.GetOwnerReferences() {
+			dependents[ref.InstanceID] = append(dependents[ref.InstanceID], obj)
+		}
+	}
+
+	var errs []error
+	for _, obj := range objects {
+		var err error
+		if obj.GetDeletionTimestamp() != nil {
+			err = gc.finalize(obj, dependents[obj.GetInstanceID()], byID)
+		} else {
+			err = gc.collectOrphan(obj, byID)
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return utilerrors.NewAggregate(errs)
+}
+
+// finalize processes the garbage collector finalizers of an object being deleted.
+func (gc *GarbageCollector) finalize(
+	obj metav1.Object,
+	dependents []metav1.Object,
+	byID map[string]metav1.Object,
+) error {
+	if metav1.HasFinalizer(obj, metav1.FinalizerOrphanDependents) {
+		for _, dependent := range dependents {
+			removeOwner(dependent, obj.GetInstanceID())
+			if err := gc.store.Update(dependent); err != nil {
+				return err
+			}
+		}
+		metav1.RemoveFinalizer(obj, metav1.FinalizerOrphanDependents)
+		if err := gc.store.Update(obj); err != nil {
+			return err
+		}
+	}
+
+	if metav1.HasFinalizer(obj, metav1.FinalizerDeleteDependents) {
+		waiting := false
+		for _, dependent := range dependents {
+			if hasLiveOwner(dependent, obj.GetInstanceID(), byID) {
+				removeOwner(dependent, obj.GetInstanceID())
+				if err := gc.store.Update(dependent); err != nil {
+					return err
+				}
+
+				continue
+			}
+			if err := gc.deleteForeground(dependent); err != nil {
+				return err
+			}
+			waiting = true
+		}
+		if waiting {
+			return nil
+		}
+		metav1.RemoveFinalizer(obj, metav1.FinalizerDeleteDependents)
+		if err := gc.store.Update(obj); err != nil {
+			return err
+		}
+	}
+
+	if len(obj.GetFinalizers()) == 0 {
+		return gc.store.Delete(obj)
+	}
+
+	return nil
+}
+
+// collectOrphan drops the references to owners which no longer exist and deletes
+// obj in the background when no owner is left.
+func (gc *GarbageCollector) collectOrphan(obj metav1.Object, byID map[string]metav1.Object) error {
+	refs := obj.GetOwnerReferences()
+	if len(refs) == 0 {
+		return nil
+	}
+
+	var alive []metav1.OwnerReference
+	for _, ref := range refs {
+		if _, ok := byID[ref.InstanceID]; ok {
+			alive = append(alive, ref)
+		}
+	}
+	if len(alive) == len(refs) {
+		return nil
+	}
+	if len(alive) > 0 {
+		obj.SetOwnerReferences(alive)
+
+		return gc.store.Update(obj)
+	}
+
+	return gc.deleteBackground(obj)
+}
+
+// deleteForeground marks obj for foreground deletion so its own dependents go first.
+func (gc *GarbageCollector) deleteForeground(obj metav1.Object) error {
+	changed := metav1.AddFinalizer(obj, metav1.FinalizerDeleteDependents)
+	if obj.GetDeletionTimestamp() == nil {
+		gc.markDeleted(obj)
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+
+	return gc.store.Update(obj)
+}
+
+// deleteBackground deletes obj right away unless it has finalizers, in which
+// case it is only marked for deletion.
+func (gc *GarbageCollector) deleteBackground(obj metav1.Object) error {
+	if len(obj.GetFinalizers()) == 0 {
+		return gc.store.Delete(obj)
+	}
+	if obj.GetDeletionTimestamp() != nil {
+		return nil
+	}
+	gc.markDeleted(obj)
+
+	return gc.store.Update(obj)
+}
+
+func (gc *GarbageCollector) markDeleted(obj metav1.Object) {
+	now := gc.clock.Now()
+	obj.SetDeletionTimestamp(&now)
+}
+
+// hasLiveOwner returns true if obj has an owner other than instanceID which
+// exists and is not being deleted.
+func hasLiveOwner(obj metav1.Object, instanceID string, byID map[string]metav1.Object) bool {
+	for _, ref := range obj.GetOwnerReferences() {
+		if ref.InstanceID == instanceID {
+			continue
+		}
+		if owner, ok := byID[ref.InstanceID]; ok && owner.GetDeletionTimestamp() == nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+func removeOwner(obj metav1.Object, instanceID string) {
+	var kept []metav1.OwnerReference
+	for _, ref := range obj.GetOwnerReferences() {
+		if ref.InstanceID != instanceID {
+			kept = append(kept, ref)
+		}
+	}
+	obj.SetOwnerReferences(kept)
+}
@@ -0,0 +1,237 @@
+// Copyright 2020 Lingfei Kong <colin404@foxmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package garbagecollector
+
+import (
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	utilerrors "github.com/marmotedu/errors"
+
+	metav1 "github.com/HappyLadySauce/component-base/pkg/meta/v1"
+	"github.com/HappyLadySauce/component-base/pkg/util/clock"
+)
+
+type fakeStore struct {
+	objects map[string]*metav1.ObjectMeta
+	// deleteErr is returned by Delete when set.
+	deleteErr error
+}
+
+func newFakeStore(objects ...*metav1.ObjectMeta) *fakeStore {
+	s := &fakeStore{objects: map[string]*metav1.ObjectMeta{}}
+	for _, obj := range objects {
+		s.objects[obj.InstanceID] = obj
+	}
+
+	return s
+}
+
+func (s *fakeStore) List() ([]metav1.Object, error) {
+	out := make([]metav1.Object, 0, len(s.objects))
+	for _, obj := range s.objects {
+		out = append(out, obj)
+	}
+
+	return out, nil
+}
+
+func (s *fakeStore) Update(obj metav1.Object) error { return nil }
+
+func (s *fakeStore) Delete(obj metav1.Object) error {
+	if s.deleteErr != nil {
+		return s.deleteErr
+	}
+	delete(s.objects, obj.GetInstanceID())
+
+	return nil
+}
+
+func (s *fakeStore) names() []string {
+	var names []string
+	for id := range s.objects {
+		names = append(names, id)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+func owned(id string, owners ...string) *metav1.ObjectMeta {
+	obj := &metav1.ObjectMeta{InstanceID: id}
+	for _, owner := range owners {
+		obj.OwnerReferences = append(obj.OwnerReferences, metav1.OwnerReference{Kind: "Test", InstanceID: owner})
+	}
+
+	return obj
+}
+
+func deleting(obj *metav1.ObjectMeta, finalizers ...string) *metav1.ObjectMeta {
+	now := time.Now()
+	obj.DeletionTimestamp = &now
+	obj.Finalizers = finalizers
+
+	return obj
+}
+
+func syncN(t *testing.T, gc *GarbageCollector, n int) {
+	for i := 0; i < n; i++ {
+		if err := gc.Sync(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func TestBackgroundDeletion(t *testing.T) {
+	// app-1 was deleted: its secret goes away, the policy survives through app-2.
+	store := newFakeStore(
+		owned("app-2"),
+		owned("secret-1", "app-1"),
+		owned("secret-2", "secret-1"),
+		owned("policy-1", "app-1", "app-2"),
+	)
+	gc := NewWithClock(store, clock.NewFakeClock(time.Now()))
+	syncN(t, gc, 2)
+
+	if expected := []string{"app-2", "policy-1"}; !equal(store.names(), expected) {
+		t.Errorf("expected %v, got %v", expected, store.names())
+	}
+	if refs := store.objects["policy-1"].OwnerReferences; len(refs) != 1 || refs[0].InstanceID != "app-2" {
+		t.Errorf("expected policy-1 to be owned by app-2 only, got %v", refs)
+	}
+}
+
+func TestForegroundDeletion(t *testing.T) {
+	store := newFakeStore(
+		deleting(owned("app-1"), metav1.FinalizerDeleteDependents),
+		owned("secret-1", "app-1"),
+		owned("secret-2", "secret-1"),
+	)
+	gc := New(store)
+
+	syncN(t, gc, 1)
+	if _, ok := store.objects["app-1"]; !ok {
+		t.Fatalf("owner was deleted before its dependents")
+	}
+	if store.objects["secret-1"].DeletionTimestamp == nil {
+		t.Errorf("expected secret-1 to be marked for deletion")
+	}
+
+	syncN(t, gc, 3)
+	if len(store.objects) != 0 {
+		t.Errorf("expected every object to be deleted, got %v", store.names())
+	}
+}
+
+func TestForegroundDeletionSharedDependent(t *testing.T) {
+	// policy-1 is also owned by app-2, so that deleting app-1 only releases it,
+	// as a background deletion would.
+	store := newFakeStore(
+		deleting(owned("app-1"), metav1.FinalizerDeleteDependents),
+		owned("app-2"),
+		owned("secret-1", "app-1"),
+		owned("policy-1", "app-1", "app-2"),
+	)
+	syncN(t, New(store), 3)
+
+	if expected := []string{"app-2", "policy-1"}; !equal(store.names(), expected) {
+		t.Errorf("expected %v, got %v", expected, store.names())
+	}
+	policy := store.objects["policy-1"]
+	if policy.DeletionTimestamp != nil {
+		t.Errorf("expected policy-1 not to be marked for deletion")
+	}
+	if refs := policy.OwnerReferences; len(refs) != 1 || refs[0].InstanceID != "app-2" {
+		t.Errorf("expected policy-1 to be owned by app-2 only, got %v", refs)
+	}
+}
+
+func TestForegroundDeletionOwnersBeingDeleted(t *testing.T) {
+	// Both owners of policy-1 are deleted in the foreground: neither is live, so
+	// that policy-1 is deleted rather than released by both.
+	store := newFakeStore(
+		deleting(owned("app-1"), metav1.FinalizerDeleteDependents),
+		deleting(owned("app-2"), metav1.FinalizerDeleteDependents),
+		owned("policy-1", "app-1", "app-2"),
+	)
+	syncN(t, New(store), 3)
+
+	if len(store.objects) != 0 {
+		t.Errorf("expected every object to be deleted, got %v", store.names())
+	}
+}
+
+func TestOrphanDeletion(t *testing.T) {
+	store := newFakeStore(
+		deleting(owned("app-1"), metav1.FinalizerOrphanDependents),
+		owned("secret-1", "app-1"),
+	)
+	syncN(t, New(store), 2)
+
+	if expected := []string{"secret-1"}; !equal(store.names(), expected) {
+		t.Errorf("expected %v, got %v", expected, store.names())
+	}
+	if len(store.objects["secret-1"].OwnerReferences) != 0 {
+		t.Errorf("expected secret-1 to be orphaned")
+	}
+}
+
+func TestCustomFinalizer(t *testing.T) {
+	store := newFakeStore(deleting(owned("app-1"), "example.com/cleanup"))
+	gc := New(store)
+	syncN(t, gc, 1)
+	if _, ok := store.objects["app-1"]; !ok {
+		t.Fatalf("object was deleted while it still had a finalizer")
+	}
+
+	metav1.RemoveFinalizer(store.objects["app-1"], "example.com/cleanup")
+	syncN(t, gc, 1)
+	if len(store.objects) != 0 {
+		t.Errorf("expected the object to be deleted, got %v", store.names())
+	}
+}
+
+func TestSyncErrors(t *testing.T) {
+	store := newFakeStore(owned("secret-1", "app-1"), owned("secret-2", "app-1"))
+	store.deleteErr = errors.New("storage is down")
+
+	err := New(store).Sync()
+	var agg utilerrors.Aggregate
+	if !errors.As(err, &agg) || len(agg.Errors()) != 2 {
+		t.Errorf("expected the errors of both objects, got %v", err)
+	}
+}
+
+func TestRun(t *testing.T) {
+	store := newFakeStore(owned("secret-1", "app-1"))
+	stopCh := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		New(store).Run(time.Millisecond, stopCh)
+		close(done)
+	}()
+	time.Sleep(20 * time.Millisecond)
+	close(stopCh)
+	<-done
+
+	if len(store.objects) != 0 {
+		t.Errorf("expected secret-1 to be collected, got %v", store.names())
+	}
+}
@@ -0,0 +1,198 @@
+// Copyright 2020 Lingfei Kong <colin404@foxmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package json
+
+import (
+	"bytes"
+	stdjson "encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unsafe"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/modern-go/reflect2"
+)
+
+// Backend is an implementation of JSON encoding.
+type Backend string
+
+const (
+	// BackendStd is the encoding/json package of the standard library.
+	BackendStd Backend = "std"
+	// BackendJsoniter is the github.com/json-iterator/go package.
+	BackendJsoniter Backend = "jsoniter"
+)
+
+// Options configure the behavior of an API.
+type Options struct {
+	// Backend is the implementation of the API.
+	Backend Backend
+	// EscapeHTML escapes <, > and & in marshaled strings.
+	EscapeHTML bool
+	// SortMapKeys marshals the keys of maps in sorted order. encoding/json always sorts them.
+	SortMapKeys bool
+	// UseNumber decodes numbers into an interface{} as a Number instead of a float64.
+	UseNumber bool
+	// CaseSensitive only decodes the keys matching the name of a field exactly,
+	// instead of case-insensitively. Only jsoniter supports it.
+	CaseSensitive bool
+	// FloatPrecision is the maximum number of decimals of marshaled floats. 0 marshals
+	// the shortest representation decoding to the same float. Only jsoniter supports it.
+	FloatPrecision int
+}
+
+// DefaultOptions returns the options of the backend selected at build time,
+// behaving like encoding/json.
+func DefaultOptions() Options {
+	return Options{Backend: defaultBackend, EscapeHTML: true, SortMapKeys: true}
+}
+
+// API marshals and unmarshals JSON with a backend configured by Options.
+type API interface {
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+	MarshalIndent(v interface{}, prefix, indent string) ([]byte, error)
+}
+
+// New returns the API configured by opts. An error is returned if the backend
+// is unknown or does not support the options.
+func New(opts Options) (API, error) {
+	if opts.FloatPrecision < 0 {
+		return nil, fmt.Errorf("invalid float precision %d", opts.FloatPrecision)
+	}
+
+	switch opts.Backend {
+	case BackendStd:
+		if opts.CaseSensitive {
+			return nil, fmt.Errorf("backend %s does not support case-sensitive decoding", opts.Backend)
+		}
+		if opts.FloatPrecision > 0 {
+			return nil, fmt.Errorf("backend %s does not support a float precision", opts.Backend)
+		}
+
+		return &stdAPI{opts: opts}, nil
+	case BackendJsoniter:
+		api := jsoniter.Config{
+			EscapeHTML:             opts.EscapeHTML,
+			SortMapKeys:            opts.SortMapKeys,
+			UseNumber:              opts.UseNumber,
+			CaseSensitive:          opts.CaseSensitive,
+			ValidateJsonRawMessage: true,
+		}.Froze()
+		if opts.FloatPrecision > 0 {
+			api.RegisterExtension(jsoniter.EncoderExtension{
+				reflect2.TypeOfPtr((*float32)(nil)).Elem(): &floatEncoder{precision: opts.FloatPrecision, bitSize: 32},
+				reflect2.TypeOfPtr((*float64)(nil)).Elem(): &floatEncoder{precision: opts.FloatPrecision, bitSize: 64},
+			})
+		}
+
+		return api, nil
+	default:
+		return nil, fmt.Errorf("unknown backend %q", opts.Backend)
+	}
+}
+
+// Configure replaces Marshal, Unmarshal and MarshalIndent by the API configured
+// by opts. It must be called at startup, before the package is used. NewEncoder
+// and NewDecoder keep the backend selected at build time.
+func Configure(opts Options) error {
+	api, err := New(opts)
+	if err != nil {
+		return err
+	}
+
+	Marshal = api.Marshal
+	Unmarshal = api.Unmarshal
+	MarshalIndent = api.MarshalIndent
+
+	return nil
+}
+
+// stdAPI is the API of encoding/json.
+type stdAPI struct {
+	opts Options
+}
+
+func (a *stdAPI) Marshal(v interface{}) ([]byte, error) {
+	if a.opts.EscapeHTML {
+		return stdjson.Marshal(v)
+	}
+
+	return a.encode(v, "", "")
+}
+
+func (a *stdAPI) MarshalIndent(v interface{}, prefix, indent string) ([]byte, error) {
+	if a.opts.EscapeHTML {
+		return stdjson.MarshalIndent(v, prefix, indent)
+	}
+
+	return a.encode(v, prefix, indent)
+}
+
+// encode marshals v without escaping HTML, which only an Encoder supports.
+func (a *stdAPI) encode(v interface{}, prefix, indent string) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := stdjson.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent(prefix, indent)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+func (a *stdAPI) Unmarshal(data []byte, v interface{}) error {
+	// Like Unmarshal, invalid data is rejected before v is modified.
+	if !a.opts.UseNumber || !stdjson.Valid(data) {
+		return stdjson.Unmarshal(data, v)
+	}
+
+	dec := stdjson.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	return dec.Decode(v)
+}
+
+// floatEncoder marshals floats with at most precision decimals.
+type floatEncoder struct {
+	precision int
+	bitSize   int
+}
+
+func (e *floatEncoder) value(ptr unsafe.Pointer) float64 {
+	if e.bitSize == 32 {
+		return float64(*(*float32)(ptr))
+	}
+
+	return *(*float64)(ptr)
+}
+
+func (e *floatEncoder) IsEmpty(ptr unsafe.Pointer) bool {
+	return e.value(ptr) == 0
+}
+
+func (e *floatEncoder) Encode(ptr unsafe.Pointer, stream *jsoniter.Stream) {
+	f := e.value(ptr)
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		stream.Error = fmt.Errorf("unsupported value: %v", f)
+
+		return
+	}
+	if math.Abs(f) >= 1e21 {
+		// Like encoding/json, large floats use an exponent.
+		stream.WriteRaw(strconv.FormatFloat(f, 'e', -1, e.bitSize))
+
+		return
+	}
+
+	s := strconv.FormatFloat(f, 'f', e.precision, e.bitSize)
+	if strings.Contains(s, ".") {
+		s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	}
+	stream.WriteRaw(s)
+}
@@ -0,0 +1,164 @@
+// Copyright 2020 Lingfei Kong <colin404@foxmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package json
+
+import (
+	"testing"
+)
+
+type sample struct {
+	Name   string      `json:"name"`
+	Ratio  float64     `json:"ratio"`
+	Weight float32     `json:"weight,omitempty"`
+	Value  interface{} `json:"value,omitempty"`
+}
+
+var backends = []Backend{BackendStd, BackendJsoniter}
+
+func newAPI(t *testing.T, opts Options) API {
+	api, err := New(opts)
+	if err != nil {
+		t.Fatalf("%s: unexpected error: %v", opts.Backend, err)
+	}
+
+	return api
+}
+
+func TestBackendDefaultOptions(t *testing.T) {
+	for _, backend := range backends {
+		opts := DefaultOptions()
+		opts.Backend = backend
+		api := newAPI(t, opts)
+
+		data, err := api.Marshal(sample{Name: "<a&b>", Ratio: 0.1})
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", backend, err)
+		}
+		if expected := `{"name":"\u003ca\u0026b\u003e","ratio":0.1}`; string(data) != expected {
+			t.Errorf("%s: expected %s, got %s", backend, expected, data)
+		}
+
+		var s sample
+		if err := api.Unmarshal([]byte(`{"NAME":"foo","value":1}`), &s); err != nil {
+			t.Fatalf("%s: unexpected error: %v", backend, err)
+		}
+		if s.Name != "foo" || s.Value != float64(1) {
+			t.Errorf("%s: unexpected value %#v", backend, s)
+		}
+		if err := api.Unmarshal([]byte(`{"name":`), &s); err == nil {
+			t.Errorf("%s: expected a syntax error", backend)
+		}
+	}
+}
+
+func TestBackendOptions(t *testing.T) {
+	for _, backend := range backends {
+		api := newAPI(t, Options{Backend: backend, UseNumber: true})
+
+		data, err := api.Marshal(sample{Name: "<a&b>"})
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", backend, err)
+		}
+		if expected := `{"name":"<a&b>","ratio":0}`; string(data) != expected {
+			t.Errorf("%s: expected %s, got %s", backend, expected, data)
+		}
+
+		data, err = api.MarshalIndent(sample{Name: "<a>"}, "", "  ")
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", backend, err)
+		}
+		if expected := "{\n  \"name\": \"<a>\",\n  \"ratio\": 0\n}"; string(data) != expected {
+			t.Errorf("%s: expected %s, got %s", backend, expected, data)
+		}
+
+		var s sample
+		if err := api.Unmarshal([]byte(`{"value":12345678901234567890}`), &s); err != nil {
+			t.Fatalf("%s: unexpected error: %v", backend, err)
+		}
+		if s.Value != Number("12345678901234567890") {
+			t.Errorf("%s: expected a number, got %#v", backend, s.Value)
+		}
+		if err := api.Unmarshal([]byte(`{"value":1} {}`), &s); err == nil {
+			t.Errorf("%s: expected an error on trailing data", backend)
+		}
+	}
+}
+
+func TestBackendMaps(t *testing.T) {
+	for _, backend := range backends {
+		api := newAPI(t, Options{Backend: backend, SortMapKeys: true})
+
+		data, err := api.Marshal(map[string]interface{}{"b": map[string]int{"y": 2, "x": 1}, "a": []string{"c"}})
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", backend, err)
+		}
+		if expected := `{"a":["c"],"b":{"x":1,"y":2}}`; string(data) != expected {
+			t.Errorf("%s: expected %s, got %s", backend, expected, data)
+		}
+
+		var m map[string]interface{}
+		if err := api.Unmarshal(data, &m); err != nil {
+			t.Fatalf("%s: unexpected error: %v", backend, err)
+		}
+		if b, ok := m["b"].(map[string]interface{}); !ok || b["y"] != float64(2) {
+			t.Errorf("%s: unexpected value %#v", backend, m)
+		}
+	}
+}
+
+func TestBackendJsoniterOptions(t *testing.T) {
+	api := newAPI(t, Options{Backend: BackendJsoniter, CaseSensitive: true, FloatPrecision: 2})
+
+	var s sample
+	if err := api.Unmarshal([]byte(`{"NAME":"foo","name":"bar"}`), &s); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s.Name != "bar" {
+		t.Errorf("expected the exact key to be decoded, got %q", s.Name)
+	}
+
+	data, err := api.Marshal(sample{Ratio: 3.14159, Weight: 2.5})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := `{"name":"","ratio":3.14,"weight":2.5}`; string(data) != expected {
+		t.Errorf("expected %s, got %s", expected, data)
+	}
+	if _, err := api.Marshal(sample{Weight: 1e30, Ratio: -0.001}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestBackendUnsupportedOptions(t *testing.T) {
+	for _, opts := range []Options{
+		{Backend: BackendStd, CaseSensitive: true},
+		{Backend: BackendStd, FloatPrecision: 3},
+		{Backend: BackendJsoniter, FloatPrecision: -1},
+		{Backend: "unknown"},
+	} {
+		if _, err := New(opts); err == nil {
+			t.Errorf("%+v: expected an error", opts)
+		}
+	}
+}
+
+func TestConfigure(t *testing.T) {
+	marshal, unmarshal, marshalIndent := Marshal, Unmarshal, MarshalIndent
+	defer func() {
+		Marshal, Unmarshal, MarshalIndent = marshal, unmarshal, marshalIndent
+	}()
+
+	if err := Configure(Options{Backend: BackendStd, CaseSensitive: true}); err == nil {
+		t.Errorf("expected an error")
+	}
+	if err := Configure(Options{Backend: BackendStd}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, err := Marshal("<a>")
+	if err != nil || string(data) != `"<a>"` {
+		t.Errorf("unexpected result %s, %v", data, err)
+	}
+}
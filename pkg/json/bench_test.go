@@ -0,0 +1,153 @@
+// Copyright 2020 Lingfei Kong <colin404@foxmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// The benchmarks live in an external package since pkg/meta/v1 imports pkg/json.
+package json_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/HappyLadySauce/component-base/pkg/json"
+	metav1 "github.com/HappyLadySauce/component-base/pkg/meta/v1"
+)
+
+// benchObject is a typical API object.
+type benchObject struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Username string   `json:"username"`
+	Expires  int64    `json:"expires"`
+	Ratio    float64  `json:"ratio"`
+	Emails   []string `json:"emails,omitempty"`
+}
+
+type benchObjectList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:",inline"`
+
+	Items []benchObject `json:"items"`
+}
+
+func newBenchObject(i int) benchObject {
+	now := time.Date(2020, 8, 1, 10, 0, 0, 0, time.UTC)
+
+	return benchObject{
+		TypeMeta: metav1.TypeMeta{Kind: "Secret", APIVersion: "iam.example.com/v1"},
+		ObjectMeta: metav1.ObjectMeta{
+			ID:         uint64(i),
+			InstanceID: fmt.Sprintf("secret-%08d", i),
+			Name:       fmt.Sprintf("secret-%d", i),
+			Labels:     map[string]string{"app": "iam", "tier": "backend"},
+			Extend:     metav1.Extend{"description": "a secret <of> the user", "weight": 0.25},
+			CreatedAt:  now,
+			UpdatedAt:  now,
+		},
+		Username: "colin",
+		Expires:  now.Unix(),
+		Ratio:    3.14159265,
+		Emails:   []string{"colin@example.com", "admin@example.com"},
+	}
+}
+
+func newBenchObjectList(n int) *benchObjectList {
+	list := &benchObjectList{
+		TypeMeta: metav1.TypeMeta{Kind: "SecretList", APIVersion: "iam.example.com/v1"},
+		ListMeta: metav1.ListMeta{TotalCount: int64(n)},
+	}
+	for i := 0; i < n; i++ {
+		list.Items = append(list.Items, newBenchObject(i))
+	}
+
+	return list
+}
+
+type benchAPI struct {
+	name string
+	api  json.API
+}
+
+// benchAPIs returns the configurations compared by the benchmarks, in a stable
+// order so that runs can be compared.
+func benchAPIs(b *testing.B) []benchAPI {
+	var apis []benchAPI
+	for _, c := range []struct {
+		name string
+		opts json.Options
+	}{
+		{"std", json.Options{Backend: json.BackendStd, EscapeHTML: true, SortMapKeys: true}},
+		{"std-fast", json.Options{Backend: json.BackendStd}},
+		{"jsoniter", json.Options{Backend: json.BackendJsoniter, EscapeHTML: true, SortMapKeys: true}},
+		{"jsoniter-fast", json.Options{Backend: json.BackendJsoniter}},
+		{"jsoniter-float6", json.Options{Backend: json.BackendJsoniter, FloatPrecision: 6}},
+	} {
+		api, err := json.New(c.opts)
+		if err != nil {
+			b.Fatalf("%s: unexpected error: %v", c.name, err)
+		}
+		apis = append(apis, benchAPI{name: c.name, api: api})
+	}
+
+	return apis
+}
+
+func BenchmarkMarshal(b *testing.B) {
+	for _, n := range []int{1, 100} {
+		list := newBenchObjectList(n)
+		for _, c := range benchAPIs(b) {
+			api := c.api
+			b.Run(fmt.Sprintf("%s/%d", c.name, n), func(b *testing.B) {
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					if _, err := api.Marshal(list); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkUnmarshal(b *testing.B) {
+	for _, n := range []int{1, 100} {
+		data, err := json.Marshal(newBenchObjectList(n))
+		if err != nil {
+			b.Fatal(err)
+		}
+		for _, c := range benchAPIs(b) {
+			api := c.api
+			b.Run(fmt.Sprintf("%s/%d", c.name, n), func(b *testing.B) {
+				b.ReportAllocs()
+				b.SetBytes(int64(len(data)))
+				for i := 0; i < b.N; i++ {
+					var list benchObjectList
+					if err := api.Unmarshal(data, &list); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkUnmarshalObjectMeta(b *testing.B) {
+	data, err := json.Marshal(newBenchObject(1).ObjectMeta)
+	if err != nil {
+		b.Fatal(err)
+	}
+	for _, c := range benchAPIs(b) {
+		api := c.api
+		b.Run(c.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				var meta metav1.ObjectMeta
+				if err := api.Unmarshal(data, &meta); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -11,6 +11,12 @@ import "encoding/json"
 // RawMessage is exported by component-base/pkg/json package.
 type RawMessage = json.RawMessage
 
+// Number is exported by component-base/pkg/json package.
+type Number = json.Number
+
+// defaultBackend is the backend selected at build time.
+const defaultBackend = BackendStd
+
 var (
 	// Marshal is exported by component-base/pkg/json package.
 	Marshal = json.Marshal
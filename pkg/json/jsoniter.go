@@ -6,11 +6,22 @@
 
 package json
 
-import jsoniter "github.com/json-iterator/go"
+import (
+	stdjson "encoding/json"
+
+	jsoniter "github.com/json-iterator/go"
+)
 
 // RawMessage is exported by component-base/pkg/json package.
 type RawMessage = jsoniter.RawMessage
 
+// Number is exported by component-base/pkg/json package.
+// jsoniter decodes numbers into encoding/json.Number when UseNumber is set.
+type Number = stdjson.Number
+
+// defaultBackend is the backend selected at build time.
+const defaultBackend = BackendJsoniter
+
 var (
 	json = jsoniter.ConfigCompatibleWithStandardLibrary
 	// Marshal is exported by component-base/pkg/json package.
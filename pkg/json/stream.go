@@ -0,0 +1,329 @@
+// Copyright 2020 Lingfei Kong <colin404@foxmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package json
+
+import (
+	"bufio"
+	stdjson "encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Token is a token of a JSON stream: a Delim, a bool, a string, a Number or nil.
+type Token = stdjson.Token
+
+// Delim is a JSON array or object delimiter: one of [ ] { or }.
+type Delim = stdjson.Delim
+
+// scope is an array or an object being written.
+type scope struct {
+	delim Delim
+	// n is the number of elements, or fields, of the scope.
+	n int
+	// key tells if a field name was written, waiting for its value.
+	key bool
+}
+
+// StreamWriter writes a JSON document to an io.Writer one token at a time, so
+// that large arrays are written without holding them in memory. Values are
+// marshaled with Marshal. Several top-level values are separated by newlines.
+// The first error is sticky: later calls return it without writing.
+type StreamWriter struct {
+	w      *bufio.Writer
+	scopes []scope
+	// values is the number of top-level values.
+	values int
+	err    error
+}
+
+// NewStreamWriter returns a StreamWriter writing to w. Flush must be called
+// once the document is written.
+func NewStreamWriter(w io.Writer) *StreamWriter {
+	return &StreamWriter{w: bufio.NewWriter(w)}
+}
+
+// BeginObject starts an object value.
+func (s *StreamWriter) BeginObject() error {
+	return s.begin('{')
+}
+
+// EndObject ends the current object.
+func (s *StreamWriter) EndObject() error {
+	return s.end('}')
+}
+
+// BeginArray starts an array value.
+func (s *StreamWriter) BeginArray() error {
+	return s.begin('[')
+}
+
+// EndArray ends the current array.
+func (s *StreamWriter) EndArray() error {
+	return s.end(']')
+}
+
+// WriteField writes the name of the next field of the current object, which
+// must be followed by its value.
+func (s *StreamWriter) WriteField(name string) error {
+	if s.err != nil {
+		return s.err
+	}
+
+	top := s.top()
+	if top == nil || top.delim != '{' || top.key {
+		return s.fail(fmt.Errorf("unexpected field %q", name))
+	}
+	data, err := stdjson.Marshal(name)
+	if err != nil {
+		return s.fail(err)
+	}
+	if top.n > 0 {
+		s.write([]byte{','})
+	}
+	top.n++
+	top.key = true
+	s.write(data)
+
+	return s.write([]byte{':'})
+}
+
+// WriteValue writes v, marshaled with Marshal, as the next value.
+func (s *StreamWriter) WriteValue(v interface{}) error {
+	if s.err != nil {
+		return s.err
+	}
+
+	data, err := Marshal(v)
+	if err != nil {
+		return s.fail(err)
+	}
+
+	return s.WriteRaw(data)
+}
+
+// WriteRaw writes data, which must be a valid JSON value, as the next value.
+func (s *StreamWriter) WriteRaw(data []byte) error {
+	if err := s.beforeValue(); err != nil {
+		return err
+	}
+
+	return s.write(data)
+}
+
+// Flush writes the buffered data to the underlying io.Writer.
+func (s *StreamWriter) Flush() error {
+	if s.err != nil {
+		return s.err
+	}
+	if err := s.w.Flush(); err != nil {
+		return s.fail(err)
+	}
+
+	return nil
+}
+
+// Close checks that all the arrays and objects are ended and flushes the
+// buffered data. It does not close the underlying io.Writer.
+func (s *StreamWriter) Close() error {
+	if s.err == nil && len(s.scopes) > 0 {
+		return s.fail(fmt.Errorf("unexpected end of document in %q", s.top().delim))
+	}
+
+	return s.Flush()
+}
+
+func (s *StreamWriter) begin(delim Delim) error {
+	if err := s.beforeValue(); err != nil {
+		return err
+	}
+	s.scopes = append(s.scopes, scope{delim: delim})
+
+	return s.write([]byte{byte(delim)})
+}
+
+func (s *StreamWriter) end(delim Delim) error {
+	if s.err != nil {
+		return s.err
+	}
+
+	top := s.top()
+	if top == nil || top.key || closingDelim(top.delim) != delim {
+		return s.fail(fmt.Errorf("unexpected %q", delim))
+	}
+	s.scopes = s.scopes[:len(s.scopes)-1]
+
+	return s.write([]byte{byte(delim)})
+}
+
+// beforeValue writes the separator of the next value, and checks that a value
+// is expected.
+func (s *StreamWriter) beforeValue() error {
+	if s.err != nil {
+		return s.err
+	}
+
+	top := s.top()
+	switch {
+	case top == nil:
+		if s.values > 0 {
+			s.write([]byte{'\n'})
+		}
+		s.values++
+	case top.delim == '[':
+		if top.n > 0 {
+			s.write([]byte{','})
+		}
+		top.n++
+	case !top.key:
+		return s.fail(errors.New("missing field name before value"))
+	default:
+		top.key = false
+	}
+
+	return s.err
+}
+
+func (s *StreamWriter) top() *scope {
+	if len(s.scopes) == 0 {
+		return nil
+	}
+
+	return &s.scopes[len(s.scopes)-1]
+}
+
+func (s *StreamWriter) write(data []byte) error {
+	if s.err != nil {
+		return s.err
+	}
+	if _, err := s.w.Write(data); err != nil {
+		return s.fail(err)
+	}
+
+	return nil
+}
+
+func (s *StreamWriter) fail(err error) error {
+	s.err = err
+
+	return err
+}
+
+func closingDelim(delim Delim) Delim {
+	if delim == '{' {
+		return '}'
+	}
+
+	return ']'
+}
+
+// StreamReader reads a JSON stream from an io.Reader one token at a time. It
+// can skip whole values and decode one value at a time, such as the elements
+// of a large array, so that only the current value is held in memory. Numbers
+// are read as Number.
+type StreamReader struct {
+	dec *stdjson.Decoder
+}
+
+// NewStreamReader returns a StreamReader reading from r.
+func NewStreamReader(r io.Reader) *StreamReader {
+	dec := stdjson.NewDecoder(r)
+	dec.UseNumber()
+
+	return &StreamReader{dec: dec}
+}
+
+// Token returns the next token of the stream. Field names are returned as
+// strings; commas and colons are skipped. io.EOF is returned at the end of the stream.
+func (r *StreamReader) Token() (Token, error) {
+	return r.dec.Token()
+}
+
+// More reports whether the current array or object has another element, or
+// whether the stream has another top-level value.
+func (r *StreamReader) More() bool {
+	return r.dec.More()
+}
+
+// BeginObject reads the start of an object.
+func (r *StreamReader) BeginObject() error {
+	return r.expectDelim('{')
+}
+
+// EndObject reads the end of the current object.
+func (r *StreamReader) EndObject() error {
+	return r.expectDelim('}')
+}
+
+// BeginArray reads the start of an array.
+func (r *StreamReader) BeginArray() error {
+	return r.expectDelim('[')
+}
+
+// EndArray reads the end of the current array.
+func (r *StreamReader) EndArray() error {
+	return r.expectDelim(']')
+}
+
+// ReadField reads the name of the next field of the current object.
+func (r *StreamReader) ReadField() (string, error) {
+	tok, err := r.dec.Token()
+	if err != nil {
+		return "", err
+	}
+	name, ok := tok.(string)
+	if !ok {
+		return "", fmt.Errorf("expected a field name, got %v", tok)
+	}
+
+	return name, nil
+}
+
+// Decode decodes the next value of the stream into v with Unmarshal.
+func (r *StreamReader) Decode(v interface{}) error {
+	var raw stdjson.RawMessage
+	if err := r.dec.Decode(&raw); err != nil {
+		return err
+	}
+
+	return Unmarshal(raw, v)
+}
+
+// Skip skips the next value of the stream, with all its nested values, without
+// decoding it.
+func (r *StreamReader) Skip() error {
+	depth := 0
+	for {
+		tok, err := r.dec.Token()
+		if err != nil {
+			return err
+		}
+		if delim, ok := tok.(Delim); ok {
+			switch {
+			case delim == '{' || delim == '[':
+				depth++
+			case depth == 0:
+				return fmt.Errorf("expected a value, got %q", delim)
+			default:
+				depth--
+			}
+		}
+		if depth == 0 {
+			return nil
+		}
+	}
+}
+
+func (r *StreamReader) expectDelim(delim Delim) error {
+	tok, err := r.dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok != delim {
+		return fmt.Errorf("expected %q, got %v", delim, tok)
+	}
+
+	return nil
+}
@@ -0,0 +1,219 @@
+// Copyright 2020 Lingfei Kong <colin404@foxmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package json
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type record struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+func TestStreamWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewStreamWriter(&buf)
+
+	steps := []func() error{
+		w.BeginObject,
+		func() error { return w.WriteField("kind") },
+		func() error { return w.WriteValue("RecordList") },
+		func() error { return w.WriteField("items") },
+		w.BeginArray,
+		func() error { return w.WriteValue(record{ID: 1, Name: "a"}) },
+		func() error { return w.WriteValue(record{ID: 2, Name: "b"}) },
+		w.BeginArray,
+		w.EndArray,
+		w.EndArray,
+		func() error { return w.WriteField("meta") },
+		w.BeginObject,
+		w.EndObject,
+		w.EndObject,
+		func() error { return w.WriteRaw([]byte("true")) },
+		w.Close,
+	}
+	for i, step := range steps {
+		if err := step(); err != nil {
+			t.Fatalf("step %d: unexpected error: %v", i, err)
+		}
+	}
+
+	expected := `{"kind":"RecordList","items":[{"id":1,"name":"a"},{"id":2,"name":"b"},[]],"meta":{}}` + "\ntrue"
+	if buf.String() != expected {
+		t.Errorf("expected %s, got %s", expected, buf.String())
+	}
+}
+
+func TestStreamWriterErrors(t *testing.T) {
+	testCases := []struct {
+		name  string
+		steps func(w *StreamWriter) error
+	}{
+		{"value without field", func(w *StreamWriter) error {
+			w.BeginObject()
+
+			return w.WriteValue(1)
+		}},
+		{"field in array", func(w *StreamWriter) error {
+			w.BeginArray()
+
+			return w.WriteField("a")
+		}},
+		{"two fields", func(w *StreamWriter) error {
+			w.BeginObject()
+			w.WriteField("a")
+
+			return w.WriteField("b")
+		}},
+		{"mismatched end", func(w *StreamWriter) error {
+			w.BeginObject()
+
+			return w.EndArray()
+		}},
+		{"end without begin", func(w *StreamWriter) error {
+			return w.EndObject()
+		}},
+		{"unmarshalable value", func(w *StreamWriter) error {
+			return w.WriteValue(make(chan int))
+		}},
+		{"unended array", func(w *StreamWriter) error {
+			w.BeginArray()
+
+			return w.Close()
+		}},
+	}
+
+	for _, tc := range testCases {
+		w := NewStreamWriter(io.Discard)
+		err := tc.steps(w)
+		if err == nil {
+			t.Errorf("%s: expected an error", tc.name)
+
+			continue
+		}
+		if sticky := w.WriteRaw([]byte("1")); sticky != err {
+			t.Errorf("%s: expected the error to be sticky, got %v", tc.name, sticky)
+		}
+	}
+}
+
+func TestStreamReader(t *testing.T) {
+	data := `{"kind":"RecordList","meta":{"labels":{"a":[1,{"b":2}]}},"items":[{"id":1,"name":"a"},{"id":2,"name":"b"}]}`
+	r := NewStreamReader(strings.NewReader(data))
+
+	if err := r.BeginObject(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var records []record
+	for r.More() {
+		name, err := r.ReadField()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if name != "items" {
+			if err := r.Skip(); err != nil {
+				t.Fatalf("%s: unexpected error: %v", name, err)
+			}
+
+			continue
+		}
+
+		if err := r.BeginArray(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for r.More() {
+			var rec record
+			if err := r.Decode(&rec); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			records = append(records, rec)
+		}
+		if err := r.EndArray(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if err := r.EndObject(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := r.Token(); !errors.Is(err, io.EOF) {
+		t.Errorf("expected EOF, got %v", err)
+	}
+
+	expected := []record{{ID: 1, Name: "a"}, {ID: 2, Name: "b"}}
+	if !reflect.DeepEqual(records, expected) {
+		t.Errorf("expected %v, got %v", expected, records)
+	}
+}
+
+func TestStreamReaderErrors(t *testing.T) {
+	r := NewStreamReader(strings.NewReader(`[1]`))
+	if err := r.BeginObject(); err == nil {
+		t.Errorf("expected an error on a mismatched delimiter")
+	}
+
+	r = NewStreamReader(strings.NewReader(`[]`))
+	r.BeginArray()
+	if err := r.Skip(); err == nil {
+		t.Errorf("expected an error when skipping the end of an array")
+	}
+
+	r = NewStreamReader(strings.NewReader(`[1,`))
+	r.BeginArray()
+	r.Skip()
+	if err := r.Skip(); err == nil {
+		t.Errorf("expected an error on a truncated stream")
+	}
+
+	r = NewStreamReader(strings.NewReader(`{"id":1}`))
+	r.BeginObject()
+	r.ReadField()
+	if _, err := r.ReadField(); err == nil {
+		t.Errorf("expected an error when reading a value as a field name")
+	}
+}
+
+// TestStreamRoundTrip streams records from a writer to a reader through a pipe,
+// as an export would.
+func TestStreamRoundTrip(t *testing.T) {
+	const n = 1000
+
+	pr, pw := io.Pipe()
+	go func() {
+		w := NewStreamWriter(pw)
+		w.BeginArray()
+		for i := 0; i < n; i++ {
+			w.WriteValue(record{ID: i, Name: "record"})
+		}
+		w.EndArray()
+		pw.CloseWithError(w.Close())
+	}()
+
+	r := NewStreamReader(pr)
+	if err := r.BeginArray(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	count := 0
+	for ; r.More(); count++ {
+		var rec record
+		if err := r.Decode(&rec); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if rec.ID != count {
+			t.Fatalf("expected record %d, got %d", count, rec.ID)
+		}
+	}
+	if err := r.EndArray(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if count != n {
+		t.Errorf("expected %d records, got %d", n, count)
+	}
+}
@@ -0,0 +1,351 @@
+// Copyright 2020 Lingfei Kong <colin404@foxmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package json
+
+import (
+	"bytes"
+	stdjson "encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/HappyLadySauce/component-base/pkg/validation/field"
+)
+
+// StrictMode tells how the issues found by a strict decoding are surfaced.
+type StrictMode int
+
+const (
+	// StrictModeWarn returns the issues along with the decoded value, without failing.
+	StrictModeWarn StrictMode = iota
+	// StrictModeError fails the decoding with a StrictDecodingError when issues are found.
+	StrictModeError
+)
+
+// StrictDecodingError is returned in StrictModeError when the decoded data holds
+// unknown fields, duplicate keys or case-mismatched keys.
+type StrictDecodingError struct {
+	Errors field.ErrorList
+}
+
+func (e *StrictDecodingError) Error() string {
+	return "strict decoding error: " + e.Errors.ToAggregate().Error()
+}
+
+// IsStrictDecodingError returns true if err is a StrictDecodingError.
+func IsStrictDecodingError(err error) bool {
+	var e *StrictDecodingError
+
+	return errors.As(err, &e)
+}
+
+// UnmarshalStrict decodes data into v, like Unmarshal, and reports the fields of
+// data which are not decoded as written: unknown fields, duplicate keys and keys
+// which only match a field case-insensitively. Each issue holds the JSON path of
+// the key. In StrictModeError, a StrictDecodingError is returned when there are
+// issues; v is decoded in both modes.
+func UnmarshalStrict(data []byte, v interface{}, mode StrictMode) (field.ErrorList, error) {
+	if err := Unmarshal(data, v); err != nil {
+		return nil, err
+	}
+
+	errs, err := StrictErrors(data, v)
+	if err != nil {
+		return nil, err
+	}
+	if len(errs) > 0 && mode == StrictModeError {
+		return errs, &StrictDecodingError{Errors: errs}
+	}
+
+	return errs, nil
+}
+
+// StrictErrors checks data against the type of v, without decoding it, and
+// returns the unknown fields, duplicate keys and case-mismatched keys of data.
+func StrictErrors(data []byte, v interface{}) (field.ErrorList, error) {
+	// The check only walks the tokens of data, which is the same with all
+	// backends, so the standard library is used.
+	c := &strictChecker{dec: stdjson.NewDecoder(bytes.NewReader(data))}
+	c.dec.UseNumber()
+	if err := c.value(reflect.TypeOf(v), nil); err != nil {
+		return nil, err
+	}
+
+	return c.errs, nil
+}
+
+var unmarshalerType = reflect.TypeOf((*stdjson.Unmarshaler)(nil)).Elem()
+
+type strictChecker struct {
+	dec  *stdjson.Decoder
+	errs field.ErrorList
+}
+
+// value checks the next value of the stream, decoded into a value of type t. A
+// nil t is an untyped value, for which only duplicate keys are reported.
+func (c *strictChecker) value(t reflect.Type, path *field.Path) error {
+	tok, err := c.dec.Token()
+	if err != nil {
+		return err
+	}
+
+	switch tok {
+	case stdjson.Delim('{'):
+		return c.object(checkedType(t), path)
+	case stdjson.Delim('['):
+		return c.array(checkedType(t), path)
+	default:
+		return nil
+	}
+}
+
+func (c *strictChecker) object(t reflect.Type, path *field.Path) error {
+	var fields *structFields
+	if t != nil && t.Kind() == reflect.Struct {
+		fields = cachedFields(t)
+	}
+
+	seen := make(map[string]bool)
+	for c.dec.More() {
+		tok, err := c.dec.Token()
+		if err != nil {
+			return err
+		}
+		key, _ := tok.(string)
+
+		var elem reflect.Type
+		childPath := path.Key(key)
+		seenKey := key
+		switch {
+		case fields != nil:
+			childPath = path.Child(key)
+			f, exact := fields.lookup(key)
+			switch {
+			case f == nil:
+				c.errs = append(c.errs, field.NotSupported(childPath, key, fields.names))
+			case !exact:
+				c.errs = append(c.errs, field.Invalid(childPath, key, fmt.Sprintf("must match the case of %q", f.name)))
+			}
+			if f != nil {
+				elem, seenKey = f.typ, f.name
+			}
+		case t != nil && t.Kind() == reflect.Map:
+			elem = t.Elem()
+		}
+
+		if seen[seenKey] {
+			c.errs = append(c.errs, field.Duplicate(childPath, key))
+		}
+		seen[seenKey] = true
+
+		if err := c.value(elem, childPath); err != nil {
+			return err
+		}
+	}
+
+	_, err := c.dec.Token()
+
+	return err
+}
+
+func (c *strictChecker) array(t reflect.Type, path *field.Path) error {
+	var elem reflect.Type
+	if t != nil && (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) {
+		elem = t.Elem()
+	}
+
+	for i := 0; c.dec.More(); i++ {
+		if err := c.value(elem, path.Index(i)); err != nil {
+			return err
+		}
+	}
+
+	_, err := c.dec.Token()
+
+	return err
+}
+
+// checkedType returns the type whose fields are checked for a value of type t,
+// or nil if the value is decoded without following the fields of a type.
+func checkedType(t reflect.Type) reflect.Type {
+	for t != nil {
+		if t.Implements(unmarshalerType) || reflect.PtrTo(t).Implements(unmarshalerType) {
+			return nil
+		}
+		switch t.Kind() {
+		case reflect.Ptr:
+			t = t.Elem()
+		case reflect.Interface:
+			return nil
+		default:
+			return t
+		}
+	}
+
+	return nil
+}
+
+// structField is a field of a struct as decoded from JSON.
+type structField struct {
+	name string
+	typ  reflect.Type
+	// index is the index sequence of the field, as used by reflect.Type.FieldByIndex.
+	index []int
+}
+
+// structFields are the fields of a struct, by JSON name.
+type structFields struct {
+	byName map[string]*structField
+	// names are the sorted JSON names of the fields.
+	names []string
+}
+
+// lookup returns the field key decodes into, and whether key matches its name
+// exactly. Like encoding/json, the names are otherwise matched case-insensitively.
+func (f *structFields) lookup(key string) (*structField, bool) {
+	if sf, ok := f.byName[key]; ok {
+		return sf, true
+	}
+	for _, name := range f.names {
+		if strings.EqualFold(name, key) {
+			return f.byName[name], false
+		}
+	}
+
+	return nil, false
+}
+
+var fieldCache sync.Map // map[reflect.Type]*structFields
+
+func cachedFields(t reflect.Type) *structFields {
+	if f, ok := fieldCache.Load(t); ok {
+		return f.(*structFields)
+	}
+
+	var candidates []fieldCandidate
+	collectFields(t, nil, map[reflect.Type]bool{t: true}, &candidates)
+	fields := dominantFields(candidates)
+
+	f, _ := fieldCache.LoadOrStore(t, fields)
+
+	return f.(*structFields)
+}
+
+// Fields returns the fields of the struct t encoded by encoding/json, as index
+// sequences for reflect.Type.FieldByIndex, by JSON name. The fields of embedded
+// structs without a JSON name are promoted, unless a shallower field of the same
+// name hides them, wherever it is declared.
+func Fields(t reflect.Type) map[string][]int {
+	fields := cachedFields(t)
+
+	indexes := make(map[string][]int, len(fields.byName))
+	for name, f := range fields.byName {
+		indexes[name] = append([]int(nil), f.index...)
+	}
+
+	return indexes
+}
+
+// fieldCandidate is a field of a struct or of its embedded structs, which is
+// decoded unless another field of the same name dominates it.
+type fieldCandidate struct {
+	structField
+	depth  int
+	tagged bool
+}
+
+// collectFields appends the fields of t to candidates, following the rules of
+// encoding/json: the fields of embedded structs without a JSON name are
+// promoted one level deeper. index is the index sequence of t in the walked
+// struct. visiting holds the embedded structs being walked, which are not walked
+// again when a struct embeds itself.
+func collectFields(t reflect.Type, index []int, visiting map[reflect.Type]bool, candidates *[]fieldCandidate) {
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		fieldIndex := append(append([]int(nil), index...), i)
+		tag := sf.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+
+		if sf.Anonymous && name == "" {
+			ft := sf.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				if !visiting[ft] {
+					visiting[ft] = true
+					collectFields(ft, fieldIndex, visiting, candidates)
+					delete(visiting, ft)
+				}
+
+				continue
+			}
+		}
+		if sf.PkgPath != "" {
+			// Unexported fields are not decoded.
+			continue
+		}
+		tagged := name != ""
+		if !tagged {
+			name = sf.Name
+		}
+		*candidates = append(*candidates, fieldCandidate{
+			structField: structField{name: name, typ: sf.Type, index: fieldIndex},
+			depth:       len(index),
+			tagged:      tagged,
+		})
+	}
+}
+
+// dominantFields returns the fields decoded among candidates. Like encoding/json,
+// the shallowest fields of a name hide the deeper ones; among several shallowest
+// fields, a single tagged one wins, otherwise the name is ambiguous and dropped.
+func dominantFields(candidates []fieldCandidate) *structFields {
+	byName := make(map[string][]fieldCandidate)
+	for _, c := range candidates {
+		byName[c.name] = append(byName[c.name], c)
+	}
+
+	fields := &structFields{byName: make(map[string]*structField)}
+	for name, cs := range byName {
+		minDepth := cs[0].depth
+		for _, c := range cs[1:] {
+			if c.depth < minDepth {
+				minDepth = c.depth
+			}
+		}
+
+		var shallowest, tagged []fieldCandidate
+		for _, c := range cs {
+			if c.depth != minDepth {
+				continue
+			}
+			shallowest = append(shallowest, c)
+			if c.tagged {
+				tagged = append(tagged, c)
+			}
+		}
+
+		switch {
+		case len(shallowest) == 1:
+			fields.byName[name] = &shallowest[0].structField
+		case len(tagged) == 1:
+			fields.byName[name] = &tagged[0].structField
+		}
+	}
+
+	for name := range fields.byName {
+		fields.names = append(fields.names, name)
+	}
+	sort.Strings(fields.names)
+
+	return fields
+}
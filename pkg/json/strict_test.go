@@ -0,0 +1,216 @@
+// Copyright 2020 Lingfei Kong <colin404@foxmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package json
+
+import (
+	stdjson "encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/HappyLadySauce/component-base/pkg/validation/field"
+)
+
+type selector struct {
+	MatchLabels map[string]string `json:"matchLabels,omitempty"`
+}
+
+type meta struct {
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"createdAt,omitempty"`
+}
+
+type container struct {
+	Image string `json:"image"`
+}
+
+type config struct {
+	meta `json:",inline"`
+
+	LabelSelector *selector   `json:"labelSelector,omitempty"`
+	Containers    []container `json:"containers,omitempty"`
+	Extend        interface{} `json:"extend,omitempty"`
+	Raw           RawMessage  `json:"raw,omitempty"`
+	Replicas      int         `json:"replicas"`
+	Ignored       string      `json:"-"`
+}
+
+func TestStrictErrors(t *testing.T) {
+	testCases := []struct {
+		name     string
+		data     string
+		expected []string
+	}{
+		{
+			"valid",
+			`{"name":"foo","createdAt":"2020-08-01T10:00:00Z","labelSelector":{"matchLabels":{"app":"iam"}}}`,
+			nil,
+		},
+		{"unknown field", `{"labelSelecter":{"matchLabels":{}}}`, []string{"labelSelecter"}},
+		{"nested unknown field", `{"labelSelector":{"matchLabel":{}}}`, []string{"labelSelector.matchLabel"}},
+		{"unknown field in list", `{"containers":[{"image":"a"},{"imag":"b"}]}`, []string{"containers[1].imag"}},
+		{"ignored field", `{"Ignored":"x"}`, []string{"Ignored"}},
+		{"duplicate field", `{"name":"foo","replicas":1,"name":"bar"}`, []string{"name"}},
+		{"case-mismatched field", `{"Replicas":1}`, []string{"Replicas"}},
+		{"case-insensitive duplicate", `{"replicas":1,"REPLICAS":2}`, []string{"REPLICAS", "REPLICAS"}},
+		{"duplicate map key", `{"labelSelector":{"matchLabels":{"app":"a","app":"b"}}}`, []string{"labelSelector.matchLabels[app]"}},
+		{"untyped values", `{"extend":{"a":{"b":1}},"raw":{"c":[{"d":1}]}}`, nil},
+		{"untyped duplicate", `{"extend":{"a":1,"a":2}}`, []string{"extend[a]"}},
+	}
+
+	for _, tc := range testCases {
+		errs, err := StrictErrors([]byte(tc.data), &config{})
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tc.name, err)
+
+			continue
+		}
+		var fields []string
+		for _, e := range errs {
+			fields = append(fields, e.Field)
+		}
+		if !reflect.DeepEqual(fields, tc.expected) {
+			t.Errorf("%s: expected issues on %v, got %v", tc.name, tc.expected, errs)
+		}
+	}
+}
+
+type taggedOwner struct {
+	Name string `json:"Owner"`
+}
+
+type untaggedOwner struct {
+	Owner string
+}
+
+type otherOwner struct {
+	Owner string
+}
+
+type recursive struct {
+	*recursive
+
+	Depth int `json:"depth"`
+}
+
+// ambiguous embeds fields of the same name at the same depth.
+type ambiguous struct {
+	untaggedOwner
+	otherOwner
+	recursive
+}
+
+// tagDominated embeds a tagged and an untagged field of the same name at the same depth.
+type tagDominated struct {
+	taggedOwner
+	untaggedOwner `json:",inline"`
+}
+
+func TestStrictErrorsFieldDominance(t *testing.T) {
+	testCases := []struct {
+		name     string
+		v        interface{}
+		data     string
+		expected []string
+	}{
+		{"ambiguous fields are dropped", &ambiguous{}, `{"Owner":"colin","depth":1}`, []string{"Owner"}},
+		{"tagged field wins", &tagDominated{}, `{"Owner":"colin"}`, nil},
+		{"tagged field wins case-insensitively", &tagDominated{}, `{"owner":"colin"}`, []string{"owner"}},
+	}
+
+	for _, tc := range testCases {
+		errs, err := StrictErrors([]byte(tc.data), tc.v)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tc.name, err)
+
+			continue
+		}
+		var fields []string
+		for _, e := range errs {
+			fields = append(fields, e.Field)
+		}
+		if !reflect.DeepEqual(fields, tc.expected) {
+			t.Errorf("%s: expected issues on %v, got %v", tc.name, tc.expected, errs)
+		}
+	}
+
+	// The checker agrees with what encoding/json decodes.
+	var a ambiguous
+	if err := stdjson.Unmarshal([]byte(`{"Owner":"colin"}`), &a); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if a.untaggedOwner.Owner != "" || a.otherOwner.Owner != "" {
+		t.Errorf("expected the ambiguous field not to be decoded, got %#v", a)
+	}
+	var d tagDominated
+	if err := stdjson.Unmarshal([]byte(`{"Owner":"colin"}`), &d); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if d.taggedOwner.Name != "colin" || d.untaggedOwner.Owner != "" {
+		t.Errorf("expected the tagged field to be decoded, got %#v", d)
+	}
+}
+
+func TestFields(t *testing.T) {
+	testCases := []struct {
+		v        interface{}
+		expected map[string][]int
+	}{
+		{ambiguous{}, map[string][]int{"depth": {2, 1}}},
+		{tagDominated{}, map[string][]int{"Owner": {0, 0}}},
+	}
+
+	for _, tc := range testCases {
+		if fields := Fields(reflect.TypeOf(tc.v)); !reflect.DeepEqual(fields, tc.expected) {
+			t.Errorf("%T: expected %v, got %v", tc.v, tc.expected, fields)
+		}
+	}
+}
+
+func TestStrictErrorTypes(t *testing.T) {
+	errs, err := StrictErrors([]byte(`{"labelSelecter":{},"Name":"foo","replicas":1,"replicas":2}`), &config{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []field.ErrorType{field.ErrorTypeNotSupported, field.ErrorTypeInvalid, field.ErrorTypeDuplicate}
+	if len(errs) != len(expected) {
+		t.Fatalf("expected %d issues, got %v", len(expected), errs)
+	}
+	for i, e := range errs {
+		if e.Type != expected[i] {
+			t.Errorf("expected %s, got %v", expected[i], e)
+		}
+	}
+}
+
+func TestUnmarshalStrict(t *testing.T) {
+	data := []byte(`{"name":"foo","replicas":1,"labelSelecter":{}}`)
+
+	c := &config{}
+	errs, err := UnmarshalStrict(data, c, StrictModeWarn)
+	if err != nil || len(errs) != 1 {
+		t.Errorf("expected a warning, got %v, %v", errs, err)
+	}
+	if c.Name != "foo" || c.Replicas != 1 {
+		t.Errorf("unexpected value %#v", c)
+	}
+
+	c = &config{}
+	errs, err = UnmarshalStrict(data, c, StrictModeError)
+	if !IsStrictDecodingError(err) || len(errs) != 1 {
+		t.Errorf("expected a strict decoding error, got %v, %v", errs, err)
+	}
+	if c.Name != "foo" {
+		t.Errorf("unexpected value %#v", c)
+	}
+
+	if _, err := UnmarshalStrict([]byte(`{"name":`), c, StrictModeError); err == nil || IsStrictDecodingError(err) {
+		t.Errorf("expected a syntax error, got %v", err)
+	}
+	if errs, err := UnmarshalStrict([]byte(`{"name":"foo"}`), c, StrictModeError); err != nil || len(errs) != 0 {
+		t.Errorf("unexpected issues %v, %v", errs, err)
+	}
+}
@@ -40,6 +40,11 @@ const (
 
 	ErrYamlMissingDictKey = errors.Error("Cannot find JSONDict key")
 	ErrYamlIllFormat      = errors.Error("Illformat")
+
+	ErrInvalidJsonPointer = errors.Error("not a valid JSON pointer")
+	ErrNotJsonContainer   = errors.Error("not a JSONDict or JSONArray")
+
+	ErrInvalidJsonSchema = errors.Error("not a valid JSON schema")
 )
 
 type JSONError struct {
@@ -0,0 +1,308 @@
+// Copyright 2020 Lingfei Kong <colin404@foxmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonutils
+
+import (
+	"fmt"
+	"strings"
+
+	"yunion.io/x/pkg/sortedmap"
+)
+
+// Operations of a JSON patch produced by DiffJSON.
+const (
+	PatchOpAdd     = "add"
+	PatchOpRemove  = "remove"
+	PatchOpReplace = "replace"
+	PatchOpMove    = "move"
+)
+
+// JSONDiffOptions configure how DiffJSON compares arrays.
+type JSONDiffOptions struct {
+	// ArrayKey is the field identifying the elements of arrays of dicts, such as
+	// name: the elements are matched by key, whatever their position. Arrays are
+	// compared by position when it is empty, or when an element is not a dict
+	// holding a unique key.
+	ArrayKey string
+	// ArrayKeys overrides ArrayKey for the arrays at some paths, given as JSON
+	// pointers in which * matches any token, such as /spec/containers/*/ports.
+	// An empty key compares the array by position.
+	ArrayKeys map[string]string
+}
+
+// JSONChange is a change between two documents, and an operation of the JSON
+// patch turning the first document into the second one.
+type JSONChange struct {
+	// Op is one of PatchOpAdd, PatchOpRemove, PatchOpReplace and PatchOpMove.
+	Op string
+	// Path is the JSON pointer of the changed object.
+	Path string
+	// From is the JSON pointer a moved array element comes from.
+	From string
+	// Old is the removed or replaced object.
+	Old JSONObject
+	// New is the added or replacing object.
+	New JSONObject
+}
+
+// JSONDiff is the list of changes between two documents, in the order of the
+// operations of a RFC 6902 JSON patch.
+type JSONDiff []JSONChange
+
+// DiffJSON compares the documents a and b and returns the changes turning a
+// into b. Numbers compare semantically: 1 equals 1.0.
+func DiffJSON(a, b JSONObject, opts *JSONDiffOptions) JSONDiff {
+	d := &jsonDiffer{}
+	if opts != nil {
+		d.opts = *opts
+	}
+	for pattern, key := range d.opts.ArrayKeys {
+		ptr, err := ParseJSONPointer(pattern)
+		if err != nil {
+			continue
+		}
+		d.arrayKeys = append(d.arrayKeys, arrayKeyPattern{pattern: ptr, key: key})
+	}
+	d.diff(JSONPointer{}, a, b)
+	return d.changes
+}
+
+// Equal tells whether the documents are equal.
+func (diff JSONDiff) Equal() bool {
+	return len(diff) == 0
+}
+
+// Patch returns the RFC 6902 JSON patch of the changes.
+func (diff JSONDiff) Patch() *JSONArray {
+	patch := NewArray()
+	for _, change := range diff {
+		op := NewDict()
+		op.Set("op", NewString(change.Op))
+		if change.Op == PatchOpMove {
+			op.Set("from", NewString(change.From))
+		}
+		op.Set("path", NewString(change.Path))
+		if change.Op == PatchOpAdd || change.Op == PatchOpReplace {
+			op.Set("value", change.New)
+		}
+		patch.Add(op)
+	}
+	return patch
+}
+
+// Report returns a human readable report of the changes, in the style of a
+// unified diff: each change starts with a @@ line holding its path, followed
+// by the removed lines prefixed with - and the added lines prefixed with +.
+func (diff JSONDiff) Report() string {
+	var sb strings.Builder
+	for _, change := range diff {
+		if change.Op == PatchOpMove {
+			fmt.Fprintf(&sb, "@@ %s -> %s @@\n", change.From, change.Path)
+			continue
+		}
+		path := change.Path
+		if path == "" {
+			path = "/"
+		}
+		fmt.Fprintf(&sb, "@@ %s @@\n", path)
+		if change.Old != nil {
+			writeReportLines(&sb, "-", change.Old)
+		}
+		if change.New != nil {
+			writeReportLines(&sb, "+", change.New)
+		}
+	}
+	return sb.String()
+}
+
+func writeReportLines(sb *strings.Builder, prefix string, obj JSONObject) {
+	for _, line := range strings.Split(obj.PrettyString(), "\n") {
+		sb.WriteString(prefix)
+		sb.WriteString(line)
+		sb.WriteByte('\n')
+	}
+}
+
+type arrayKeyPattern struct {
+	pattern JSONPointer
+	key     string
+}
+
+func (p *arrayKeyPattern) match(path JSONPointer) bool {
+	if len(p.pattern) != len(path) {
+		return false
+	}
+	for i, token := range p.pattern {
+		if token != "*" && token != path[i] {
+			return false
+		}
+	}
+	return true
+}
+
+type jsonDiffer struct {
+	opts      JSONDiffOptions
+	arrayKeys []arrayKeyPattern
+	changes   JSONDiff
+}
+
+func (d *jsonDiffer) add(change JSONChange) {
+	d.changes = append(d.changes, change)
+}
+
+func (d *jsonDiffer) diff(path JSONPointer, a, b JSONObject) {
+	switch ao := a.(type) {
+	case *JSONDict:
+		if bo, ok := b.(*JSONDict); ok {
+			d.diffDicts(path, ao, bo)
+			return
+		}
+	case *JSONArray:
+		if bo, ok := b.(*JSONArray); ok {
+			d.diffArrays(path, ao, bo)
+			return
+		}
+	}
+	if !jsonValueEquals(a, b) {
+		d.add(JSONChange{Op: PatchOpReplace, Path: path.String(), Old: a, New: b})
+	}
+}
+
+func (d *jsonDiffer) diffDicts(path JSONPointer, a, b *JSONDict) {
+	for iter := sortedmap.NewIterator(a.data); iter.HasMore(); iter.Next() {
+		k, av := iter.Get()
+		bv, ok := b.data.Get(k)
+		if !ok {
+			d.add(JSONChange{Op: PatchOpRemove, Path: childPointer(path, k).String(), Old: av.(JSONObject)})
+			continue
+		}
+		d.diff(childPointer(path, k), av.(JSONObject), bv.(JSONObject))
+	}
+	for iter := sortedmap.NewIterator(b.data); iter.HasMore(); iter.Next() {
+		k, bv := iter.Get()
+		if _, ok := a.data.Get(k); !ok {
+			d.add(JSONChange{Op: PatchOpAdd, Path: childPointer(path, k).String(), New: bv.(JSONObject)})
+		}
+	}
+}
+
+func (d *jsonDiffer) diffArrays(path JSONPointer, a, b *JSONArray) {
+	key := d.arrayKey(path)
+	if key != "" {
+		aKeys, aok := elementKeys(a, key)
+		bKeys, bok := elementKeys(b, key)
+		if aok && bok {
+			d.diffKeyedArrays(path, a, b, aKeys, bKeys)
+			return
+		}
+	}
+
+	n := len(a.data)
+	if len(b.data) < n {
+		n = len(b.data)
+	}
+	for i := 0; i < n; i++ {
+		d.diff(indexPointer(path, i), a.data[i], b.data[i])
+	}
+	// The trailing elements are removed from the end, so that indexes stay valid.
+	for i := len(a.data) - 1; i >= n; i-- {
+		d.add(JSONChange{Op: PatchOpRemove, Path: indexPointer(path, i).String(), Old: a.data[i]})
+	}
+	for i := n; i < len(b.data); i++ {
+		d.add(JSONChange{Op: PatchOpAdd, Path: indexPointer(path, i).String(), New: b.data[i]})
+	}
+}
+
+// diffKeyedArrays matches the elements of a and b by key. The elements missing
+// from b are removed first; then the elements of b are moved, or added, to their
+// position in order, and compared with the element of a of the same key.
+func (d *jsonDiffer) diffKeyedArrays(path JSONPointer, a, b *JSONArray, aKeys, bKeys []string) {
+	inB := make(map[string]bool, len(bKeys))
+	for _, k := range bKeys {
+		inB[k] = true
+	}
+	elements := make(map[string]JSONObject, len(aKeys))
+	var current []string
+	for i := len(aKeys) - 1; i >= 0; i-- {
+		if !inB[aKeys[i]] {
+			d.add(JSONChange{Op: PatchOpRemove, Path: indexPointer(path, i).String(), Old: a.data[i]})
+			continue
+		}
+		elements[aKeys[i]] = a.data[i]
+		current = append([]string{aKeys[i]}, current...)
+	}
+
+	for j, k := range bKeys {
+		elem, ok := elements[k]
+		if !ok {
+			d.add(JSONChange{Op: PatchOpAdd, Path: indexPointer(path, j).String(), New: b.data[j]})
+			current = append(current[:j], append([]string{k}, current[j:]...)...)
+			continue
+		}
+		if p := indexOfString(current, k); p != j {
+			d.add(JSONChange{
+				Op:   PatchOpMove,
+				From: indexPointer(path, p).String(),
+				Path: indexPointer(path, j).String(),
+			})
+			current = append(current[:p], current[p+1:]...)
+			current = append(current[:j], append([]string{k}, current[j:]...)...)
+		}
+		d.diff(indexPointer(path, j), elem, b.data[j])
+	}
+}
+
+// arrayKey returns the key of the elements of the array at path.
+func (d *jsonDiffer) arrayKey(path JSONPointer) string {
+	for i := range d.arrayKeys {
+		if d.arrayKeys[i].match(path) {
+			return d.arrayKeys[i].key
+		}
+	}
+	return d.opts.ArrayKey
+}
+
+// elementKeys returns the keys of the elements of arr, or false if an element
+// is not a dict holding a unique key.
+func elementKeys(arr *JSONArray, key string) ([]string, bool) {
+	keys := make([]string, 0, len(arr.data))
+	seen := make(map[string]bool, len(arr.data))
+	for _, elem := range arr.data {
+		dict, ok := elem.(*JSONDict)
+		if !ok {
+			return nil, false
+		}
+		v, ok := dict.data.Get(key)
+		if !ok {
+			return nil, false
+		}
+		k := v.(JSONObject).String()
+		if seen[k] {
+			return nil, false
+		}
+		seen[k] = true
+		keys = append(keys, k)
+	}
+	return keys, true
+}
+
+func indexOfString(strs []string, s string) int {
+	for i := range strs {
+		if strs[i] == s {
+			return i
+		}
+	}
+	return -1
+}
+
+func childPointer(path JSONPointer, token string) JSONPointer {
+	child := make(JSONPointer, len(path), len(path)+1)
+	copy(child, path)
+	return append(child, token)
+}
+
+func indexPointer(path JSONPointer, i int) JSONPointer {
+	return childPointer(path, fmt.Sprintf("%d", i))
+}
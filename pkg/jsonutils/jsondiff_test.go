@@ -0,0 +1,193 @@
+// Copyright 2020 Lingfei Kong <colin404@foxmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonutils
+
+import (
+	"fmt"
+	"testing"
+)
+
+// applyPatch applies a RFC 6902 JSON patch made of the operations produced by
+// DiffJSON to doc, and returns the patched document.
+func applyPatch(doc JSONObject, patch *JSONArray) (JSONObject, error) {
+	for _, op := range patch.data {
+		name, _ := op.GetString("op")
+		path, _ := op.GetString("path")
+		value, _ := op.Get("value")
+		var err error
+		switch name {
+		case PatchOpAdd:
+			doc, err = patchAdd(doc, path, value)
+		case PatchOpRemove:
+			err = RemovePointer(doc, path)
+		case PatchOpReplace:
+			if path == "" {
+				doc = value
+				continue
+			}
+			if _, err = GetPointer(doc, path); err == nil {
+				err = SetPointer(doc, path, value, false)
+			}
+		case PatchOpMove:
+			from, _ := op.GetString("from")
+			if value, err = GetPointer(doc, from); err == nil {
+				if err = RemovePointer(doc, from); err == nil {
+					doc, err = patchAdd(doc, path, value)
+				}
+			}
+		default:
+			err = fmt.Errorf("unknown op %q", name)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", op, err)
+		}
+	}
+	return doc, nil
+}
+
+// patchAdd adds value at path, inserting it when the parent is an array.
+func patchAdd(doc JSONObject, path string, value JSONObject) (JSONObject, error) {
+	ptr, err := ParseJSONPointer(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(ptr) == 0 {
+		return value, nil
+	}
+	parent, err := GetPointer(doc, ptr[:len(ptr)-1].String())
+	if err != nil {
+		return nil, err
+	}
+	arr, ok := parent.(*JSONArray)
+	if !ok {
+		return doc, SetPointer(doc, path, value, false)
+	}
+	i, err := pointerIndex(ptr[len(ptr)-1], len(arr.data))
+	if err != nil {
+		return nil, err
+	}
+	if i > len(arr.data) {
+		return nil, ErrOutOfIndexRange
+	}
+	data := append([]JSONObject{}, arr.data[:i]...)
+	arr.data = append(append(data, value), arr.data[i:]...)
+	return doc, nil
+}
+
+func TestDiffJSON(t *testing.T) {
+	cases := []struct {
+		name  string
+		a     string
+		b     string
+		opts  *JSONDiffOptions
+		patch string
+	}{
+		{"equal", `{"a":[1,{"b":"c"}]}`, `{"a":[1,{"b":"c"}]}`, nil, `[]`},
+		{"int equals float", `{"a":1,"b":[2]}`, `{"a":1.0,"b":[2.0]}`, nil, `[]`},
+		{"number changed", `{"a":1}`, `{"a":1.5}`, nil, `[{"op":"replace","path":"/a","value":1.5}]`},
+		{"type changed", `{"a":1}`, `{"a":"1"}`, nil, `[{"op":"replace","path":"/a","value":"1"}]`},
+		{"root replaced", `[1]`, `{"a":1}`, nil, `[{"op":"replace","path":"","value":{"a":1}}]`},
+		{
+			"fields added and removed", `{"a":1,"b/c":2}`, `{"a":1,"d~e":3}`, nil,
+			`[{"op":"remove","path":"/b~1c"},{"op":"add","path":"/d~0e","value":3}]`,
+		},
+		{
+			"index array shortened", `[1,2,3]`, `[1,3]`, nil,
+			`[{"op":"replace","path":"/1","value":3},{"op":"remove","path":"/2"}]`,
+		},
+		{
+			"index array extended", `[1]`, `[1,2,3]`, nil,
+			`[{"op":"add","path":"/1","value":2},{"op":"add","path":"/2","value":3}]`,
+		},
+		{
+			"index array removes from the end", `[1,2,3]`, `[1]`, nil,
+			`[{"op":"remove","path":"/2"},{"op":"remove","path":"/1"}]`,
+		},
+		{
+			"keyed array",
+			`[{"name":"x"},{"name":"y"},{"name":"z"}]`,
+			`[{"name":"z"},{"name":"x","v":1}]`,
+			&JSONDiffOptions{ArrayKey: "name"},
+			`[{"op":"remove","path":"/1"},{"from":"/1","op":"move","path":"/0"},{"op":"add","path":"/1/v","value":1}]`,
+		},
+		{
+			"keyed array insertion",
+			`[{"name":"x"},{"name":"z"}]`,
+			`[{"name":"x"},{"name":"y"},{"name":"z"}]`,
+			&JSONDiffOptions{ArrayKey: "name"},
+			`[{"op":"add","path":"/1","value":{"name":"y"}}]`,
+		},
+		{
+			"same array by index",
+			`[{"name":"x"},{"name":"z"}]`,
+			`[{"name":"x"},{"name":"y"},{"name":"z"}]`,
+			nil,
+			`[{"op":"replace","path":"/1/name","value":"y"},{"op":"add","path":"/2","value":{"name":"z"}}]`,
+		},
+		{
+			"duplicate keys compare by index",
+			`[{"name":"x"},{"name":"x"}]`,
+			`[{"name":"x"}]`,
+			&JSONDiffOptions{ArrayKey: "name"},
+			`[{"op":"remove","path":"/1"}]`,
+		},
+		{
+			"scalars compare by index", `[1,2]`, `[2,1]`, &JSONDiffOptions{ArrayKey: "name"},
+			`[{"op":"replace","path":"/0","value":2},{"op":"replace","path":"/1","value":1}]`,
+		},
+		{
+			"array keys by path",
+			`{"spec":[{"ports":[{"port":80},{"port":443}],"tags":[{"port":1},{"port":2}]}]}`,
+			`{"spec":[{"ports":[{"port":443},{"port":80}],"tags":[{"port":2},{"port":1}]}]}`,
+			&JSONDiffOptions{ArrayKeys: map[string]string{"/spec/*/ports": "port"}},
+			`[{"from":"/spec/0/ports/1","op":"move","path":"/spec/0/ports/0"},` +
+				`{"op":"replace","path":"/spec/0/tags/0/port","value":2},` +
+				`{"op":"replace","path":"/spec/0/tags/1/port","value":1}]`,
+		},
+		{
+			"empty array key overrides",
+			`{"a":[{"name":"x"},{"name":"y"}]}`,
+			`{"a":[{"name":"y"},{"name":"x"}]}`,
+			&JSONDiffOptions{ArrayKey: "name", ArrayKeys: map[string]string{"/a": ""}},
+			`[{"op":"replace","path":"/a/0/name","value":"y"},{"op":"replace","path":"/a/1/name","value":"x"}]`,
+		},
+	}
+	for _, c := range cases {
+		a := mustParse(t, c.a)
+		b := mustParse(t, c.b)
+		diff := DiffJSON(a, b, c.opts)
+		if diff.Equal() != (c.patch == "[]") {
+			t.Errorf("%s: want equal %v got %v", c.name, c.patch == "[]", diff.Equal())
+		}
+		patch := diff.Patch()
+		if patch.String() != c.patch {
+			t.Errorf("%s: want patch %s got %s", c.name, c.patch, patch.String())
+		}
+
+		patched, err := applyPatch(mustParse(t, c.a), mustParse(t, patch.String()).(*JSONArray))
+		if err != nil {
+			t.Errorf("%s: apply %s: %v", c.name, patch.String(), err)
+			continue
+		}
+		if !DiffJSON(patched, b, nil).Equal() {
+			t.Errorf("%s: patched %s into %s, want %s", c.name, c.a, patched, c.b)
+		}
+	}
+}
+
+func TestJSONDiffReport(t *testing.T) {
+	a := mustParse(t, `{"a":1,"b":[{"name":"x"},{"name":"y"}]}`)
+	b := mustParse(t, `{"a":2,"b":[{"name":"y"},{"name":"x"}],"c":"d"}`)
+	report := DiffJSON(a, b, &JSONDiffOptions{ArrayKey: "name"}).Report()
+	want := "@@ /a @@\n-1\n+2\n@@ /b/1 -> /b/0 @@\n@@ /c @@\n+\"d\"\n"
+	if report != want {
+		t.Errorf("want report\n%s\ngot\n%s", want, report)
+	}
+
+	report = DiffJSON(mustParse(t, `1`), mustParse(t, `2`), nil).Report()
+	if report != "@@ / @@\n-1\n+2\n" {
+		t.Errorf("unexpected report of the root\n%s", report)
+	}
+}
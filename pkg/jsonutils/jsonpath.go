@@ -0,0 +1,778 @@
+// Copyright 2020 Lingfei Kong <colin404@foxmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonutils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"yunion.io/x/pkg/sortedmap"
+)
+
+// JSONPathError is a syntax error of a JSONPath expression, at the byte offset
+// Offset of Expr.
+type JSONPathError struct {
+	Expr   string
+	Offset int
+	Msg    string
+}
+
+func (e *JSONPathError) Error() string {
+	return fmt.Sprintf("jsonpath error %s at %d: %s^%s", e.Msg, e.Offset, e.Expr[:e.Offset], e.Expr[e.Offset:])
+}
+
+// JSONPath is a compiled JSONPath expression, safe for concurrent use.
+//
+// The supported syntax is:
+//
+//	$                 the root object, which may be omitted
+//	.name, ['name']   a field of a dict
+//	.*, [*]           all the fields of a dict, or all the elements of an array
+//	[0], [-1]         an element of an array, negative indexes counting from the end
+//	[start:end:step]  a slice of an array
+//	[a,b]             the union of several selectors
+//	..name, ..*       recursive descent, selecting among the object and all its descendants
+//	[?(expr)]         the fields or elements for which the filter expression is true
+//
+// Filter expressions compare the first object selected by a path relative to the
+// current object (@) or to the root ($) with literals or other paths, using ==, !=,
+// <, <=, > and >=, and combine the comparisons with &&, || and !. A path alone
+// tests that it selects an object. Numbers compare semantically, 1 being equal to 1.0.
+type JSONPath struct {
+	expr     string
+	segments []pathSegment
+}
+
+// CompileJSONPath compiles a JSONPath expression, which can then be evaluated
+// against several objects.
+func CompileJSONPath(expr string) (*JSONPath, error) {
+	p := &jsonPathParser{expr: expr}
+	p.skipSpaces()
+	if p.peek() == '$' {
+		p.pos++
+	}
+	segments, err := p.parseSegments()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpaces()
+	if p.pos < len(p.expr) {
+		return nil, p.errorf("unexpected character %q", p.expr[p.pos])
+	}
+	return &JSONPath{expr: expr, segments: segments}, nil
+}
+
+// MustCompileJSONPath is like CompileJSONPath but panics if the expression is invalid.
+func MustCompileJSONPath(expr string) *JSONPath {
+	path, err := CompileJSONPath(expr)
+	if err != nil {
+		panic(err)
+	}
+	return path
+}
+
+// String returns the source expression of the path.
+func (path *JSONPath) String() string {
+	return path.expr
+}
+
+// Find returns the objects selected by the path in obj, in document order.
+func (path *JSONPath) Find(obj JSONObject) []JSONObject {
+	return evalSegments(path.segments, obj, obj)
+}
+
+// FindOne returns the first object selected by the path in obj, or
+// ErrJsonDictKeyNotFound if the path selects nothing.
+func (path *JSONPath) FindOne(obj JSONObject) (JSONObject, error) {
+	nodes := path.Find(obj)
+	if len(nodes) == 0 {
+		return nil, ErrJsonDictKeyNotFound
+	}
+	return nodes[0], nil
+}
+
+// QueryJSONPath compiles expr and returns the objects it selects in obj.
+func QueryJSONPath(obj JSONObject, expr string) ([]JSONObject, error) {
+	path, err := CompileJSONPath(expr)
+	if err != nil {
+		return nil, err
+	}
+	return path.Find(obj), nil
+}
+
+type pathSegment struct {
+	recursive bool
+	selectors []pathSelector
+}
+
+type pathSelector interface {
+	// selectFrom appends the children of obj it selects to nodes.
+	selectFrom(root, obj JSONObject, nodes []JSONObject) []JSONObject
+}
+
+func evalSegments(segments []pathSegment, root, obj JSONObject) []JSONObject {
+	nodes := []JSONObject{obj}
+	for _, seg := range segments {
+		var next []JSONObject
+		for _, node := range nodes {
+			if seg.recursive {
+				for _, desc := range descendants(node, nil) {
+					for _, sel := range seg.selectors {
+						next = sel.selectFrom(root, desc, next)
+					}
+				}
+				continue
+			}
+			for _, sel := range seg.selectors {
+				next = sel.selectFrom(root, node, next)
+			}
+		}
+		nodes = next
+	}
+	return nodes
+}
+
+// descendants appends obj and all its descendants to nodes, in document order.
+func descendants(obj JSONObject, nodes []JSONObject) []JSONObject {
+	nodes = append(nodes, obj)
+	for _, child := range children(obj) {
+		nodes = descendants(child, nodes)
+	}
+	return nodes
+}
+
+// children returns the values of a dict, by key, or the elements of an array.
+func children(obj JSONObject) []JSONObject {
+	switch o := obj.(type) {
+	case *JSONDict:
+		values := make([]JSONObject, 0, len(o.data))
+		for iter := sortedmap.NewIterator(o.data); iter.HasMore(); iter.Next() {
+			_, v := iter.Get()
+			values = append(values, v.(JSONObject))
+		}
+		return values
+	case *JSONArray:
+		return o.data
+	default:
+		return nil
+	}
+}
+
+type nameSelector struct {
+	name string
+}
+
+func (sel *nameSelector) selectFrom(root, obj JSONObject, nodes []JSONObject) []JSONObject {
+	if dict, ok := obj.(*JSONDict); ok {
+		if v, ok := dict.data.Get(sel.name); ok {
+			nodes = append(nodes, v.(JSONObject))
+		}
+	}
+	return nodes
+}
+
+type wildcardSelector struct{}
+
+func (sel *wildcardSelector) selectFrom(root, obj JSONObject, nodes []JSONObject) []JSONObject {
+	return append(nodes, children(obj)...)
+}
+
+type indexSelector struct {
+	index int
+}
+
+func (sel *indexSelector) selectFrom(root, obj JSONObject, nodes []JSONObject) []JSONObject {
+	if arr, ok := obj.(*JSONArray); ok {
+		i := sel.index
+		if i < 0 {
+			i += len(arr.data)
+		}
+		if i >= 0 && i < len(arr.data) {
+			nodes = append(nodes, arr.data[i])
+		}
+	}
+	return nodes
+}
+
+type sliceSelector struct {
+	start, end, step *int
+}
+
+func (sel *sliceSelector) selectFrom(root, obj JSONObject, nodes []JSONObject) []JSONObject {
+	arr, ok := obj.(*JSONArray)
+	if !ok {
+		return nodes
+	}
+	n := len(arr.data)
+	step := 1
+	if sel.step != nil {
+		step = *sel.step
+	}
+	normalize := func(i *int, def int) int {
+		if i == nil {
+			return def
+		}
+		if *i < 0 {
+			return n + *i
+		}
+		return *i
+	}
+
+	switch {
+	case step > 0:
+		lower := clampInt(normalize(sel.start, 0), 0, n)
+		upper := clampInt(normalize(sel.end, n), 0, n)
+		for i := lower; i < upper; i += step {
+			nodes = append(nodes, arr.data[i])
+		}
+	case step < 0:
+		upper := clampInt(normalize(sel.start, n-1), -1, n-1)
+		lower := clampInt(normalize(sel.end, -1), -1, n-1)
+		if sel.end == nil {
+			lower = -1
+		}
+		for i := upper; i > lower; i += step {
+			nodes = append(nodes, arr.data[i])
+		}
+	}
+	return nodes
+}
+
+func clampInt(i, min, max int) int {
+	if i < min {
+		return min
+	}
+	if i > max {
+		return max
+	}
+	return i
+}
+
+type filterSelector struct {
+	expr filterExpr
+}
+
+func (sel *filterSelector) selectFrom(root, obj JSONObject, nodes []JSONObject) []JSONObject {
+	for _, child := range children(obj) {
+		if sel.expr.eval(root, child) {
+			nodes = append(nodes, child)
+		}
+	}
+	return nodes
+}
+
+type filterExpr interface {
+	eval(root, current JSONObject) bool
+}
+
+type orExpr struct {
+	left, right filterExpr
+}
+
+func (e *orExpr) eval(root, current JSONObject) bool {
+	return e.left.eval(root, current) || e.right.eval(root, current)
+}
+
+type andExpr struct {
+	left, right filterExpr
+}
+
+func (e *andExpr) eval(root, current JSONObject) bool {
+	return e.left.eval(root, current) && e.right.eval(root, current)
+}
+
+type notExpr struct {
+	expr filterExpr
+}
+
+func (e *notExpr) eval(root, current JSONObject) bool {
+	return !e.expr.eval(root, current)
+}
+
+type existsExpr struct {
+	path *pathOperand
+}
+
+func (e *existsExpr) eval(root, current JSONObject) bool {
+	_, ok := e.path.value(root, current)
+	return ok
+}
+
+type compareExpr struct {
+	op          string
+	left, right operand
+}
+
+func (e *compareExpr) eval(root, current JSONObject) bool {
+	l, lok := e.left.value(root, current)
+	r, rok := e.right.value(root, current)
+	if !lok || !rok {
+		// A missing value only equals another missing value.
+		switch e.op {
+		case "==":
+			return lok == rok
+		case "!=":
+			return lok != rok
+		default:
+			return false
+		}
+	}
+
+	switch e.op {
+	case "==":
+		return jsonValueEquals(l, r)
+	case "!=":
+		return !jsonValueEquals(l, r)
+	}
+	var cmp int
+	if lf, ok := jsonNumber(l); ok {
+		rf, ok := jsonNumber(r)
+		if !ok {
+			return false
+		}
+		cmp = compareFloats(lf, rf)
+	} else {
+		ls, lok := l.(*JSONString)
+		rs, rok := r.(*JSONString)
+		if !lok || !rok {
+			return false
+		}
+		cmp = strings.Compare(ls.data, rs.data)
+	}
+	switch e.op {
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	default:
+		return cmp >= 0
+	}
+}
+
+func compareFloats(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// jsonNumber returns the value of a JSONInt or a JSONFloat.
+func jsonNumber(obj JSONObject) (float64, bool) {
+	switch o := obj.(type) {
+	case *JSONInt:
+		return float64(o.data), true
+	case *JSONFloat:
+		return o.data, true
+	default:
+		return 0, false
+	}
+}
+
+// jsonValueEquals is like Equals, except that numbers compare semantically, at
+// any depth: 1 equals 1.0.
+func jsonValueEquals(a, b JSONObject) bool {
+	if af, ok := jsonNumber(a); ok {
+		bf, ok := jsonNumber(b)
+		return ok && af == bf
+	}
+	switch ao := a.(type) {
+	case *JSONDict:
+		bo, ok := b.(*JSONDict)
+		if !ok || len(ao.data) != len(bo.data) {
+			return false
+		}
+		for iter := sortedmap.NewIterator(ao.data); iter.HasMore(); iter.Next() {
+			k, av := iter.Get()
+			bv, ok := bo.data.Get(k)
+			if !ok || !jsonValueEquals(av.(JSONObject), bv.(JSONObject)) {
+				return false
+			}
+		}
+		return true
+	case *JSONArray:
+		bo, ok := b.(*JSONArray)
+		if !ok || len(ao.data) != len(bo.data) {
+			return false
+		}
+		for i := range ao.data {
+			if !jsonValueEquals(ao.data[i], bo.data[i]) {
+				return false
+			}
+		}
+		return true
+	default:
+		return a.Equals(b)
+	}
+}
+
+type operand interface {
+	value(root, current JSONObject) (JSONObject, bool)
+}
+
+type literalOperand struct {
+	obj JSONObject
+}
+
+func (o *literalOperand) value(root, current JSONObject) (JSONObject, bool) {
+	return o.obj, true
+}
+
+type pathOperand struct {
+	relative bool
+	segments []pathSegment
+}
+
+// value returns the first object selected by the path.
+func (o *pathOperand) value(root, current JSONObject) (JSONObject, bool) {
+	start := root
+	if o.relative {
+		start = current
+	}
+	nodes := evalSegments(o.segments, root, start)
+	if len(nodes) == 0 {
+		return nil, false
+	}
+	return nodes[0], true
+}
+
+type jsonPathParser struct {
+	expr string
+	pos  int
+}
+
+func (p *jsonPathParser) errorf(format string, args ...interface{}) error {
+	return &JSONPathError{Expr: p.expr, Offset: p.pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+func (p *jsonPathParser) peek() byte {
+	if p.pos < len(p.expr) {
+		return p.expr[p.pos]
+	}
+	return 0
+}
+
+func (p *jsonPathParser) skipSpaces() {
+	for p.pos < len(p.expr) && (p.expr[p.pos] == ' ' || p.expr[p.pos] == '\t') {
+		p.pos++
+	}
+}
+
+func (p *jsonPathParser) consume(s string) bool {
+	if strings.HasPrefix(p.expr[p.pos:], s) {
+		p.pos += len(s)
+		return true
+	}
+	return false
+}
+
+// parseSegments parses the segments of a path, up to the first character which
+// does not continue it.
+func (p *jsonPathParser) parseSegments() ([]pathSegment, error) {
+	var segments []pathSegment
+	for {
+		switch p.peek() {
+		case '.':
+			p.pos++
+			seg := pathSegment{}
+			if p.peek() == '.' {
+				p.pos++
+				seg.recursive = true
+			}
+			switch {
+			case seg.recursive && p.peek() == '[':
+				selectors, err := p.parseBracket()
+				if err != nil {
+					return nil, err
+				}
+				seg.selectors = selectors
+			case p.peek() == '*':
+				p.pos++
+				seg.selectors = []pathSelector{&wildcardSelector{}}
+			default:
+				name := p.parseName()
+				if name == "" {
+					return nil, p.errorf("expected a field name")
+				}
+				seg.selectors = []pathSelector{&nameSelector{name: name}}
+			}
+			segments = append(segments, seg)
+		case '[':
+			selectors, err := p.parseBracket()
+			if err != nil {
+				return nil, err
+			}
+			segments = append(segments, pathSegment{selectors: selectors})
+		default:
+			return segments, nil
+		}
+	}
+}
+
+func (p *jsonPathParser) parseName() string {
+	start := p.pos
+	for p.pos < len(p.expr) && !strings.ContainsRune(".[]()=!<>&|,'\" \t", rune(p.expr[p.pos])) {
+		p.pos++
+	}
+	return p.expr[start:p.pos]
+}
+
+// parseBracket parses the selectors of a bracketed segment.
+func (p *jsonPathParser) parseBracket() ([]pathSelector, error) {
+	p.pos++
+	var selectors []pathSelector
+	for {
+		p.skipSpaces()
+		sel, err := p.parseSelector()
+		if err != nil {
+			return nil, err
+		}
+		selectors = append(selectors, sel)
+
+		p.skipSpaces()
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case ']':
+			p.pos++
+			return selectors, nil
+		default:
+			return nil, p.errorf("expected ',' or ']'")
+		}
+	}
+}
+
+func (p *jsonPathParser) parseSelector() (pathSelector, error) {
+	switch c := p.peek(); {
+	case c == '*':
+		p.pos++
+		return &wildcardSelector{}, nil
+	case c == '\'' || c == '"':
+		name, err := p.parseString()
+		if err != nil {
+			return nil, err
+		}
+		return &nameSelector{name: name}, nil
+	case c == '?':
+		p.pos++
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		return &filterSelector{expr: expr}, nil
+	case c == '-' || c == ':' || (c >= '0' && c <= '9'):
+		return p.parseIndexOrSlice()
+	default:
+		return nil, p.errorf("expected a selector")
+	}
+}
+
+func (p *jsonPathParser) parseIndexOrSlice() (pathSelector, error) {
+	start, err := p.parseInt()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpaces()
+	if p.peek() != ':' {
+		if start == nil {
+			return nil, p.errorf("expected an index")
+		}
+		return &indexSelector{index: *start}, nil
+	}
+
+	sel := &sliceSelector{start: start}
+	p.pos++
+	p.skipSpaces()
+	if sel.end, err = p.parseInt(); err != nil {
+		return nil, err
+	}
+	p.skipSpaces()
+	if p.peek() == ':' {
+		p.pos++
+		p.skipSpaces()
+		if sel.step, err = p.parseInt(); err != nil {
+			return nil, err
+		}
+	}
+	return sel, nil
+}
+
+// parseInt parses an optional integer.
+func (p *jsonPathParser) parseInt() (*int, error) {
+	start := p.pos
+	if p.peek() == '-' {
+		p.pos++
+	}
+	for p.peek() >= '0' && p.peek() <= '9' {
+		p.pos++
+	}
+	if p.pos == start {
+		return nil, nil
+	}
+	i, err := strconv.Atoi(p.expr[start:p.pos])
+	if err != nil {
+		p.pos = start
+		return nil, p.errorf("invalid integer")
+	}
+	return &i, nil
+}
+
+// parseString parses a single or double quoted string, in which a backslash
+// escapes the next character.
+func (p *jsonPathParser) parseString() (string, error) {
+	start := p.pos
+	quote := p.expr[p.pos]
+	p.pos++
+	var sb strings.Builder
+	for p.pos < len(p.expr) {
+		c := p.expr[p.pos]
+		p.pos++
+		switch {
+		case c == quote:
+			return sb.String(), nil
+		case c == '\\' && p.pos < len(p.expr):
+			sb.WriteByte(p.expr[p.pos])
+			p.pos++
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	p.pos = start
+	return "", p.errorf("unterminated string")
+}
+
+func (p *jsonPathParser) parseOr() (filterExpr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		p.skipSpaces()
+		if !p.consume("||") {
+			return left, nil
+		}
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &orExpr{left: left, right: right}
+	}
+}
+
+func (p *jsonPathParser) parseAnd() (filterExpr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		p.skipSpaces()
+		if !p.consume("&&") {
+			return left, nil
+		}
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &andExpr{left: left, right: right}
+	}
+}
+
+var compareOps = []string{"==", "!=", "<=", ">=", "<", ">"}
+
+func (p *jsonPathParser) parseUnary() (filterExpr, error) {
+	p.skipSpaces()
+	switch {
+	case p.peek() == '!' && !strings.HasPrefix(p.expr[p.pos:], "!="):
+		p.pos++
+		expr, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &notExpr{expr: expr}, nil
+	case p.peek() == '(':
+		p.pos++
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		p.skipSpaces()
+		if !p.consume(")") {
+			return nil, p.errorf("expected ')'")
+		}
+		return expr, nil
+	}
+
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpaces()
+	for _, op := range compareOps {
+		if p.consume(op) {
+			right, err := p.parseOperand()
+			if err != nil {
+				return nil, err
+			}
+			return &compareExpr{op: op, left: left, right: right}, nil
+		}
+	}
+	path, ok := left.(*pathOperand)
+	if !ok {
+		return nil, p.errorf("expected a comparison operator")
+	}
+	return &existsExpr{path: path}, nil
+}
+
+func (p *jsonPathParser) parseOperand() (operand, error) {
+	p.skipSpaces()
+	switch c := p.peek(); {
+	case c == '@' || c == '$':
+		p.pos++
+		segments, err := p.parseSegments()
+		if err != nil {
+			return nil, err
+		}
+		return &pathOperand{relative: c == '@', segments: segments}, nil
+	case c == '\'' || c == '"':
+		s, err := p.parseString()
+		if err != nil {
+			return nil, err
+		}
+		return &literalOperand{obj: NewString(s)}, nil
+	case c == '-' || (c >= '0' && c <= '9'):
+		return p.parseNumber()
+	case p.consume("true"):
+		return &literalOperand{obj: JSONTrue}, nil
+	case p.consume("false"):
+		return &literalOperand{obj: JSONFalse}, nil
+	case p.consume("null"):
+		return &literalOperand{obj: JSONNull}, nil
+	default:
+		return nil, p.errorf("expected a value")
+	}
+}
+
+func (p *jsonPathParser) parseNumber() (operand, error) {
+	start := p.pos
+	for p.pos < len(p.expr) && strings.ContainsRune("+-.eE0123456789", rune(p.expr[p.pos])) {
+		p.pos++
+	}
+	s := p.expr[start:p.pos]
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return &literalOperand{obj: NewInt(i)}, nil
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		p.pos = start
+		return nil, p.errorf("invalid number")
+	}
+	return &literalOperand{obj: NewFloat64(f)}, nil
+}
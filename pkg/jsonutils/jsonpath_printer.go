@@ -0,0 +1,174 @@
+// Copyright 2020 Lingfei Kong <colin404@foxmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonutils
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// JSONPathOutputPrefix is the prefix of the output format of a command line
+// printing objects through a JSONPath template, as in -o jsonpath={.name}.
+const JSONPathOutputPrefix = "jsonpath="
+
+// JSONPathPrinter prints objects through a template, in which the JSONPath
+// expressions between braces are replaced by the objects they select, such as
+// "{.metadata.name}{'\t'}{.status.phase}{'\n'}". A brace holding a quoted string
+// prints the string. The objects selected by an expression are separated by
+// spaces; strings are printed without quotes.
+type JSONPathPrinter struct {
+	parts            []templatePart
+	allowMissingKeys bool
+}
+
+// templatePart is either a text or a path.
+type templatePart struct {
+	text string
+	path *JSONPath
+}
+
+// NewJSONPathPrinter parses template. Errors hold the offset in the template.
+func NewJSONPathPrinter(template string) (*JSONPathPrinter, error) {
+	printer := &JSONPathPrinter{}
+	for pos := 0; pos < len(template); {
+		open := strings.IndexByte(template[pos:], '{')
+		if open < 0 {
+			printer.parts = append(printer.parts, templatePart{text: template[pos:]})
+			break
+		}
+		open += pos
+		if open > pos {
+			printer.parts = append(printer.parts, templatePart{text: template[pos:open]})
+		}
+
+		end := closingBrace(template, open+1)
+		if end < 0 {
+			return nil, &JSONPathError{Expr: template, Offset: open, Msg: "unclosed brace"}
+		}
+		part, err := parseTemplateBlock(template[open+1 : end])
+		if err != nil {
+			if e, ok := err.(*JSONPathError); ok {
+				return nil, &JSONPathError{Expr: template, Offset: open + 1 + e.Offset, Msg: e.Msg}
+			}
+			return nil, err
+		}
+		printer.parts = append(printer.parts, part)
+		pos = end + 1
+	}
+	return printer, nil
+}
+
+// NewJSONPathPrinterForOutput returns the printer of an output format such as
+// jsonpath={.items[*].name}, given to a -o command line flag.
+func NewJSONPathPrinterForOutput(output string) (*JSONPathPrinter, error) {
+	if !strings.HasPrefix(output, JSONPathOutputPrefix) {
+		return nil, fmt.Errorf("output format %q is not %s<template>", output, JSONPathOutputPrefix)
+	}
+	template := strings.TrimPrefix(output, JSONPathOutputPrefix)
+	if template == "" {
+		return nil, fmt.Errorf("missing template of output format %q", output)
+	}
+	return NewJSONPathPrinter(template)
+}
+
+// AllowMissingKeys tells whether expressions selecting nothing print nothing,
+// instead of failing.
+func (printer *JSONPathPrinter) AllowMissingKeys(allow bool) {
+	printer.allowMissingKeys = allow
+}
+
+// PrintObj prints obj through the template to w.
+func (printer *JSONPathPrinter) PrintObj(obj JSONObject, w io.Writer) error {
+	var sb strings.Builder
+	for _, part := range printer.parts {
+		if part.path == nil {
+			sb.WriteString(part.text)
+			continue
+		}
+		nodes := part.path.Find(obj)
+		if len(nodes) == 0 && !printer.allowMissingKeys {
+			return fmt.Errorf("%s is not found", part.path)
+		}
+		for i, node := range nodes {
+			if i > 0 {
+				sb.WriteByte(' ')
+			}
+			if s, ok := node.(*JSONString); ok {
+				sb.WriteString(s.data)
+			} else {
+				sb.WriteString(node.String())
+			}
+		}
+	}
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// closingBrace returns the index of the brace closing a block starting at
+// start, ignoring the braces in quoted strings, or -1.
+func closingBrace(template string, start int) int {
+	var quote byte
+	for i := start; i < len(template); i++ {
+		c := template[i]
+		switch {
+		case quote != 0 && c == '\\':
+			i++
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '}':
+			return i
+		}
+	}
+	return -1
+}
+
+func parseTemplateBlock(block string) (templatePart, error) {
+	trimmed := strings.TrimSpace(block)
+	if len(trimmed) >= 2 && (trimmed[0] == '\'' || trimmed[0] == '"') && trimmed[len(trimmed)-1] == trimmed[0] {
+		// Quoted strings hold escape sequences, such as \n.
+		text, err := strconv.Unquote(doubleQuoted(trimmed))
+		if err != nil {
+			return templatePart{}, &JSONPathError{Expr: block, Offset: strings.Index(block, trimmed), Msg: "invalid string"}
+		}
+		return templatePart{text: text}, nil
+	}
+	path, err := CompileJSONPath(block)
+	if err != nil {
+		return templatePart{}, err
+	}
+	return templatePart{path: path}, nil
+}
+
+// doubleQuoted returns the double-quoted Go form of a single or double quoted string.
+func doubleQuoted(quoted string) string {
+	if quoted[0] == '"' {
+		return quoted
+	}
+	var sb strings.Builder
+	sb.WriteByte('"')
+	content := quoted[1 : len(quoted)-1]
+	for i := 0; i < len(content); i++ {
+		switch c := content[i]; {
+		case c == '\\' && i+1 < len(content) && content[i+1] == '\'':
+			sb.WriteByte('\'')
+			i++
+		case c == '\\' && i+1 < len(content):
+			sb.WriteString(content[i : i+2])
+			i++
+		case c == '"':
+			sb.WriteString(`\"`)
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	sb.WriteByte('"')
+	return sb.String()
+}
@@ -0,0 +1,195 @@
+// Copyright 2020 Lingfei Kong <colin404@foxmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonutils
+
+import (
+	"strings"
+	"testing"
+)
+
+const jsonPathTestDoc = `{
+	"store": {
+		"book": [
+			{"title": "a", "price": 8},
+			{"title": "b", "price": 12.5},
+			{"title": "c", "price": 9, "isbn": "x"},
+			{"title": "d", "price": 22}
+		],
+		"bicycle": {"price": 19.95}
+	},
+	"limit": 10
+}`
+
+func mustParse(t *testing.T, str string) JSONObject {
+	t.Helper()
+	obj, err := ParseString(str)
+	if err != nil {
+		t.Fatalf("parse %s: %v", str, err)
+	}
+	return obj
+}
+
+// joinNodes returns the nodes separated by commas, strings unquoted.
+func joinNodes(nodes []JSONObject) string {
+	strs := make([]string, len(nodes))
+	for i, node := range nodes {
+		if s, ok := node.(*JSONString); ok {
+			strs[i] = s.data
+		} else {
+			strs[i] = node.String()
+		}
+	}
+	return strings.Join(strs, ",")
+}
+
+func TestJSONPathFind(t *testing.T) {
+	doc := mustParse(t, jsonPathTestDoc)
+	cases := []struct {
+		name string
+		expr string
+		want string
+	}{
+		{"field", "$.limit", "10"},
+		{"root omitted", ".store.bicycle.price", "19.95"},
+		{"bracket name", "$['store']['bicycle']['price']", "19.95"},
+		{"index", "$.store.book[1].title", "b"},
+		{"negative index", "$.store.book[-1].title", "d"},
+		{"negative index out of range", "$.store.book[-5].title", ""},
+		{"wildcard", "$.store.book[*].title", "a,b,c,d"},
+		{"union", "$.store.book[0,-1].title", "a,d"},
+		{"slice", "$.store.book[1:3].title", "b,c"},
+		{"slice negative start", "$.store.book[-2:].title", "c,d"},
+		{"slice negative end", "$.store.book[:-2].title", "a,b"},
+		{"slice step", "$.store.book[::2].title", "a,c"},
+		{"slice negative step", "$.store.book[::-1].title", "d,c,b,a"},
+		{"slice negative bounds and step", "$.store.book[-1:0:-2].title", "d,b"},
+		{"slice out of range", "$.store.book[10:].title", ""},
+		{"filter comparison", "$.store.book[?(@.price < 10)].title", "a,c"},
+		{"filter int equals float", "$.store.book[?(@.price == 8.0)].title", "a"},
+		{"filter string", "$.store.book[?(@.title == 'b')].price", "12.5"},
+		{"filter existence", "$.store.book[?(@.isbn)].title", "c"},
+		{"filter negation", "$.store.book[?(!@.isbn)].title", "a,b,d"},
+		{"filter root path", "$.store.book[?(@.price > $.limit)].title", "b,d"},
+		{"filter and", "$.store.book[?(@.price > $.limit && @.title != 'd')].title", "b"},
+		{"filter or", "$.store.book[?(@.price < 9 || @.price >= 22)].title", "a,d"},
+		{"recursive descent", "$..price", "19.95,8,12.5,9,22"},
+		{"recursive descent wildcard", "$.store.bicycle..*", "19.95"},
+		{"recursive descent index", "$..book[0].title", "a"},
+		{"recursive descent filter", "$..[?(@.isbn)].title", "c"},
+		{"missing", "$.store.pen", ""},
+	}
+	for _, c := range cases {
+		nodes, err := QueryJSONPath(doc, c.expr)
+		if err != nil {
+			t.Errorf("%s: %s: %v", c.name, c.expr, err)
+			continue
+		}
+		if got := joinNodes(nodes); got != c.want {
+			t.Errorf("%s: %s: want %q got %q", c.name, c.expr, c.want, got)
+		}
+	}
+}
+
+func TestJSONPathFindOne(t *testing.T) {
+	doc := mustParse(t, jsonPathTestDoc)
+	path := MustCompileJSONPath("$.store.book[?(@.price > 10)].title")
+	node, err := path.FindOne(doc)
+	if err != nil || joinNodes([]JSONObject{node}) != "b" {
+		t.Errorf("want b got %v %v", node, err)
+	}
+	if _, err := MustCompileJSONPath("$.store.pen").FindOne(doc); err != ErrJsonDictKeyNotFound {
+		t.Errorf("want ErrJsonDictKeyNotFound got %v", err)
+	}
+}
+
+func TestJSONPathSyntaxError(t *testing.T) {
+	cases := []struct {
+		expr   string
+		offset int
+	}{
+		{"$.store[", 8},
+		{"$.store.book[?(@.price <)]", 24},
+		{"$.store]", 7},
+	}
+	for _, c := range cases {
+		_, err := CompileJSONPath(c.expr)
+		e, ok := err.(*JSONPathError)
+		if !ok {
+			t.Errorf("%s: want JSONPathError got %v", c.expr, err)
+			continue
+		}
+		if e.Offset != c.offset {
+			t.Errorf("%s: want offset %d got %d: %v", c.expr, c.offset, e.Offset, e)
+		}
+	}
+}
+
+func TestJSONPathPrinter(t *testing.T) {
+	doc := mustParse(t, jsonPathTestDoc)
+	cases := []struct {
+		name     string
+		template string
+		allow    bool
+		want     string
+		wantErr  bool
+	}{
+		{"path", "{.limit}", false, "10", false},
+		{"several nodes", "{.store.book[*].title}", false, "a b c d", false},
+		{"text and escapes", "{.store.book[0].title}{'\\t'}{.store.book[0].price}{\"\\n\"}", false, "a\t8\n", false},
+		{"literal text", "limit: {.limit}", false, "limit: 10", false},
+		{"braces in strings", "{'{}'}", false, "{}", false},
+		{"filter", "{.store.book[?(@.price > $.limit)].title}", false, "b d", false},
+		{"negative slice", "{.store.book[-2:].title}", false, "c d", false},
+		{"recursive descent", "{..isbn}", false, "x", false},
+		{"dict", "{.store.bicycle}", false, `{"price":19.95}`, false},
+		{"missing key", "{.store.pen}", false, "", true},
+		{"missing key allowed", "{.store.pen}", true, "", false},
+	}
+	for _, c := range cases {
+		printer, err := NewJSONPathPrinter(c.template)
+		if err != nil {
+			t.Errorf("%s: %v", c.name, err)
+			continue
+		}
+		printer.AllowMissingKeys(c.allow)
+		var sb strings.Builder
+		err = printer.PrintObj(doc, &sb)
+		if c.wantErr {
+			if err == nil {
+				t.Errorf("%s: want error got %q", c.name, sb.String())
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", c.name, err)
+			continue
+		}
+		if sb.String() != c.want {
+			t.Errorf("%s: want %q got %q", c.name, c.want, sb.String())
+		}
+	}
+}
+
+func TestJSONPathPrinterErrors(t *testing.T) {
+	for _, template := range []string{"{.limit", "{.store[}", "{'\\q'}"} {
+		if _, err := NewJSONPathPrinter(template); err == nil {
+			t.Errorf("%s: want error", template)
+		}
+	}
+
+	printer, err := NewJSONPathPrinterForOutput("jsonpath={.limit}")
+	if err != nil {
+		t.Fatalf("jsonpath={.limit}: %v", err)
+	}
+	var sb strings.Builder
+	if err := printer.PrintObj(mustParse(t, jsonPathTestDoc), &sb); err != nil || sb.String() != "10" {
+		t.Errorf("want 10 got %q %v", sb.String(), err)
+	}
+	for _, output := range []string{"json", "jsonpath="} {
+		if _, err := NewJSONPathPrinterForOutput(output); err == nil {
+			t.Errorf("%s: want error", output)
+		}
+	}
+}
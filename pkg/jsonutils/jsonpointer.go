@@ -0,0 +1,339 @@
+// Copyright 2020 Lingfei Kong <colin404@foxmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonutils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// JSONPointer is a parsed RFC 6901 JSON pointer: the unescaped reference tokens
+// of the path, from the root. The empty pointer refers to the whole document.
+type JSONPointer []string
+
+var (
+	pointerEscaper   = strings.NewReplacer("~", "~0", "/", "~1")
+	pointerUnescaper = strings.NewReplacer("~1", "/", "~0", "~")
+)
+
+// ParseJSONPointer parses a JSON pointer such as /items/0/name, in which ~1
+// stands for / and ~0 for ~ in the reference tokens.
+func ParseJSONPointer(pointer string) (JSONPointer, error) {
+	if pointer == "" {
+		return JSONPointer{}, nil
+	}
+	if pointer[0] != '/' {
+		return nil, &JSONPointerError{Pointer: pointer, Segment: -1, Err: ErrInvalidJsonPointer}
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		for j := 0; j < len(token); j++ {
+			if token[j] == '~' && (j+1 == len(token) || (token[j+1] != '0' && token[j+1] != '1')) {
+				return nil, &JSONPointerError{Pointer: pointer, Segment: i, Err: ErrInvalidJsonPointer}
+			}
+		}
+		tokens[i] = pointerUnescaper.Replace(token)
+	}
+	return JSONPointer(tokens), nil
+}
+
+// String returns the escaped form of the pointer.
+func (ptr JSONPointer) String() string {
+	var sb strings.Builder
+	for _, token := range ptr {
+		sb.WriteByte('/')
+		sb.WriteString(pointerEscaper.Replace(token))
+	}
+	return sb.String()
+}
+
+// JSONPointerError is returned when a JSON pointer is invalid or cannot be
+// followed in a document. Segment is the index of the reference token which
+// failed, or -1 if the whole pointer is invalid.
+type JSONPointerError struct {
+	Pointer string
+	Segment int
+	Err     error
+}
+
+func (e *JSONPointerError) Error() string {
+	if e.Segment < 0 {
+		return fmt.Sprintf("json pointer %q: %s", e.Pointer, e.Err)
+	}
+	return fmt.Sprintf("json pointer %q: %s at segment %d (%q)", e.Pointer, e.Err, e.Segment, e.failedPath())
+}
+
+// failedPath returns the prefix of the pointer up to the segment which failed.
+func (e *JSONPointerError) failedPath() string {
+	end := 0
+	for i := 0; i <= e.Segment; i++ {
+		next := strings.IndexByte(e.Pointer[end+1:], '/')
+		if next < 0 {
+			return e.Pointer
+		}
+		end += next + 1
+	}
+	return e.Pointer[:end]
+}
+
+// Unwrap returns the cause of the error, such as ErrJsonDictKeyNotFound.
+func (e *JSONPointerError) Unwrap() error {
+	return e.Err
+}
+
+// GetPointer returns the object pointer refers to in obj.
+func GetPointer(obj JSONObject, pointer string) (JSONObject, error) {
+	ptr, err := ParseJSONPointer(pointer)
+	if err != nil {
+		return nil, err
+	}
+	for i, token := range ptr {
+		if obj, err = pointerChild(obj, token); err != nil {
+			return nil, &JSONPointerError{Pointer: pointer, Segment: i, Err: err}
+		}
+	}
+	return obj, nil
+}
+
+// SetPointer sets the object pointer refers to in obj to value. A field is
+// added to a dict if missing; an array element is replaced, or appended when
+// the last token is - or the length of the array. When createParents is true,
+// missing intermediate containers are created: arrays when the next token is
+// - or 0, dicts otherwise. The root of obj cannot be replaced.
+func SetPointer(obj JSONObject, pointer string, value JSONObject, createParents bool) error {
+	ptr, err := ParseJSONPointer(pointer)
+	if err != nil {
+		return err
+	}
+	if len(ptr) == 0 {
+		return &JSONPointerError{Pointer: pointer, Segment: -1, Err: ErrUnsupported}
+	}
+
+	last := len(ptr) - 1
+	for i, token := range ptr[:last] {
+		child, err := pointerChild(obj, token)
+		if createParents && (err == ErrJsonDictKeyNotFound || err == ErrOutOfIndexRange) {
+			child = newPointerContainer(ptr[i+1])
+			err = setPointerChild(obj, token, child)
+		}
+		if err != nil {
+			return &JSONPointerError{Pointer: pointer, Segment: i, Err: err}
+		}
+		obj = child
+	}
+	if err := setPointerChild(obj, ptr[last], value); err != nil {
+		return &JSONPointerError{Pointer: pointer, Segment: last, Err: err}
+	}
+	return nil
+}
+
+// RemovePointer removes the object pointer refers to from obj. The root of obj
+// cannot be removed.
+func RemovePointer(obj JSONObject, pointer string) error {
+	ptr, err := ParseJSONPointer(pointer)
+	if err != nil {
+		return err
+	}
+	if len(ptr) == 0 {
+		return &JSONPointerError{Pointer: pointer, Segment: -1, Err: ErrUnsupported}
+	}
+
+	last := len(ptr) - 1
+	for i, token := range ptr[:last] {
+		if obj, err = pointerChild(obj, token); err != nil {
+			return &JSONPointerError{Pointer: pointer, Segment: i, Err: err}
+		}
+	}
+	if err := removePointerChild(obj, ptr[last]); err != nil {
+		return &JSONPointerError{Pointer: pointer, Segment: last, Err: err}
+	}
+	return nil
+}
+
+// The methods of the JSONObject interface are defined on every type, so that the
+// receiver is the object itself rather than the embedded JSONValue.
+
+func (this *JSONValue) GetPointer(pointer string) (JSONObject, error) {
+	return GetPointer(this, pointer)
+}
+
+func (this *JSONValue) SetPointer(pointer string, value JSONObject, createParents bool) error {
+	return SetPointer(this, pointer, value, createParents)
+}
+
+func (this *JSONValue) RemovePointer(pointer string) error {
+	return RemovePointer(this, pointer)
+}
+
+func (this *JSONBool) GetPointer(pointer string) (JSONObject, error) {
+	return GetPointer(this, pointer)
+}
+
+func (this *JSONBool) SetPointer(pointer string, value JSONObject, createParents bool) error {
+	return SetPointer(this, pointer, value, createParents)
+}
+
+func (this *JSONBool) RemovePointer(pointer string) error {
+	return RemovePointer(this, pointer)
+}
+
+func (this *JSONInt) GetPointer(pointer string) (JSONObject, error) {
+	return GetPointer(this, pointer)
+}
+
+func (this *JSONInt) SetPointer(pointer string, value JSONObject, createParents bool) error {
+	return SetPointer(this, pointer, value, createParents)
+}
+
+func (this *JSONInt) RemovePointer(pointer string) error {
+	return RemovePointer(this, pointer)
+}
+
+func (this *JSONFloat) GetPointer(pointer string) (JSONObject, error) {
+	return GetPointer(this, pointer)
+}
+
+func (this *JSONFloat) SetPointer(pointer string, value JSONObject, createParents bool) error {
+	return SetPointer(this, pointer, value, createParents)
+}
+
+func (this *JSONFloat) RemovePointer(pointer string) error {
+	return RemovePointer(this, pointer)
+}
+
+func (this *JSONString) GetPointer(pointer string) (JSONObject, error) {
+	return GetPointer(this, pointer)
+}
+
+func (this *JSONString) SetPointer(pointer string, value JSONObject, createParents bool) error {
+	return SetPointer(this, pointer, value, createParents)
+}
+
+func (this *JSONString) RemovePointer(pointer string) error {
+	return RemovePointer(this, pointer)
+}
+
+func (this *JSONDict) GetPointer(pointer string) (JSONObject, error) {
+	return GetPointer(this, pointer)
+}
+
+func (this *JSONDict) SetPointer(pointer string, value JSONObject, createParents bool) error {
+	return SetPointer(this, pointer, value, createParents)
+}
+
+func (this *JSONDict) RemovePointer(pointer string) error {
+	return RemovePointer(this, pointer)
+}
+
+func (this *JSONArray) GetPointer(pointer string) (JSONObject, error) {
+	return GetPointer(this, pointer)
+}
+
+func (this *JSONArray) SetPointer(pointer string, value JSONObject, createParents bool) error {
+	return SetPointer(this, pointer, value, createParents)
+}
+
+func (this *JSONArray) RemovePointer(pointer string) error {
+	return RemovePointer(this, pointer)
+}
+
+func pointerChild(obj JSONObject, token string) (JSONObject, error) {
+	switch o := obj.(type) {
+	case *JSONDict:
+		v, ok := o.data.Get(token)
+		if !ok {
+			return nil, ErrJsonDictKeyNotFound
+		}
+		return v.(JSONObject), nil
+	case *JSONArray:
+		i, err := pointerIndex(token, len(o.data))
+		if err != nil {
+			return nil, err
+		}
+		if i >= len(o.data) {
+			return nil, ErrOutOfIndexRange
+		}
+		return o.data[i], nil
+	default:
+		return nil, ErrNotJsonContainer
+	}
+}
+
+func setPointerChild(obj JSONObject, token string, value JSONObject) error {
+	switch o := obj.(type) {
+	case *JSONDict:
+		o.Set(token, value)
+		return nil
+	case *JSONArray:
+		i, err := pointerIndex(token, len(o.data))
+		if err != nil {
+			return err
+		}
+		switch {
+		case i < len(o.data):
+			o.data[i] = value
+		case i == len(o.data):
+			o.data = append(o.data, value)
+		default:
+			return ErrOutOfIndexRange
+		}
+		return nil
+	default:
+		return ErrNotJsonContainer
+	}
+}
+
+func removePointerChild(obj JSONObject, token string) error {
+	switch o := obj.(type) {
+	case *JSONDict:
+		if !o.Remove(token) {
+			return ErrJsonDictKeyNotFound
+		}
+		return nil
+	case *JSONArray:
+		i, err := pointerIndex(token, len(o.data))
+		if err != nil {
+			return err
+		}
+		if i >= len(o.data) {
+			return ErrOutOfIndexRange
+		}
+		o.data = append(o.data[:i:i], o.data[i+1:]...)
+		return nil
+	default:
+		return ErrNotJsonContainer
+	}
+}
+
+// pointerIndex returns the array index of token: a decimal number without
+// leading zeros, or - for the element after the last one.
+func pointerIndex(token string, length int) (int, error) {
+	if token == "-" {
+		return length, nil
+	}
+	if token == "" || (len(token) > 1 && token[0] == '0') {
+		return 0, ErrInvalidJsonPointer
+	}
+	for _, c := range token {
+		if c < '0' || c > '9' {
+			return 0, ErrInvalidJsonPointer
+		}
+	}
+	i, err := strconv.Atoi(token)
+	if err != nil {
+		return 0, ErrOutOfIndexRange
+	}
+	return i, nil
+}
+
+// newPointerContainer returns the container created for the token next, which
+// is an array index for - and 0.
+func newPointerContainer(next string) JSONObject {
+	if next == "-" || next == "0" {
+		return NewArray()
+	}
+	return NewDict()
+}
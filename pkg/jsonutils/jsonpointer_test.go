@@ -0,0 +1,190 @@
+// Copyright 2020 Lingfei Kong <colin404@foxmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonutils
+
+import (
+	"reflect"
+	"testing"
+)
+
+const jsonPointerTestDoc = `{
+	"a/b": 1,
+	"m~n": 2,
+	"~1": 3,
+	"": 4,
+	"items": [{"name": "x"}, {"name": "y"}, {"name": "z"}]
+}`
+
+// pointerErr returns the cause of a JSONPointerError.
+func pointerErr(err error) error {
+	if e, ok := err.(*JSONPointerError); ok {
+		return e.Err
+	}
+	return err
+}
+
+func TestParseJSONPointer(t *testing.T) {
+	cases := []struct {
+		pointer string
+		want    JSONPointer
+	}{
+		{"", JSONPointer{}},
+		{"/", JSONPointer{""}},
+		{"/a~1b", JSONPointer{"a/b"}},
+		{"/m~0n", JSONPointer{"m~n"}},
+		// ~01 is ~ followed by 1, not /.
+		{"/~01", JSONPointer{"~1"}},
+		{"/~10", JSONPointer{"/0"}},
+		{"/items/0/name", JSONPointer{"items", "0", "name"}},
+	}
+	for _, c := range cases {
+		ptr, err := ParseJSONPointer(c.pointer)
+		if err != nil {
+			t.Errorf("%s: %v", c.pointer, err)
+			continue
+		}
+		if !reflect.DeepEqual(ptr, c.want) {
+			t.Errorf("%s: want %q got %q", c.pointer, c.want, ptr)
+		}
+		if ptr.String() != c.pointer {
+			t.Errorf("%s: escaped back to %s", c.pointer, ptr.String())
+		}
+	}
+
+	for _, pointer := range []string{"a", "/~", "/~2", "/a~"} {
+		if _, err := ParseJSONPointer(pointer); pointerErr(err) != ErrInvalidJsonPointer {
+			t.Errorf("%s: want ErrInvalidJsonPointer got %v", pointer, err)
+		}
+	}
+}
+
+func TestGetPointer(t *testing.T) {
+	doc := mustParse(t, jsonPointerTestDoc)
+	cases := []struct {
+		pointer string
+		want    string
+		err     error
+	}{
+		{"", doc.String(), nil},
+		{"/a~1b", "1", nil},
+		{"/m~0n", "2", nil},
+		{"/~01", "3", nil},
+		{"/", "4", nil},
+		{"/items/1/name", `"y"`, nil},
+		{"/a/b", "", ErrJsonDictKeyNotFound},
+		{"/items/3", "", ErrOutOfIndexRange},
+		{"/items/-", "", ErrOutOfIndexRange},
+		{"/items/01", "", ErrInvalidJsonPointer},
+		{"/items/x", "", ErrInvalidJsonPointer},
+		{"/a~1b/c", "", ErrNotJsonContainer},
+	}
+	for _, c := range cases {
+		obj, err := GetPointer(doc, c.pointer)
+		if c.err != nil {
+			if pointerErr(err) != c.err {
+				t.Errorf("%s: want %v got %v", c.pointer, c.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", c.pointer, err)
+			continue
+		}
+		if obj.String() != c.want {
+			t.Errorf("%s: want %s got %s", c.pointer, c.want, obj.String())
+		}
+	}
+
+	_, err := GetPointer(doc, "/items/1/age")
+	if e, ok := err.(*JSONPointerError); !ok || e.Segment != 2 {
+		t.Errorf("want an error at segment 2 got %v", err)
+	}
+}
+
+func TestSetPointer(t *testing.T) {
+	cases := []struct {
+		name          string
+		pointer       string
+		value         string
+		createParents bool
+		want          string
+		err           error
+	}{
+		{"escaped key", "/a~1b", "5", false, `{"a/b":5,"items":[1,2]}`, nil},
+		{"tilde key", "/m~0n", "5", false, `{"a/b":1,"items":[1,2],"m~n":5}`, nil},
+		{"replace element", "/items/0", "5", false, `{"a/b":1,"items":[5,2]}`, nil},
+		{"append with -", "/items/-", "5", false, `{"a/b":1,"items":[1,2,5]}`, nil},
+		{"append with length", "/items/2", "5", false, `{"a/b":1,"items":[1,2,5]}`, nil},
+		{"index past end", "/items/3", "5", false, "", ErrOutOfIndexRange},
+		{"missing parent", "/x/y", "5", false, "", ErrJsonDictKeyNotFound},
+		{"created dict", "/x/y", "5", true, `{"a/b":1,"items":[1,2],"x":{"y":5}}`, nil},
+		{"created array", "/x/-", "5", true, `{"a/b":1,"items":[1,2],"x":[5]}`, nil},
+		{"root", "", "5", false, "", ErrUnsupported},
+	}
+	for _, c := range cases {
+		doc := mustParse(t, `{"a/b":1,"items":[1,2]}`)
+		err := SetPointer(doc, c.pointer, mustParse(t, c.value), c.createParents)
+		if c.err != nil {
+			if pointerErr(err) != c.err {
+				t.Errorf("%s: want %v got %v", c.name, c.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", c.name, err)
+			continue
+		}
+		if doc.String() != c.want {
+			t.Errorf("%s: want %s got %s", c.name, c.want, doc.String())
+		}
+	}
+}
+
+func TestRemovePointer(t *testing.T) {
+	cases := []struct {
+		name    string
+		pointer string
+		want    string
+		err     error
+	}{
+		{"escaped key", "/a~1b", `{"items":[1,2,3],"m~n":2}`, nil},
+		{"tilde key", "/m~0n", `{"a/b":1,"items":[1,2,3]}`, nil},
+		{"first element", "/items/0", `{"a/b":1,"items":[2,3],"m~n":2}`, nil},
+		{"middle element", "/items/1", `{"a/b":1,"items":[1,3],"m~n":2}`, nil},
+		{"last element", "/items/2", `{"a/b":1,"items":[1,2],"m~n":2}`, nil},
+		{"append index", "/items/-", "", ErrOutOfIndexRange},
+		{"index past end", "/items/3", "", ErrOutOfIndexRange},
+		{"missing key", "/a~0b", "", ErrJsonDictKeyNotFound},
+		{"root", "", "", ErrUnsupported},
+	}
+	for _, c := range cases {
+		doc := mustParse(t, `{"a/b":1,"m~n":2,"items":[1,2,3]}`)
+		err := RemovePointer(doc, c.pointer)
+		if c.err != nil {
+			if pointerErr(err) != c.err {
+				t.Errorf("%s: want %v got %v", c.name, c.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", c.name, err)
+			continue
+		}
+		if doc.String() != c.want {
+			t.Errorf("%s: want %s got %s", c.name, c.want, doc.String())
+		}
+	}
+}
+
+func TestRemovePointerCopiesElements(t *testing.T) {
+	arr := mustParse(t, `[1,2,3]`).(*JSONArray)
+	before := arr.data
+	if err := arr.RemovePointer("/0"); err != nil {
+		t.Fatal(err)
+	}
+	if arr.String() != "[2,3]" || before[0].String() != "1" {
+		t.Errorf("want [2,3] leaving the old backing array got %s and %v", arr.String(), before)
+	}
+}
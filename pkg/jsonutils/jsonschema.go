@@ -0,0 +1,728 @@
+// Copyright 2020 Lingfei Kong <colin404@foxmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonutils
+
+import (
+	"fmt"
+	"io/ioutil"
+	"math"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"yunion.io/x/pkg/errors"
+	"yunion.io/x/pkg/sortedmap"
+)
+
+// SchemaErrorType tells why a value does not match a JSON schema. The values
+// are those of field.ErrorType in the validation package of component-base, so
+// that a SchemaError converts to a field.Error field by field, through
+// field.FromExternalErrors.
+type SchemaErrorType string
+
+const (
+	SchemaErrorRequired     SchemaErrorType = "FieldValueRequired"
+	SchemaErrorInvalid      SchemaErrorType = "FieldValueInvalid"
+	SchemaErrorNotSupported SchemaErrorType = "FieldValueNotSupported"
+	SchemaErrorForbidden    SchemaErrorType = "FieldValueForbidden"
+)
+
+func (t SchemaErrorType) String() string {
+	switch t {
+	case SchemaErrorRequired:
+		return "Required value"
+	case SchemaErrorInvalid:
+		return "Invalid value"
+	case SchemaErrorNotSupported:
+		return "Unsupported value"
+	case SchemaErrorForbidden:
+		return "Forbidden"
+	default:
+		return string(t)
+	}
+}
+
+// SchemaError is a value of a document which does not match a JSON schema.
+// Field is the JSONPath of the value, such as $.spec.ports[0].name.
+type SchemaError struct {
+	Type     SchemaErrorType
+	Field    string
+	BadValue JSONObject
+	Detail   string
+}
+
+func (e *SchemaError) Error() string {
+	s := fmt.Sprintf("%s: %s", e.Field, e.Type)
+	if e.BadValue != nil && e.Type != SchemaErrorRequired && e.Type != SchemaErrorForbidden {
+		s += ": " + e.BadValue.String()
+	}
+	if e.Detail != "" {
+		s += ": " + e.Detail
+	}
+	return s
+}
+
+// FieldErrorType returns the type of the error as a field.ErrorType value.
+func (e *SchemaError) FieldErrorType() string {
+	return string(e.Type)
+}
+
+// FieldPath returns the path of the value in the form of a field.Path: Field
+// without its leading $, such as spec.ports[0].name.
+func (e *SchemaError) FieldPath() string {
+	return strings.TrimPrefix(strings.TrimPrefix(e.Field, "$"), ".")
+}
+
+// FieldBadValue returns the invalid value as a Go value, or nil.
+func (e *SchemaError) FieldBadValue() interface{} {
+	if e.BadValue == nil {
+		return nil
+	}
+	return e.BadValue.Interface()
+}
+
+// FieldDetail returns the detail of the error.
+func (e *SchemaError) FieldDetail() string {
+	return e.Detail
+}
+
+// SchemaErrorList holds the errors of a document, in the order of the document
+// and the schema.
+type SchemaErrorList []*SchemaError
+
+// ToAggregate returns the errors as an aggregate, or nil if there are none.
+func (list SchemaErrorList) ToAggregate() errors.Aggregate {
+	if len(list) == 0 {
+		return nil
+	}
+	return errors.NewAggregate(list.Errors())
+}
+
+// Errors returns the errors as a slice of error, such as the one taken by
+// field.FromExternalErrors to build a field.ErrorList.
+func (list SchemaErrorList) Errors() []error {
+	if len(list) == 0 {
+		return nil
+	}
+	errs := make([]error, len(list))
+	for i := range list {
+		errs[i] = list[i]
+	}
+	return errs
+}
+
+// JSONSchema is a compiled JSON schema. It supports the following keywords of
+// draft 2020-12: type, enum, const, properties, additionalProperties, required,
+// pattern, minLength, maxLength, minimum, maximum, exclusiveMinimum,
+// exclusiveMaximum, items, minItems, maxItems, allOf, anyOf, oneOf, not and
+// $ref to a JSON pointer in the same document, such as #/$defs/port. Other
+// keywords are ignored.
+type JSONSchema struct {
+	root *schemaNode
+}
+
+type schemaNode struct {
+	// always is the result of a true or false schema.
+	always *bool
+	ref    *schemaNode
+
+	types []string
+	enum  []JSONObject
+
+	properties           map[string]*schemaNode
+	propertyNames        []string
+	additionalProperties *schemaNode
+	required             []string
+
+	pattern   *regexp.Regexp
+	minLength *int
+	maxLength *int
+
+	minimum          *float64
+	maximum          *float64
+	exclusiveMinimum *float64
+	exclusiveMaximum *float64
+
+	items    *schemaNode
+	minItems *int
+	maxItems *int
+
+	allOf []*schemaNode
+	anyOf []*schemaNode
+	oneOf []*schemaNode
+	not   *schemaNode
+}
+
+var schemaTypes = map[string]bool{
+	"null": true, "boolean": true, "object": true, "array": true, "number": true, "integer": true, "string": true,
+}
+
+// CompileJSONSchema compiles schema, a JSONDict or a boolean.
+func CompileJSONSchema(schema JSONObject) (*JSONSchema, error) {
+	c := &schemaCompiler{doc: schema, nodes: map[string]*schemaNode{}}
+	root, err := c.compile(schema, JSONPointer{})
+	if err != nil {
+		return nil, err
+	}
+	for len(c.refs) > 0 {
+		ref := c.refs[0]
+		c.refs = c.refs[1:]
+		if err := c.resolve(ref); err != nil {
+			return nil, err
+		}
+	}
+	if err := c.checkCycles(); err != nil {
+		return nil, err
+	}
+	return &JSONSchema{root: root}, nil
+}
+
+// ParseJSONSchema parses and compiles a JSON schema written in YAML or JSON.
+func ParseJSONSchema(str string) (*JSONSchema, error) {
+	schema, err := ParseYAML(str)
+	if err != nil {
+		return nil, errors.Wrap(err, "ParseYAML")
+	}
+	return CompileJSONSchema(schema)
+}
+
+// LoadJSONSchema reads and compiles the JSON schema of a YAML or JSON file.
+func LoadJSONSchema(filename string) (*JSONSchema, error) {
+	content, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	schema, err := ParseJSONSchema(string(content))
+	if err != nil {
+		return nil, errors.Wrapf(err, "schema %s", filename)
+	}
+	return schema, nil
+}
+
+// Validate validates obj against the schema. The errors convert to a
+// field.ErrorList of component-base with field.FromExternalErrors(errs.Errors()).
+func (schema *JSONSchema) Validate(obj JSONObject) SchemaErrorList {
+	return schema.root.validate(obj, "$")
+}
+
+// ValidateErrors validates obj, marshaled first unless it is a JSONObject, and
+// returns the errors as a slice of error. It takes no type of this package, so
+// that the schema is usable by packages which cannot depend on this one, such as
+// validation.ValidateJSONSchema of component-base.
+func (schema *JSONSchema) ValidateErrors(obj interface{}) []error {
+	doc, ok := obj.(JSONObject)
+	if !ok {
+		doc = Marshal(obj)
+	}
+	return schema.Validate(doc).Errors()
+}
+
+type schemaRef struct {
+	node    *schemaNode
+	pointer string
+	at      JSONPointer
+}
+
+type schemaCompiler struct {
+	doc   JSONObject
+	nodes map[string]*schemaNode
+	refs  []schemaRef
+}
+
+func (c *schemaCompiler) errorf(at JSONPointer, format string, args ...interface{}) error {
+	return errors.Wrapf(ErrInvalidJsonSchema, "%s: %s", at.String(), fmt.Sprintf(format, args...))
+}
+
+func (c *schemaCompiler) compile(obj JSONObject, at JSONPointer) (*schemaNode, error) {
+	if node, ok := c.nodes[at.String()]; ok {
+		return node, nil
+	}
+	node := &schemaNode{}
+	c.nodes[at.String()] = node
+
+	if b, ok := obj.(*JSONBool); ok {
+		node.always = &b.data
+		return node, nil
+	}
+	dict, ok := obj.(*JSONDict)
+	if !ok {
+		return nil, c.errorf(at, "schema must be an object or a boolean")
+	}
+
+	for iter := sortedmap.NewIterator(dict.data); iter.HasMore(); iter.Next() {
+		keyword, v := iter.Get()
+		if err := c.compileKeyword(node, keyword, v.(JSONObject), childPointer(at, keyword)); err != nil {
+			return nil, err
+		}
+	}
+	return node, nil
+}
+
+func (c *schemaCompiler) compileKeyword(node *schemaNode, keyword string, v JSONObject, at JSONPointer) error {
+	var err error
+	switch keyword {
+	case "$ref":
+		s, ok := v.(*JSONString)
+		if !ok {
+			return c.errorf(at, "must be a string")
+		}
+		if !strings.HasPrefix(s.data, "#") {
+			return c.errorf(at, "only references within the document are supported, got %q", s.data)
+		}
+		pointer, err := url.PathUnescape(s.data[1:])
+		if err != nil {
+			return c.errorf(at, "invalid reference %q", s.data)
+		}
+		c.refs = append(c.refs, schemaRef{node: node, pointer: pointer, at: at})
+	case "type":
+		node.types, err = c.compileTypes(v, at)
+	case "enum":
+		arr, ok := v.(*JSONArray)
+		if !ok {
+			return c.errorf(at, "must be an array")
+		}
+		node.enum = arr.data
+	case "const":
+		node.enum = []JSONObject{v}
+	case "properties":
+		dict, ok := v.(*JSONDict)
+		if !ok {
+			return c.errorf(at, "must be an object")
+		}
+		node.properties = map[string]*schemaNode{}
+		for iter := sortedmap.NewIterator(dict.data); iter.HasMore(); iter.Next() {
+			name, prop := iter.Get()
+			if node.properties[name], err = c.compile(prop.(JSONObject), childPointer(at, name)); err != nil {
+				return err
+			}
+			node.propertyNames = append(node.propertyNames, name)
+		}
+	case "additionalProperties":
+		node.additionalProperties, err = c.compile(v, at)
+	case "required":
+		arr, ok := v.(*JSONArray)
+		if !ok {
+			return c.errorf(at, "must be an array of strings")
+		}
+		for _, name := range arr.data {
+			s, ok := name.(*JSONString)
+			if !ok {
+				return c.errorf(at, "must be an array of strings")
+			}
+			node.required = append(node.required, s.data)
+		}
+	case "pattern":
+		s, ok := v.(*JSONString)
+		if !ok {
+			return c.errorf(at, "must be a string")
+		}
+		if node.pattern, err = regexp.Compile(s.data); err != nil {
+			return c.errorf(at, "%v", err)
+		}
+	case "minLength":
+		node.minLength, err = c.compileCount(v, at)
+	case "maxLength":
+		node.maxLength, err = c.compileCount(v, at)
+	case "minItems":
+		node.minItems, err = c.compileCount(v, at)
+	case "maxItems":
+		node.maxItems, err = c.compileCount(v, at)
+	case "minimum":
+		node.minimum, err = c.compileNumber(v, at)
+	case "maximum":
+		node.maximum, err = c.compileNumber(v, at)
+	case "exclusiveMinimum":
+		node.exclusiveMinimum, err = c.compileNumber(v, at)
+	case "exclusiveMaximum":
+		node.exclusiveMaximum, err = c.compileNumber(v, at)
+	case "items":
+		node.items, err = c.compile(v, at)
+	case "allOf":
+		node.allOf, err = c.compileList(v, at)
+	case "anyOf":
+		node.anyOf, err = c.compileList(v, at)
+	case "oneOf":
+		node.oneOf, err = c.compileList(v, at)
+	case "not":
+		node.not, err = c.compile(v, at)
+	}
+	return err
+}
+
+func (c *schemaCompiler) compileTypes(v JSONObject, at JSONPointer) ([]string, error) {
+	var names []JSONObject
+	switch o := v.(type) {
+	case *JSONString:
+		names = []JSONObject{o}
+	case *JSONArray:
+		names = o.data
+	default:
+		return nil, c.errorf(at, "must be a string or an array of strings")
+	}
+	types := make([]string, 0, len(names))
+	for _, name := range names {
+		s, ok := name.(*JSONString)
+		if !ok || !schemaTypes[s.data] {
+			return nil, c.errorf(at, "unknown type %s", name)
+		}
+		types = append(types, s.data)
+	}
+	return types, nil
+}
+
+func (c *schemaCompiler) compileCount(v JSONObject, at JSONPointer) (*int, error) {
+	f, ok := jsonNumber(v)
+	if !ok || f < 0 || f != math.Trunc(f) {
+		return nil, c.errorf(at, "must be a non-negative integer")
+	}
+	n := int(f)
+	return &n, nil
+}
+
+func (c *schemaCompiler) compileNumber(v JSONObject, at JSONPointer) (*float64, error) {
+	f, ok := jsonNumber(v)
+	if !ok {
+		return nil, c.errorf(at, "must be a number")
+	}
+	return &f, nil
+}
+
+func (c *schemaCompiler) compileList(v JSONObject, at JSONPointer) ([]*schemaNode, error) {
+	arr, ok := v.(*JSONArray)
+	if !ok || len(arr.data) == 0 {
+		return nil, c.errorf(at, "must be a non-empty array of schemas")
+	}
+	nodes := make([]*schemaNode, len(arr.data))
+	for i, elem := range arr.data {
+		var err error
+		if nodes[i], err = c.compile(elem, indexPointer(at, i)); err != nil {
+			return nil, err
+		}
+	}
+	return nodes, nil
+}
+
+// resolve compiles the schema a reference points to, unless it is compiled
+// already, which is the case of recursive schemas.
+func (c *schemaCompiler) resolve(ref schemaRef) error {
+	ptr, err := ParseJSONPointer(ref.pointer)
+	if err != nil {
+		return c.errorf(ref.at, "invalid reference %q", "#"+ref.pointer)
+	}
+	target, err := GetPointer(c.doc, ptr.String())
+	if err != nil {
+		return c.errorf(ref.at, "unresolved reference %q", "#"+ref.pointer)
+	}
+	if ref.node.ref, err = c.compile(target, ptr); err != nil {
+		return err
+	}
+	return nil
+}
+
+// checkCycles fails on the references which lead back to their own schema
+// without descending into the document, as in {"$ref": "#"} or in a
+// {"allOf": [{"$ref": "#"}]} root, which would be validated forever. Recursive
+// schemas descending through properties or items are allowed.
+func (c *schemaCompiler) checkCycles() error {
+	pointers := make([]string, 0, len(c.nodes))
+	for pointer := range c.nodes {
+		pointers = append(pointers, pointer)
+	}
+	sort.Strings(pointers)
+
+	visiting := map[*schemaNode]bool{}
+	done := map[*schemaNode]bool{}
+	var inCycle func(node *schemaNode) bool
+	inCycle = func(node *schemaNode) bool {
+		if visiting[node] {
+			return true
+		}
+		if done[node] {
+			return false
+		}
+		visiting[node] = true
+		for _, sub := range node.inPlaceSchemas() {
+			if inCycle(sub) {
+				return true
+			}
+		}
+		delete(visiting, node)
+		done[node] = true
+		return false
+	}
+	for _, pointer := range pointers {
+		if inCycle(c.nodes[pointer]) {
+			return errors.Wrapf(ErrInvalidJsonSchema, "%s: reference cycle which does not descend into the document", pointer)
+		}
+	}
+	return nil
+}
+
+// inPlaceSchemas returns the subschemas validating the same value as node.
+func (node *schemaNode) inPlaceSchemas() []*schemaNode {
+	var nodes []*schemaNode
+	if node.ref != nil {
+		nodes = append(nodes, node.ref)
+	}
+	nodes = append(nodes, node.allOf...)
+	nodes = append(nodes, node.anyOf...)
+	nodes = append(nodes, node.oneOf...)
+	if node.not != nil {
+		nodes = append(nodes, node.not)
+	}
+	return nodes
+}
+
+func (node *schemaNode) validate(obj JSONObject, path string) SchemaErrorList {
+	if node.always != nil {
+		if *node.always {
+			return nil
+		}
+		return SchemaErrorList{{Type: SchemaErrorForbidden, Field: path, Detail: "no value is allowed"}}
+	}
+
+	var errs SchemaErrorList
+	if node.ref != nil {
+		errs = append(errs, node.ref.validate(obj, path)...)
+	}
+	if len(node.types) > 0 && !matchesSchemaTypes(obj, node.types) {
+		detail := fmt.Sprintf("must be of type %s", strings.Join(node.types, " or "))
+		// The other keywords would only repeat the mismatch.
+		return append(errs, &SchemaError{Type: SchemaErrorInvalid, Field: path, BadValue: obj, Detail: detail})
+	}
+	if node.enum != nil {
+		errs = append(errs, node.validateEnum(obj, path)...)
+	}
+
+	switch o := obj.(type) {
+	case *JSONDict:
+		errs = append(errs, node.validateDict(o, path)...)
+	case *JSONArray:
+		errs = append(errs, node.validateArray(o, path)...)
+	case *JSONString:
+		errs = append(errs, node.validateString(o, path)...)
+	case *JSONInt, *JSONFloat:
+		errs = append(errs, node.validateNumber(obj, path)...)
+	}
+
+	for _, sub := range node.allOf {
+		errs = append(errs, sub.validate(obj, path)...)
+	}
+	if node.anyOf != nil && countMatches(node.anyOf, obj, path) == 0 {
+		errs = append(errs, &SchemaError{
+			Type:     SchemaErrorInvalid,
+			Field:    path,
+			BadValue: obj,
+			Detail:   "must match at least one schema of anyOf",
+		})
+	}
+	if node.oneOf != nil {
+		if n := countMatches(node.oneOf, obj, path); n != 1 {
+			errs = append(errs, &SchemaError{
+				Type:     SchemaErrorInvalid,
+				Field:    path,
+				BadValue: obj,
+				Detail:   fmt.Sprintf("must match exactly one schema of oneOf, matches %d", n),
+			})
+		}
+	}
+	if node.not != nil && len(node.not.validate(obj, path)) == 0 {
+		errs = append(errs, &SchemaError{
+			Type:     SchemaErrorInvalid,
+			Field:    path,
+			BadValue: obj,
+			Detail:   "must not match the schema of not",
+		})
+	}
+	return errs
+}
+
+func (node *schemaNode) validateEnum(obj JSONObject, path string) SchemaErrorList {
+	values := make([]string, len(node.enum))
+	for i, v := range node.enum {
+		if jsonValueEquals(obj, v) {
+			return nil
+		}
+		values[i] = v.String()
+	}
+	detail := "supported values: " + strings.Join(values, ", ")
+	return SchemaErrorList{{Type: SchemaErrorNotSupported, Field: path, BadValue: obj, Detail: detail}}
+}
+
+func (node *schemaNode) validateDict(dict *JSONDict, path string) SchemaErrorList {
+	var errs SchemaErrorList
+	for _, name := range node.required {
+		if _, ok := dict.data.Get(name); !ok {
+			errs = append(errs, &SchemaError{Type: SchemaErrorRequired, Field: schemaChildPath(path, name)})
+		}
+	}
+	for _, name := range node.propertyNames {
+		if v, ok := dict.data.Get(name); ok {
+			errs = append(errs, node.properties[name].validate(v.(JSONObject), schemaChildPath(path, name))...)
+		}
+	}
+	if node.additionalProperties != nil {
+		for iter := sortedmap.NewIterator(dict.data); iter.HasMore(); iter.Next() {
+			name, v := iter.Get()
+			if _, ok := node.properties[name]; ok {
+				continue
+			}
+			additional := node.additionalProperties
+			if additional.always != nil && !*additional.always {
+				errs = append(errs, &SchemaError{
+					Type:   SchemaErrorForbidden,
+					Field:  schemaChildPath(path, name),
+					Detail: "additional properties are not allowed",
+				})
+				continue
+			}
+			errs = append(errs, additional.validate(v.(JSONObject), schemaChildPath(path, name))...)
+		}
+	}
+	return errs
+}
+
+func (node *schemaNode) validateArray(arr *JSONArray, path string) SchemaErrorList {
+	var errs SchemaErrorList
+	if node.minItems != nil && len(arr.data) < *node.minItems {
+		errs = append(errs, &SchemaError{
+			Type:     SchemaErrorInvalid,
+			Field:    path,
+			BadValue: arr,
+			Detail:   fmt.Sprintf("must have at least %d items", *node.minItems),
+		})
+	}
+	if node.maxItems != nil && len(arr.data) > *node.maxItems {
+		errs = append(errs, &SchemaError{
+			Type:     SchemaErrorInvalid,
+			Field:    path,
+			BadValue: arr,
+			Detail:   fmt.Sprintf("must have at most %d items", *node.maxItems),
+		})
+	}
+	if node.items != nil {
+		for i, elem := range arr.data {
+			errs = append(errs, node.items.validate(elem, fmt.Sprintf("%s[%d]", path, i))...)
+		}
+	}
+	return errs
+}
+
+func (node *schemaNode) validateString(s *JSONString, path string) SchemaErrorList {
+	var errs SchemaErrorList
+	length := utf8.RuneCountInString(s.data)
+	if node.minLength != nil && length < *node.minLength {
+		errs = append(errs, &SchemaError{
+			Type:     SchemaErrorInvalid,
+			Field:    path,
+			BadValue: s,
+			Detail:   fmt.Sprintf("must be at least %d characters", *node.minLength),
+		})
+	}
+	if node.maxLength != nil && length > *node.maxLength {
+		errs = append(errs, &SchemaError{
+			Type:     SchemaErrorInvalid,
+			Field:    path,
+			BadValue: s,
+			Detail:   fmt.Sprintf("must be at most %d characters", *node.maxLength),
+		})
+	}
+	if node.pattern != nil && !node.pattern.MatchString(s.data) {
+		errs = append(errs, &SchemaError{
+			Type:     SchemaErrorInvalid,
+			Field:    path,
+			BadValue: s,
+			Detail:   fmt.Sprintf("must match the pattern %q", node.pattern.String()),
+		})
+	}
+	return errs
+}
+
+func (node *schemaNode) validateNumber(obj JSONObject, path string) SchemaErrorList {
+	f, _ := jsonNumber(obj)
+	bounds := []struct {
+		bound  *float64
+		failed func(f, bound float64) bool
+		detail string
+	}{
+		{node.minimum, func(f, bound float64) bool { return f < bound }, "must be greater than or equal to"},
+		{node.maximum, func(f, bound float64) bool { return f > bound }, "must be less than or equal to"},
+		{node.exclusiveMinimum, func(f, bound float64) bool { return f <= bound }, "must be greater than"},
+		{node.exclusiveMaximum, func(f, bound float64) bool { return f >= bound }, "must be less than"},
+	}
+	var errs SchemaErrorList
+	for _, b := range bounds {
+		if b.bound != nil && b.failed(f, *b.bound) {
+			errs = append(errs, &SchemaError{
+				Type:     SchemaErrorInvalid,
+				Field:    path,
+				BadValue: obj,
+				Detail:   b.detail + " " + strconv.FormatFloat(*b.bound, 'f', -1, 64),
+			})
+		}
+	}
+	return errs
+}
+
+func matchesSchemaTypes(obj JSONObject, types []string) bool {
+	for _, t := range types {
+		switch t {
+		case "null":
+			if obj == JSONNull {
+				return true
+			}
+		case "boolean":
+			if _, ok := obj.(*JSONBool); ok {
+				return true
+			}
+		case "object":
+			if _, ok := obj.(*JSONDict); ok {
+				return true
+			}
+		case "array":
+			if _, ok := obj.(*JSONArray); ok {
+				return true
+			}
+		case "string":
+			if _, ok := obj.(*JSONString); ok {
+				return true
+			}
+		case "number":
+			if _, ok := jsonNumber(obj); ok {
+				return true
+			}
+		case "integer":
+			// A float holding an integer, such as 1.0, is an integer.
+			if f, ok := jsonNumber(obj); ok && f == math.Trunc(f) && !math.IsInf(f, 0) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func countMatches(nodes []*schemaNode, obj JSONObject, path string) int {
+	n := 0
+	for _, node := range nodes {
+		if len(node.validate(obj, path)) == 0 {
+			n++
+		}
+	}
+	return n
+}
+
+var schemaPathName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// schemaChildPath returns the JSONPath of the field name of the object at path,
+// in bracket notation unless name is an identifier.
+func schemaChildPath(path, name string) string {
+	if schemaPathName.MatchString(name) {
+		return path + "." + name
+	}
+	return path + "['" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(name) + "']"
+}
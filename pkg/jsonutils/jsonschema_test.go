@@ -0,0 +1,276 @@
+// Copyright 2020 Lingfei Kong <colin404@foxmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jsonutils
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// schemaErrorFields returns the errors as "field type" strings.
+func schemaErrorFields(errs SchemaErrorList) []string {
+	var fields []string
+	for _, e := range errs {
+		fields = append(fields, e.Field+" "+string(e.Type))
+	}
+	return fields
+}
+
+func TestJSONSchemaKeywords(t *testing.T) {
+	const (
+		required     = " " + string(SchemaErrorRequired)
+		invalid      = " " + string(SchemaErrorInvalid)
+		notSupported = " " + string(SchemaErrorNotSupported)
+		forbidden    = " " + string(SchemaErrorForbidden)
+	)
+	cases := []struct {
+		name   string
+		schema string
+		doc    string
+		want   []string
+	}{
+		{"true", `true`, `{"a":1}`, nil},
+		{"false", `false`, `1`, []string{"$" + forbidden}},
+		{"type", `{"type":"string"}`, `1`, []string{"$" + invalid}},
+		{"type list", `{"type":["string","null"]}`, `null`, nil},
+		{"integer", `{"type":"integer"}`, `1.0`, nil},
+		{"not integer", `{"type":"integer"}`, `1.5`, []string{"$" + invalid}},
+		{"number", `{"type":"number"}`, `1`, nil},
+		{"boolean", `{"type":"boolean"}`, `"true"`, []string{"$" + invalid}},
+		{"type stops other keywords", `{"type":"string","minimum":3}`, `1`, []string{"$" + invalid}},
+		{"enum", `{"enum":["a",1]}`, `1.0`, nil},
+		{"enum mismatch", `{"enum":["a",1]}`, `"b"`, []string{"$" + notSupported}},
+		{"const", `{"const":{"a":[1]}}`, `{"a":[1.0]}`, nil},
+		{"const mismatch", `{"const":"a"}`, `"b"`, []string{"$" + notSupported}},
+		{
+			"properties",
+			`{"properties":{"name":{"type":"string"},"port":{"type":"integer"}}}`,
+			`{"name":1,"port":80,"other":true}`,
+			[]string{"$.name" + invalid},
+		},
+		{"required", `{"required":["name","a b"]}`, `{}`, []string{"$.name" + required, "$['a b']" + required}},
+		{
+			"additionalProperties false",
+			`{"properties":{"a":true},"additionalProperties":false}`,
+			`{"a":1,"b":2}`,
+			[]string{"$.b" + forbidden},
+		},
+		{
+			"additionalProperties schema",
+			`{"properties":{"a":true},"additionalProperties":{"type":"string"}}`,
+			`{"a":1,"b":2,"c":"d"}`,
+			[]string{"$.b" + invalid},
+		},
+		{"pattern", `{"pattern":"^[a-z]+$"}`, `"abc1"`, []string{"$" + invalid}},
+		{"minLength", `{"minLength":2}`, `"é"`, []string{"$" + invalid}},
+		{"maxLength counts runes", `{"maxLength":2}`, `"éé"`, nil},
+		{"maxLength", `{"maxLength":2}`, `"abc"`, []string{"$" + invalid}},
+		{"minimum", `{"minimum":1}`, `1`, nil},
+		{"minimum failed", `{"minimum":1}`, `0.5`, []string{"$" + invalid}},
+		{"maximum", `{"maximum":1}`, `2`, []string{"$" + invalid}},
+		{"exclusiveMinimum", `{"exclusiveMinimum":1}`, `1`, []string{"$" + invalid}},
+		{"exclusiveMaximum", `{"exclusiveMaximum":1}`, `1.0`, []string{"$" + invalid}},
+		{"bounds ignore other types", `{"minimum":1,"minLength":2,"minItems":1}`, `{}`, nil},
+		{"items", `{"items":{"type":"integer"}}`, `[1,"a",2,true]`, []string{"$[1]" + invalid, "$[3]" + invalid}},
+		{"minItems", `{"minItems":2}`, `[1]`, []string{"$" + invalid}},
+		{"maxItems", `{"maxItems":1}`, `[1,2]`, []string{"$" + invalid}},
+		{"allOf", `{"allOf":[{"minimum":1},{"maximum":2}]}`, `3`, []string{"$" + invalid}},
+		{"anyOf", `{"anyOf":[{"type":"string"},{"type":"integer"}]}`, `1`, nil},
+		{"anyOf failed", `{"anyOf":[{"type":"string"},{"type":"integer"}]}`, `true`, []string{"$" + invalid}},
+		{"oneOf", `{"oneOf":[{"minimum":1},{"maximum":0}]}`, `2`, nil},
+		{"oneOf none", `{"oneOf":[{"minimum":1},{"maximum":0}]}`, `0.5`, []string{"$" + invalid}},
+		{"oneOf several", `{"oneOf":[{"minimum":1},{"maximum":3}]}`, `2`, []string{"$" + invalid}},
+		{"not", `{"not":{"type":"string"}}`, `"a"`, []string{"$" + invalid}},
+		{"not matched", `{"not":{"type":"string"}}`, `1`, nil},
+		{"unknown keywords", `{"format":"email","title":"x"}`, `"a"`, nil},
+		{
+			"ref",
+			`{"properties":{"port":{"$ref":"#/$defs/port"}},"$defs":{"port":{"type":"integer","maximum":65535}}}`,
+			`{"port":70000}`,
+			[]string{"$.port" + invalid},
+		},
+		{
+			"escaped ref",
+			`{"$ref":"#/$defs/a~1b","$defs":{"a/b":{"type":"string"}}}`,
+			`1`,
+			[]string{"$" + invalid},
+		},
+		{
+			"ref with siblings",
+			`{"$ref":"#/$defs/s","maxLength":1,"$defs":{"s":{"type":"string"}}}`,
+			`"ab"`,
+			[]string{"$" + invalid},
+		},
+	}
+	for _, c := range cases {
+		schema, err := CompileJSONSchema(mustParse(t, c.schema))
+		if err != nil {
+			t.Errorf("%s: %v", c.name, err)
+			continue
+		}
+		errs := schema.Validate(mustParse(t, c.doc))
+		if got := schemaErrorFields(errs); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("%s: want %v got %v", c.name, c.want, errs)
+		}
+	}
+}
+
+func TestJSONSchemaRefCycles(t *testing.T) {
+	// Recursive schemas descending into the document are valid.
+	tree, err := CompileJSONSchema(mustParse(t, `{
+		"$ref": "#/$defs/node",
+		"$defs": {
+			"node": {
+				"type": "object",
+				"required": ["name"],
+				"properties": {
+					"name": {"type": "string"},
+					"children": {"type": "array", "items": {"$ref": "#/$defs/node"}}
+				}
+			}
+		}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc := mustParse(t, `{"name":"a","children":[{"name":"b","children":[{"children":[]}]},{"name":1}]}`)
+	want := []string{
+		"$.children[0].children[0].name " + string(SchemaErrorRequired),
+		"$.children[1].name " + string(SchemaErrorInvalid),
+	}
+	if got := schemaErrorFields(tree.Validate(doc)); !reflect.DeepEqual(got, want) {
+		t.Errorf("want %v got %v", want, got)
+	}
+
+	self, err := CompileJSONSchema(mustParse(t, `{"properties":{"next":{"$ref":"#"}},"additionalProperties":false}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = []string{"$.next.next.other " + string(SchemaErrorForbidden)}
+	if got := schemaErrorFields(self.Validate(mustParse(t, `{"next":{"next":{"other":1}}}`))); !reflect.DeepEqual(got, want) {
+		t.Errorf("want %v got %v", want, got)
+	}
+
+	// Cycles validating the same value again are invalid, since they never end.
+	cycles := []string{
+		`{"$ref":"#"}`,
+		`{"allOf":[{"$ref":"#"}]}`,
+		`{"$ref":"#/$defs/a","$defs":{"a":{"$ref":"#/$defs/b"},"b":{"anyOf":[{"$ref":"#/$defs/a"}]}}}`,
+		`{"properties":{"a":{"$ref":"#/$defs/a"}},"$defs":{"a":{"not":{"$ref":"#/$defs/a"}}}}`,
+		`{"oneOf":[true,{"$ref":"#/oneOf/1"}]}`,
+	}
+	for _, schema := range cycles {
+		_, err := CompileJSONSchema(mustParse(t, schema))
+		if err == nil || !strings.Contains(err.Error(), "reference cycle") {
+			t.Errorf("%s: want a reference cycle error got %v", schema, err)
+		}
+	}
+}
+
+func TestJSONSchemaCompileErrors(t *testing.T) {
+	schemas := []string{
+		`1`,
+		`{"type":"text"}`,
+		`{"type":1}`,
+		`{"enum":1}`,
+		`{"pattern":"("}`,
+		`{"minLength":-1}`,
+		`{"minimum":"1"}`,
+		`{"properties":[]}`,
+		`{"required":[1]}`,
+		`{"allOf":{}}`,
+		`{"items":1}`,
+		`{"$ref":1}`,
+		`{"$ref":"other.json#/a"}`,
+		`{"$ref":"#/$defs/missing"}`,
+		`{"$ref":"#a"}`,
+	}
+	for _, schema := range schemas {
+		if _, err := CompileJSONSchema(mustParse(t, schema)); err == nil {
+			t.Errorf("%s: want error", schema)
+		}
+	}
+}
+
+func TestLoadJSONSchema(t *testing.T) {
+	dir, err := ioutil.TempDir("", "jsonschema")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	filename := filepath.Join(dir, "schema.yaml")
+	content := "type: object\nrequired: [name]\nproperties:\n  name:\n    type: string\n"
+	if err := ioutil.WriteFile(filename, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	schema, err := LoadJSONSchema(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"$.name " + string(SchemaErrorRequired)}
+	if got := schemaErrorFields(schema.Validate(mustParse(t, `{}`))); !reflect.DeepEqual(got, want) {
+		t.Errorf("want %v got %v", want, got)
+	}
+
+	if _, err := LoadJSONSchema(filepath.Join(dir, "missing.yaml")); err == nil {
+		t.Errorf("want error for a missing file")
+	}
+}
+
+func TestSchemaErrorFieldError(t *testing.T) {
+	schema, err := ParseJSONSchema(`{"properties":{"spec":{"properties":{"ports":{"items":{"maximum":65535}}}}}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	errs := schema.Validate(mustParse(t, `{"spec":{"ports":[70000]}}`))
+	if len(errs) != 1 {
+		t.Fatalf("want 1 error got %v", errs)
+	}
+	e := errs[0]
+	if e.FieldErrorType() != "FieldValueInvalid" || e.FieldPath() != "spec.ports[0]" ||
+		e.FieldBadValue() != int64(70000) || e.FieldDetail() != "must be less than or equal to 65535" {
+		t.Errorf("unexpected field error %q %q %#v %q", e.FieldErrorType(), e.FieldPath(), e.FieldBadValue(), e.FieldDetail())
+	}
+	if e.Error() != "$.spec.ports[0]: Invalid value: 70000: must be less than or equal to 65535" {
+		t.Errorf("unexpected message %q", e.Error())
+	}
+	if root := (&SchemaError{Field: "$"}); root.FieldPath() != "" || root.FieldBadValue() != nil {
+		t.Errorf("unexpected root field error %q %#v", root.FieldPath(), root.FieldBadValue())
+	}
+
+	if errs.Errors()[0] != error(e) || errs.ToAggregate() == nil {
+		t.Errorf("unexpected conversions of %v", errs)
+	}
+	if SchemaErrorList(nil).Errors() != nil || SchemaErrorList(nil).ToAggregate() != nil {
+		t.Errorf("want nil conversions of an empty list")
+	}
+}
+
+func TestJSONSchemaValidateErrors(t *testing.T) {
+	schema, err := ParseJSONSchema(`{"required":["name"],"properties":{"port":{"maximum":65535}}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	errs := schema.ValidateErrors(mustParse(t, `{"port":70000}`))
+	if len(errs) != 2 {
+		t.Fatalf("want 2 errors got %v", errs)
+	}
+	if e, ok := errs[0].(*SchemaError); !ok || e.Type != SchemaErrorRequired {
+		t.Errorf("want a required error got %v", errs[0])
+	}
+
+	value := struct {
+		Name string `json:"name"`
+		Port int    `json:"port"`
+	}{"foo", 80}
+	if errs := schema.ValidateErrors(value); errs != nil {
+		t.Errorf("want no error for a marshaled value got %v", errs)
+	}
+}
@@ -53,6 +53,11 @@ type JSONObject interface {
 	GetString(keys ...string) (string, error)
 	Unmarshal(obj interface{}, keys ...string) error
 	Equals(obj JSONObject) bool
+	// GetPointer, SetPointer and RemovePointer follow a RFC 6901 JSON pointer,
+	// see the functions of the same names.
+	GetPointer(pointer string) (JSONObject, error)
+	SetPointer(pointer string, value JSONObject, createParents bool) error
+	RemovePointer(pointer string) error
 	unmarshalValue(val reflect.Value) error
 	// IsZero() bool
 	Interface() interface{}
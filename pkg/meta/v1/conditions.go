@@ -0,0 +1,138 @@
+// Copyright 2020 Lingfei Kong <colin404@foxmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package v1
+
+import (
+	"time"
+
+	"github.com/HappyLadySauce/component-base/pkg/json"
+	"github.com/HappyLadySauce/component-base/pkg/util/clock"
+)
+
+// ConditionStatus is the status of a condition.
+// ConditionStatus是状态条件的状态。
+type ConditionStatus string
+
+// These are valid condition statuses. "ConditionTrue" means a resource is in the condition.
+// "ConditionFalse" means a resource is not in the condition. "ConditionUnknown" means
+// the server can't decide if a resource is in the condition or not.
+const (
+	ConditionTrue    ConditionStatus = "True"
+	ConditionFalse   ConditionStatus = "False"
+	ConditionUnknown ConditionStatus = "Unknown"
+)
+
+// Condition contains details for one aspect of the current state of a resource.
+// Condition是用来描述资源当前状态某一方面的结构体。
+type Condition struct {
+	// Type of condition in CamelCase, e.g. Available or Progressing.
+	Type string `json:"type"`
+
+	// Status of the condition, one of True, False, Unknown.
+	Status ConditionStatus `json:"status"`
+
+	// ObservedGeneration represents the generation of the resource the condition was set
+	// upon. A condition is out of date if it is lower than the current generation.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// LastTransitionTime is the last time the condition transitioned from one status to another.
+	LastTransitionTime time.Time `json:"lastTransitionTime"`
+
+	// Reason contains a programmatic identifier indicating the reason for the condition's
+	// last transition, in CamelCase.
+	Reason string `json:"reason"`
+
+	// Message is a human readable message indicating details about the transition.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// Conditions is the list of conditions of a resource, with at most one condition per type.
+// Conditions是资源的状态条件列表，每个类型最多有一个状态条件。
+type Conditions []Condition
+
+// String returns the string format of Conditions.
+// 返回状态条件的字符串格式。
+func (c Conditions) String() string {
+	if len(c) == 0 {
+		return ""
+	}
+	data, _ := json.Marshal(c)
+
+	return string(data)
+}
+
+// Find returns the condition of the given type, or nil if it is not present.
+// Find用来查找指定类型的状态条件。
+func (c Conditions) Find(conditionType string) *Condition {
+	for i := range c {
+		if c[i].Type == conditionType {
+			return &c[i]
+		}
+	}
+
+	return nil
+}
+
+// IsTrue returns true if the condition of the given type is present and its status is True.
+// IsTrue用来判断指定类型的状态条件是否为True。
+func (c Conditions) IsTrue(conditionType string) bool {
+	cond := c.Find(conditionType)
+
+	return cond != nil && cond.Status == ConditionTrue
+}
+
+// Set adds newCondition, or updates the condition of the same type. The
+// LastTransitionTime is taken from clk when the condition is added or its
+// status changes, and kept otherwise. Set returns true if the conditions changed.
+// Set用来添加或更新状态条件，只有状态改变时才会更新LastTransitionTime。
+func (c *Conditions) Set(clk clock.PassiveClock, newCondition Condition) bool {
+	existing := c.Find(newCondition.Type)
+	if existing == nil {
+		newCondition.LastTransitionTime = clk.Now()
+		*c = append(*c, newCondition)
+
+		return true
+	}
+
+	changed := false
+	if existing.Status != newCondition.Status {
+		existing.Status = newCondition.Status
+		existing.LastTransitionTime = clk.Now()
+		changed = true
+	}
+	if existing.Reason != newCondition.Reason {
+		existing.Reason = newCondition.Reason
+		changed = true
+	}
+	if existing.Message != newCondition.Message {
+		existing.Message = newCondition.Message
+		changed = true
+	}
+	if existing.ObservedGeneration != newCondition.ObservedGeneration {
+		existing.ObservedGeneration = newCondition.ObservedGeneration
+		changed = true
+	}
+
+	return changed
+}
+
+// Remove removes the condition of the given type. It returns true if the conditions changed.
+// Remove用来移除指定类型的状态条件。
+func (c *Conditions) Remove(conditionType string) bool {
+	var kept Conditions
+	for _, cond := range *c {
+		if cond.Type != conditionType {
+			kept = append(kept, cond)
+		}
+	}
+	if len(kept) == len(*c) {
+		return false
+	}
+	*c = kept
+
+	return true
+}
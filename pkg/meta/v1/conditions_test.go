@@ -0,0 +1,70 @@
+// Copyright 2020 Lingfei Kong <colin404@foxmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package v1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/HappyLadySauce/component-base/pkg/util/clock"
+)
+
+func TestConditions(t *testing.T) {
+	start := time.Date(2020, 5, 4, 3, 2, 1, 0, time.UTC)
+	clk := clock.NewFakePassiveClock(start)
+	var conditions Conditions
+
+	if !conditions.Set(clk, Condition{Type: "Available", Status: ConditionFalse, Reason: "Starting"}) {
+		t.Errorf("expected the condition to be added")
+	}
+	if cond := conditions.Find("Available"); cond == nil || !cond.LastTransitionTime.Equal(start) {
+		t.Fatalf("unexpected condition %v", cond)
+	}
+
+	clk.SetTime(start.Add(time.Minute))
+	if !conditions.Set(clk, Condition{Type: "Available", Status: ConditionFalse, Reason: "Pulling"}) {
+		t.Errorf("expected the reason to be updated")
+	}
+	if cond := conditions.Find("Available"); cond.Reason != "Pulling" || !cond.LastTransitionTime.Equal(start) {
+		t.Errorf("transition time changed without a status change: %v", cond)
+	}
+	if conditions.Set(clk, Condition{Type: "Available", Status: ConditionFalse, Reason: "Pulling"}) {
+		t.Errorf("expected no change")
+	}
+
+	clk.SetTime(start.Add(2 * time.Minute))
+	conditions.Set(clk, Condition{Type: "Available", Status: ConditionTrue, Reason: "Ready", ObservedGeneration: 2})
+	if cond := conditions.Find("Available"); !cond.LastTransitionTime.Equal(start.Add(2*time.Minute)) ||
+		cond.ObservedGeneration != 2 {
+		t.Errorf("unexpected condition %v", cond)
+	}
+	if !conditions.IsTrue("Available") || conditions.IsTrue("Progressing") {
+		t.Errorf("unexpected IsTrue result")
+	}
+
+	conditions.Set(clk, Condition{Type: "Progressing", Status: ConditionUnknown})
+	if !conditions.Remove("Available") || conditions.Remove("Available") {
+		t.Errorf("unexpected Remove result")
+	}
+	if len(conditions) != 1 || conditions.Find("Progressing") == nil {
+		t.Errorf("unexpected conditions %v", conditions)
+	}
+}
+
+func TestConditionsShadow(t *testing.T) {
+	now := time.Date(2020, 5, 4, 3, 2, 1, 0, time.UTC)
+	obj := &ObjectMeta{Conditions: Conditions{{Type: "Available", Status: ConditionTrue, LastTransitionTime: now}}}
+	if err := obj.BeforeCreate(nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	found := &ObjectMeta{ExtendShadow: obj.ExtendShadow, ConditionsShadow: obj.ConditionsShadow}
+	if err := found.AfterFind(nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cond := found.Conditions.Find("Available"); cond == nil || !cond.LastTransitionTime.Equal(now) {
+		t.Errorf("conditions were not restored: %v", found.Conditions)
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright 2020 Lingfei Kong <colin404@foxmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package v1
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestObjectMetaDeepCopy(t *testing.T) {
+	now := time.Now()
+	isController := true
+	in := &ObjectMeta{
+		InstanceID:        "secret-1",
+		Labels:            Labels{"app": "web"},
+		Extend:            Extend{"owner": map[string]interface{}{"name": "colin"}, "tags": []interface{}{"a"}},
+		DeletionTimestamp: &now,
+		OwnerReferences:   OwnerReferences{{InstanceID: "app-1", Controller: &isController}},
+		Finalizers:        Finalizers{FinalizerOrphanDependents},
+	}
+
+	out := in.DeepCopy()
+	if !reflect.DeepEqual(in, out) {
+		t.Fatalf("copy differs from the original: %#v", out)
+	}
+
+	out.Labels["app"] = "db"
+	out.Extend["owner"].(map[string]interface{})["name"] = "lingfei"
+	out.Extend["tags"].([]interface{})[0] = "b"
+	*out.OwnerReferences[0].Controller = false
+	out.Finalizers[0] = FinalizerDeleteDependents
+	*out.DeletionTimestamp = now.Add(time.Hour)
+
+	if in.Labels["app"] != "web" || in.Extend["owner"].(map[string]interface{})["name"] != "colin" ||
+		in.Extend["tags"].([]interface{})[0] != "a" || !*in.OwnerReferences[0].Controller ||
+		in.Finalizers[0] != FinalizerOrphanDependents || !in.DeletionTimestamp.Equal(now) {
+		t.Errorf("modifying the copy changed the original: %#v", in)
+	}
+
+	if copied := (&ObjectMeta{}).DeepCopy(); copied.Labels != nil || copied.Extend != nil {
+		t.Errorf("nil maps must stay nil: %#v", copied)
+	}
+	if obj := (&ListOptions{}).DeepCopyObject(); obj == nil {
+		t.Errorf("DeepCopyObject returned nil")
+	}
+}
+
+func TestObjectMetaDeepCopyTypedExtend(t *testing.T) {
+	now := time.Date(2020, 5, 4, 3, 2, 1, 0, time.UTC)
+	in := &ObjectMeta{Extend: Extend{"tags": []string{"a"}, "n": uint64(3)}}
+	if err := in.Extend.SetTime("owner.since", now); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := in.Extend.Set("nested", Extend{"since": now, "ports": []int{80}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	out := in.DeepCopy()
+	if !reflect.DeepEqual(in, out) {
+		t.Fatalf("copy differs from the original: %#v", out)
+	}
+
+	out.Extend["tags"].([]string)[0] = "b"
+	out.Extend["nested"].(Extend)["ports"].([]int)[0] = 443
+	if in.Extend["tags"].([]string)[0] != "a" || in.Extend["nested"].(Extend)["ports"].([]int)[0] != 80 {
+		t.Errorf("modifying the copy changed the original: %#v", in.Extend)
+	}
+	if v, ok := out.Extend.GetTime("nested.since"); !ok || !v.Equal(now) {
+		t.Errorf("GetTime on the copy: got %v, %v", v, ok)
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright 2020 Lingfei Kong <colin404@foxmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package v1
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/HappyLadySauce/component-base/pkg/util/clock"
+	"github.com/HappyLadySauce/component-base/pkg/util/wait"
+	"github.com/HappyLadySauce/component-base/pkg/validation/field"
+)
+
+var supportedPropagationPolicies = []string{
+	string(DeletePropagationOrphan),
+	string(DeletePropagationBackground),
+	string(DeletePropagationForeground),
+}
+
+// Validate validates the DeleteOptions.
+// Validate用来校验DeleteOptions。
+func (o *DeleteOptions) Validate() field.ErrorList {
+	allErrs := field.ErrorList{}
+	if o.PropagationPolicy == nil {
+		return allErrs
+	}
+
+	switch *o.PropagationPolicy {
+	case DeletePropagationOrphan, DeletePropagationBackground, DeletePropagationForeground:
+	default:
+		allErrs = append(
+			allErrs,
+			field.NotSupported(field.NewPath("propagationPolicy"), *o.PropagationPolicy, supportedPropagationPolicies),
+		)
+	}
+
+	return allErrs
+}
+
+// Delete deletes obj honouring its finalizers and opts.PropagationPolicy.
+// Foreground and orphan deletions add the matching finalizer. If obj has any
+// finalizer it is not removed: its deletion timestamp is taken from clk instead
+// and the garbage collector removes it once all finalizers are gone. Delete
+// returns true if obj was removed from storage.
+// Delete用来删除对象，对象有终结器时只设置删除时间并等待终结器被移除。
+func Delete(db *gorm.DB, clk clock.PassiveClock, obj interface{}, opts DeleteOptions) (bool, error) {
+	if errs := opts.Validate(); len(errs) > 0 {
+		return false, errs.ToAggregate()
+	}
+
+	accessor, ok := obj.(ObjectMetaAccessor)
+	if !ok {
+		return false, fmt.Errorf("%T does not have ObjectMeta", obj)
+	}
+	meta := accessor.GetObjectMeta()
+
+	if opts.PropagationPolicy != nil {
+		switch *opts.PropagationPolicy {
+		case DeletePropagationForeground:
+			AddFinalizer(meta, FinalizerDeleteDependents)
+		case DeletePropagationOrphan:
+			AddFinalizer(meta, FinalizerOrphanDependents)
+		}
+	}
+
+	if len(meta.GetFinalizers()) > 0 {
+		if meta.GetDeletionTimestamp() == nil {
+			now := clk.Now()
+			meta.SetDeletionTimestamp(&now)
+		}
+
+		return false, db.Model(obj).Select("finalizers", "deletionTimestamp").Updates(obj).Error
+	}
+
+	if opts.Unscoped {
+		db = db.Unscoped()
+	}
+
+	return true, db.Delete(obj).Error
+}
+
+// WaitForDeletion polls every interval until obj has been removed from storage
+// or stopCh is closed.
+// WaitForDeletion用来等待对象从存储中被删除。
+func WaitForDeletion(db *gorm.DB, obj interface{}, interval time.Duration, stopCh <-chan struct{}) error {
+	accessor, ok := obj.(ObjectMetaAccessor)
+	if !ok {
+		return fmt.Errorf("%T does not have ObjectMeta", obj)
+	}
+	id := accessor.GetObjectMeta().GetID()
+
+	return wait.PollImmediateUntil(interval, func() (bool, error) {
+		var count int64
+		if err := db.Model(obj).Where("id = ?", id).Count(&count).Error; err != nil {
+			return false, err
+		}
+
+		return count == 0, nil
+	}, stopCh)
+}
@@ -0,0 +1,57 @@
+// Copyright 2020 Lingfei Kong <colin404@foxmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package v1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/HappyLadySauce/component-base/pkg/util/clock"
+)
+
+func TestDelete(t *testing.T) {
+	db := newDryRunDB(t)
+	now := time.Date(2020, 8, 1, 10, 0, 0, 0, time.UTC)
+	clk := clock.NewFakeClock(now)
+
+	secret := &dryRunSecret{ObjectMeta: ObjectMeta{InstanceID: "secret-1", Name: "a"}}
+	if err := Create(db, secret, CreateOptions{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	foreground := DeletePropagationForeground
+	deleted, err := Delete(db, clk, secret, DeleteOptions{PropagationPolicy: &foreground})
+	if err != nil || deleted {
+		t.Fatalf("expected the secret to wait for its finalizer, got %v, %v", deleted, err)
+	}
+	var stored dryRunSecret
+	if err := db.First(&stored, secret.ID).Error; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stored.DeletionTimestamp == nil || !stored.DeletionTimestamp.Equal(now) {
+		t.Errorf("expected the deletion timestamp of the clock, got %v", stored.DeletionTimestamp)
+	}
+	if !HasFinalizer(&stored.ObjectMeta, FinalizerDeleteDependents) {
+		t.Errorf("expected the foreground finalizer, got %v", stored.Finalizers)
+	}
+
+	// A later deletion keeps the first timestamp.
+	clk.Step(time.Hour)
+	if _, err := Delete(db, clk, &stored, DeleteOptions{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !stored.DeletionTimestamp.Equal(now) {
+		t.Errorf("expected the deletion timestamp to be kept, got %v", stored.DeletionTimestamp)
+	}
+
+	RemoveFinalizer(&stored.ObjectMeta, FinalizerDeleteDependents)
+	deleted, err = Delete(db, clk, &stored, DeleteOptions{})
+	if err != nil || !deleted {
+		t.Fatalf("expected the secret to be deleted, got %v, %v", deleted, err)
+	}
+	if n := countSecrets(t, db); n != 0 {
+		t.Errorf("expected no row, got %d", n)
+	}
+}
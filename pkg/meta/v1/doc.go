@@ -2,6 +2,8 @@
 // Use of this source code is governed by a MIT style
 // license that can be found in the LICENSE file.
 
+// +deepcopy-gen=package
+
 // Package v1 contains API types that are common to all versions.
 //
 // The package contains two categories of types:
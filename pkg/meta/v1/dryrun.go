@@ -0,0 +1,135 @@
+// Copyright 2020 Lingfei Kong <colin404@foxmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package v1
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+
+	"github.com/HappyLadySauce/component-base/pkg/util/sets"
+	"github.com/HappyLadySauce/component-base/pkg/validation/field"
+)
+
+// DryRunAll means to complete all processing stages, but don't persist changes to storage.
+// DryRunAll表示执行所有处理阶段，但不持久化到存储中。
+const DryRunAll = "All"
+
+// allowedDryRunValues is the set of values accepted in a dryRun list.
+var allowedDryRunValues = sets.NewString(DryRunAll)
+
+// errDryRunRollback is returned from a dry-run transaction to force gorm to roll it back.
+var errDryRunRollback = errors.New("dry run: rollback")
+
+// IsDryRun returns true if the dryRun list requests a dry run.
+// IsDryRun用来判断是否为试运行。
+func IsDryRun(dryRun []string) bool {
+	return len(dryRun) > 0
+}
+
+// ValidateDryRun validates that a dryRun list only contains allowed values.
+// ValidateDryRun用来校验dryRun的取值是否合法。
+func ValidateDryRun(fldPath *field.Path, dryRun []string) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if !allowedDryRunValues.HasAll(dryRun...) {
+		allErrs = append(allErrs, field.NotSupported(fldPath, dryRun, allowedDryRunValues.List()))
+	}
+
+	return allErrs
+}
+
+// Validate validates the CreateOptions.
+// Validate用来校验CreateOptions。
+func (o *CreateOptions) Validate() field.ErrorList {
+	return ValidateDryRun(field.NewPath("dryRun"), o.DryRun)
+}
+
+// Validate validates the UpdateOptions.
+// Validate用来校验UpdateOptions。
+func (o *UpdateOptions) Validate() field.ErrorList {
+	return ValidateDryRun(field.NewPath("dryRun"), o.DryRun)
+}
+
+// Validate validates the PatchOptions.
+// Validate用来校验PatchOptions。
+func (o *PatchOptions) Validate() field.ErrorList {
+	return ValidateDryRun(field.NewPath("dryRun"), o.DryRun)
+}
+
+// WithDryRun runs fn against db. When dryRun is requested fn runs inside a transaction
+// which is always rolled back, so the full write path, hooks included, is exercised
+// without persisting anything.
+// WithDryRun用来在事务中执行fn，试运行时事务总是会被回滚。
+func WithDryRun(db *gorm.DB, dryRun []string, fn func(tx *gorm.DB) error) error {
+	if !IsDryRun(dryRun) {
+		return fn(db)
+	}
+
+	if errs := ValidateDryRun(field.NewPath("dryRun"), dryRun); len(errs) > 0 {
+		return errs.ToAggregate()
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := fn(tx); err != nil {
+			return err
+		}
+
+		return errDryRunRollback
+	})
+	if errors.Is(err, errDryRunRollback) {
+		return nil
+	}
+
+	return err
+}
+
+// Create creates obj honouring opts.DryRun. On a dry run obj is filled with the
+// values it would have been persisted with.
+// Create用来创建对象，试运行时obj会被填充为持久化后的样子。
+func Create(db *gorm.DB, obj interface{}, opts CreateOptions) error {
+	return WithDryRun(db, opts.DryRun, func(tx *gorm.DB) error {
+		if err := tx.Create(obj).Error; err != nil {
+			return err
+		}
+
+		return reload(tx, obj, opts.DryRun)
+	})
+}
+
+// Update saves all fields of obj honouring opts.DryRun. On a dry run obj is filled
+// with the values it would have been persisted with.
+// Update用来更新对象，试运行时obj会被填充为持久化后的样子。
+func Update(db *gorm.DB, obj interface{}, opts UpdateOptions) error {
+	return WithDryRun(db, opts.DryRun, func(tx *gorm.DB) error {
+		if err := tx.Save(obj).Error; err != nil {
+			return err
+		}
+
+		return reload(tx, obj, opts.DryRun)
+	})
+}
+
+// Patch updates the given columns of obj honouring opts.DryRun. On a dry run obj is
+// filled with the values it would have been persisted with.
+// Patch用来更新对象的部分字段，试运行时obj会被填充为持久化后的样子。
+func Patch(db *gorm.DB, obj interface{}, columns map[string]interface{}, opts PatchOptions) error {
+	return WithDryRun(db, opts.DryRun, func(tx *gorm.DB) error {
+		if err := tx.Model(obj).Updates(columns).Error; err != nil {
+			return err
+		}
+
+		return reload(tx, obj, opts.DryRun)
+	})
+}
+
+// reload reads obj back inside a dry-run transaction so database defaults and
+// AfterFind hooks are reflected in the response.
+func reload(tx *gorm.DB, obj interface{}, dryRun []string) error {
+	if !IsDryRun(dryRun) {
+		return nil
+	}
+
+	return tx.First(obj).Error
+}
@@ -0,0 +1,177 @@
+// Copyright 2020 Lingfei Kong <colin404@foxmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package v1
+
+import (
+	"errors"
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/HappyLadySauce/component-base/pkg/validation/field"
+)
+
+type dryRunSecret struct {
+	ObjectMeta `json:"metadata,omitempty"`
+
+	Value string `json:"value" gorm:"column:value"`
+	// Kind is filled by the database, so that a dry run must read the object back to see it.
+	Kind string `json:"kind" gorm:"column:kind;default:opaque"`
+}
+
+func newDryRunDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{
+		Logger: logger.Discard,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// A single connection keeps the in-memory database alive for the whole test.
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	if err := db.AutoMigrate(&dryRunSecret{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return db
+}
+
+func countSecrets(t *testing.T, db *gorm.DB) int64 {
+	t.Helper()
+
+	var count int64
+	if err := db.Model(&dryRunSecret{}).Count(&count).Error; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return count
+}
+
+func TestCreateDryRun(t *testing.T) {
+	db := newDryRunDB(t)
+
+	secret := &dryRunSecret{ObjectMeta: ObjectMeta{InstanceID: "secret-1", Name: "a"}, Value: "v"}
+	if err := Create(db, secret, CreateOptions{DryRun: []string{DryRunAll}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if secret.ID == 0 || secret.CreatedAt.IsZero() {
+		t.Errorf("expected the would-be ID and creation time, got %d and %v", secret.ID, secret.CreatedAt)
+	}
+	if secret.Kind != "opaque" {
+		t.Errorf("expected the database default to be read back, got %q", secret.Kind)
+	}
+	if n := countSecrets(t, db); n != 0 {
+		t.Errorf("expected no row after a dry run, got %d", n)
+	}
+
+	secret = &dryRunSecret{ObjectMeta: ObjectMeta{InstanceID: "secret-1", Name: "a"}, Value: "v"}
+	if err := Create(db, secret, CreateOptions{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n := countSecrets(t, db); n != 1 {
+		t.Errorf("expected the row to be created, got %d rows", n)
+	}
+}
+
+func TestUpdateAndPatchDryRun(t *testing.T) {
+	db := newDryRunDB(t)
+
+	secret := &dryRunSecret{ObjectMeta: ObjectMeta{InstanceID: "secret-1", Name: "a"}, Value: "v1"}
+	if err := Create(db, secret, CreateOptions{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	updated := *secret
+	updated.Value = "v2"
+	if err := Update(db, &updated, UpdateOptions{DryRun: []string{DryRunAll}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if updated.Value != "v2" {
+		t.Errorf("expected the would-be object, got value %q", updated.Value)
+	}
+
+	patched := &dryRunSecret{ObjectMeta: ObjectMeta{ID: secret.ID}}
+	columns := map[string]interface{}{"value": "v3"}
+	if err := Patch(db, patched, columns, PatchOptions{DryRun: []string{DryRunAll}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if patched.Value != "v3" || patched.Name != "a" {
+		t.Errorf("expected the whole would-be object, got name %q and value %q", patched.Name, patched.Value)
+	}
+
+	var stored dryRunSecret
+	if err := db.First(&stored, secret.ID).Error; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stored.Value != "v1" {
+		t.Errorf("expected the dry runs to leave the row untouched, got value %q", stored.Value)
+	}
+}
+
+func TestWithDryRun(t *testing.T) {
+	db := newDryRunDB(t)
+
+	failure := errors.New("failure")
+	if err := WithDryRun(db, []string{DryRunAll}, func(tx *gorm.DB) error { return failure }); err != failure {
+		t.Errorf("expected the error of fn, got %v", err)
+	}
+
+	called := false
+	err := WithDryRun(db, []string{"Some"}, func(tx *gorm.DB) error {
+		called = true
+
+		return nil
+	})
+	if err == nil || called {
+		t.Errorf("expected an invalid dryRun to be rejected before running fn, got %v", err)
+	}
+}
+
+func TestValidateDryRun(t *testing.T) {
+	testCases := []struct {
+		dryRun []string
+		valid  bool
+	}{
+		{nil, true},
+		{[]string{}, true},
+		{[]string{DryRunAll}, true},
+		{[]string{DryRunAll, DryRunAll}, true},
+		{[]string{"all"}, false},
+		{[]string{DryRunAll, "Some"}, false},
+	}
+
+	for _, tc := range testCases {
+		errs := ValidateDryRun(field.NewPath("dryRun"), tc.dryRun)
+		if valid := len(errs) == 0; valid != tc.valid {
+			t.Errorf("%v: expected valid %v, got %v", tc.dryRun, tc.valid, errs)
+		}
+		if !tc.valid && errs[0].Type != field.ErrorTypeNotSupported {
+			t.Errorf("%v: expected a NotSupported error, got %v", tc.dryRun, errs[0])
+		}
+	}
+
+	opts := CreateOptions{DryRun: []string{"Some"}}
+	if errs := opts.Validate(); len(errs) != 1 || errs[0].Field != "dryRun" {
+		t.Errorf("expected one error on dryRun, got %v", errs)
+	}
+}
+
+func TestIsDryRun(t *testing.T) {
+	if IsDryRun(nil) || IsDryRun([]string{}) {
+		t.Errorf("expected an empty dryRun not to request a dry run")
+	}
+	if !IsDryRun([]string{DryRunAll}) {
+		t.Errorf("expected dryRun All to request a dry run")
+	}
+}
@@ -0,0 +1,228 @@
+// Copyright 2020 Lingfei Kong <colin404@foxmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package v1
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/HappyLadySauce/component-base/pkg/json"
+)
+
+// Extend fields are addressed with a dotted path, e.g. "quota.cpu.limit". Each
+// segment but the last must refer to a nested map.
+
+// Get returns the value stored at path.
+// Get用来获取path对应的值。
+func (ext Extend) Get(path string) (interface{}, bool) {
+	var node interface{} = map[string]interface{}(ext)
+	for _, key := range splitExtendPath(path) {
+		m, ok := asMap(node)
+		if !ok {
+			return nil, false
+		}
+		if node, ok = m[key]; !ok {
+			return nil, false
+		}
+	}
+
+	return node, true
+}
+
+// GetString returns the string stored at path.
+// GetString用来获取path对应的字符串。
+func (ext Extend) GetString(path string) (string, bool) {
+	v, ok := ext.Get(path)
+	if !ok {
+		return "", false
+	}
+	s, ok := v.(string)
+
+	return s, ok
+}
+
+// GetInt64 returns the integer stored at path. Integral float64 and json.Number
+// values, as produced by decoding ExtendShadow, are converted.
+// GetInt64用来获取path对应的整数。
+func (ext Extend) GetInt64(path string) (int64, bool) {
+	v, ok := ext.Get(path)
+	if !ok {
+		return 0, false
+	}
+
+	return toInt64(v)
+}
+
+// GetBool returns the bool stored at path.
+// GetBool用来获取path对应的布尔值。
+func (ext Extend) GetBool(path string) (bool, bool) {
+	v, ok := ext.Get(path)
+	if !ok {
+		return false, false
+	}
+	b, ok := v.(bool)
+
+	return b, ok
+}
+
+// GetTime returns the time stored at path. Times are stored in RFC 3339 format.
+// GetTime用来获取path对应的时间。
+func (ext Extend) GetTime(path string) (time.Time, bool) {
+	v, ok := ext.Get(path)
+	if !ok {
+		return time.Time{}, false
+	}
+
+	return toTime(v)
+}
+
+// GetMap returns the nested map stored at path.
+// GetMap用来获取path对应的嵌套map。
+func (ext Extend) GetMap(path string) (map[string]interface{}, bool) {
+	v, ok := ext.Get(path)
+	if !ok {
+		return nil, false
+	}
+
+	return asMap(v)
+}
+
+// Set stores value at path, creating intermediate maps as needed, and ext itself
+// when it is nil, as in a zero ObjectMeta. It fails if an intermediate segment
+// holds a value which is not a map.
+// Set用来设置path对应的值，必要时会创建中间的map，ext为nil时也会被创建。
+func (ext *Extend) Set(path string, value interface{}) error {
+	keys := splitExtendPath(path)
+	if len(keys) == 0 {
+		return fmt.Errorf("empty extend path")
+	}
+
+	if *ext == nil {
+		*ext = Extend{}
+	}
+	m := map[string]interface{}(*ext)
+	for i, key := range keys[:len(keys)-1] {
+		child, ok := m[key]
+		if !ok {
+			next := map[string]interface{}{}
+			m[key] = next
+			m = next
+
+			continue
+		}
+		next, ok := asMap(child)
+		if !ok {
+			return fmt.Errorf("extend path %q: %q is not a map", path, strings.Join(keys[:i+1], "."))
+		}
+		m = next
+	}
+	m[keys[len(keys)-1]] = value
+
+	return nil
+}
+
+// SetString stores a string at path.
+// SetString用来设置path对应的字符串。
+func (ext *Extend) SetString(path string, value string) error { return ext.Set(path, value) }
+
+// SetInt64 stores an integer at path.
+// SetInt64用来设置path对应的整数。
+func (ext *Extend) SetInt64(path string, value int64) error { return ext.Set(path, value) }
+
+// SetBool stores a bool at path.
+// SetBool用来设置path对应的布尔值。
+func (ext *Extend) SetBool(path string, value bool) error { return ext.Set(path, value) }
+
+// SetTime stores a time at path in RFC 3339 format.
+// SetTime用来设置path对应的时间。
+func (ext *Extend) SetTime(path string, value time.Time) error {
+	return ext.Set(path, value.Format(time.RFC3339Nano))
+}
+
+// SetMap stores a nested map at path.
+// SetMap用来设置path对应的嵌套map。
+func (ext *Extend) SetMap(path string, value map[string]interface{}) error {
+	return ext.Set(path, value)
+}
+
+// Delete removes the value stored at path.
+// Delete用来删除path对应的值。
+func (ext Extend) Delete(path string) {
+	keys := splitExtendPath(path)
+	if len(keys) == 0 {
+		return
+	}
+
+	m := map[string]interface{}(ext)
+	for _, key := range keys[:len(keys)-1] {
+		next, ok := asMap(m[key])
+		if !ok {
+			return
+		}
+		m = next
+	}
+	delete(m, keys[len(keys)-1])
+}
+
+func splitExtendPath(path string) []string {
+	if path == "" {
+		return nil
+	}
+
+	return strings.Split(path, ".")
+}
+
+func asMap(v interface{}) (map[string]interface{}, bool) {
+	switch m := v.(type) {
+	case map[string]interface{}:
+		return m, true
+	case Extend:
+		return m, true
+	default:
+		return nil, false
+	}
+}
+
+func toInt64(v interface{}) (int64, bool) {
+	switch n := v.(type) {
+	case int:
+		return int64(n), true
+	case int32:
+		return int64(n), true
+	case int64:
+		return n, true
+	case uint32:
+		return int64(n), true
+	case float64:
+		// float64(math.MaxInt64) rounds up to 2^63, which is out of range.
+		if n != math.Trunc(n) || n >= math.MaxInt64 || n < math.MinInt64 {
+			return 0, false
+		}
+
+		return int64(n), true
+	case json.Number:
+		i, err := strconv.ParseInt(n.String(), 10, 64)
+
+		return i, err == nil
+	default:
+		return 0, false
+	}
+}
+
+func toTime(v interface{}) (time.Time, bool) {
+	switch t := v.(type) {
+	case time.Time:
+		return t, true
+	case string:
+		parsed, err := time.Parse(time.RFC3339Nano, t)
+
+		return parsed, err == nil
+	default:
+		return time.Time{}, false
+	}
+}
@@ -0,0 +1,203 @@
+// Copyright 2020 Lingfei Kong <colin404@foxmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package v1
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/HappyLadySauce/component-base/pkg/json"
+	"github.com/HappyLadySauce/component-base/pkg/validation/field"
+)
+
+// ExtendFieldType is the type of an extend field declared in an ExtendSchema.
+// ExtendFieldType是扩展字段的类型。
+type ExtendFieldType string
+
+// These are the supported extend field types.
+const (
+	ExtendFieldString ExtendFieldType = "string"
+	ExtendFieldInt64  ExtendFieldType = "int64"
+	ExtendFieldBool   ExtendFieldType = "bool"
+	ExtendFieldTime   ExtendFieldType = "time"
+	ExtendFieldMap    ExtendFieldType = "map"
+)
+
+// ExtendField declares a single extend field.
+// ExtendField是用来声明扩展字段的结构体。
+type ExtendField struct {
+	// Type is the type of the value stored in the field.
+	Type ExtendFieldType
+	// Required fields must be present.
+	Required bool
+}
+
+// ExtendSchema declares the extend fields of a resource kind, keyed by dotted path.
+// The content of ExtendFieldMap fields is not checked.
+// ExtendSchema是用来声明某类资源扩展字段的结构体。
+type ExtendSchema struct {
+	// Fields maps a dotted path to the declaration of the field stored there.
+	Fields map[string]ExtendField
+	// RejectUnknown reports keys which are not declared in Fields instead of
+	// silently keeping them.
+	RejectUnknown bool
+}
+
+var (
+	extendSchemasLock sync.RWMutex
+	extendSchemas     = map[string]*ExtendSchema{}
+)
+
+// RegisterExtendSchema registers the extend schema of a resource kind. Registering
+// a kind twice replaces the previous schema.
+// RegisterExtendSchema用来注册某类资源的扩展字段模式。
+func RegisterExtendSchema(kind string, schema *ExtendSchema) {
+	extendSchemasLock.Lock()
+	defer extendSchemasLock.Unlock()
+
+	extendSchemas[kind] = schema
+}
+
+// ExtendSchemaFor returns the extend schema registered for kind.
+// ExtendSchemaFor用来获取某类资源的扩展字段模式。
+func ExtendSchemaFor(kind string) (*ExtendSchema, bool) {
+	extendSchemasLock.RLock()
+	defer extendSchemasLock.RUnlock()
+
+	schema, ok := extendSchemas[kind]
+
+	return schema, ok
+}
+
+// Validate validates ext against the schema.
+// Validate用来根据模式校验扩展字段。
+func (s *ExtendSchema) Validate(ext Extend, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	paths := make([]string, 0, len(s.Fields))
+	for path := range s.Fields {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		decl := s.Fields[path]
+		value, ok := ext.Get(path)
+		if !ok {
+			if decl.Required {
+				allErrs = append(allErrs, field.Required(fldPath.Key(path), ""))
+			}
+
+			continue
+		}
+		if !decl.Type.matches(value) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Key(path), value, "must be of type "+string(decl.Type)))
+		}
+	}
+
+	if s.RejectUnknown {
+		for _, path := range s.unknownPaths(ext) {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Key(path), "unknown extend field"))
+		}
+	}
+
+	return allErrs
+}
+
+// MergeWithSchema merges extend fields from extendShadow like Merge, but keys
+// which are not declared in schema are not merged and are returned instead.
+// A nil ext is allocated, so the returned Extend must be used.
+// MergeWithSchema用来根据模式合并扩展字段，并返回未声明的字段。ext为nil时会被创建。
+func (ext Extend) MergeWithSchema(extendShadow string, schema *ExtendSchema) (Extend, []string) {
+	if ext == nil {
+		ext = Extend{}
+	}
+	if schema == nil {
+		return ext.Merge(extendShadow), nil
+	}
+
+	var extend Extend
+	_ = json.Unmarshal([]byte(extendShadow), &extend)
+
+	unknown := schema.unknownPaths(extend)
+	for _, path := range unknown {
+		extend.Delete(path)
+	}
+
+	for k, v := range extend {
+		if _, ok := ext[k]; !ok {
+			ext[k] = v
+		}
+	}
+
+	return ext, unknown
+}
+
+// unknownPaths returns the sorted dotted paths in ext which are not declared by
+// the schema, nor an ancestor of a declared path, nor inside a declared map.
+func (s *ExtendSchema) unknownPaths(ext Extend) []string {
+	var unknown []string
+	var walk func(m map[string]interface{}, prefix string)
+	walk = func(m map[string]interface{}, prefix string) {
+		for k, v := range m {
+			path := k
+			if prefix != "" {
+				path = prefix + "." + k
+			}
+			if _, declared := s.Fields[path]; declared {
+				continue
+			}
+			if s.isAncestor(path) {
+				if child, ok := asMap(v); ok {
+					walk(child, path)
+				}
+
+				continue
+			}
+			unknown = append(unknown, path)
+		}
+	}
+	walk(ext, "")
+	sort.Strings(unknown)
+
+	return unknown
+}
+
+func (s *ExtendSchema) isAncestor(path string) bool {
+	for declared := range s.Fields {
+		if len(declared) > len(path) && declared[:len(path)] == path && declared[len(path)] == '.' {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (t ExtendFieldType) matches(v interface{}) bool {
+	switch t {
+	case ExtendFieldString:
+		_, ok := v.(string)
+
+		return ok
+	case ExtendFieldInt64:
+		_, ok := toInt64(v)
+
+		return ok
+	case ExtendFieldBool:
+		_, ok := v.(bool)
+
+		return ok
+	case ExtendFieldTime:
+		_, ok := toTime(v)
+
+		return ok
+	case ExtendFieldMap:
+		_, ok := asMap(v)
+
+		return ok
+	default:
+		return false
+	}
+}
@@ -0,0 +1,159 @@
+// Copyright 2020 Lingfei Kong <colin404@foxmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package v1
+
+import (
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/HappyLadySauce/component-base/pkg/validation/field"
+)
+
+func TestExtendAccessors(t *testing.T) {
+	ext := Extend{}
+	now := time.Date(2020, 5, 4, 3, 2, 1, 0, time.UTC)
+
+	if err := ext.SetString("owner.name", "colin"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := ext.SetInt64("quota.cpu", 4); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := ext.SetBool("enabled", true); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := ext.SetTime("owner.since", now); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := ext.SetString("enabled.nested", "x"); err == nil {
+		t.Errorf("expected an error when setting below a non-map value")
+	}
+
+	// Round trip through ExtendShadow, as a database read would.
+	ext = Extend{}.Merge(ext.String())
+
+	if v, ok := ext.GetString("owner.name"); !ok || v != "colin" {
+		t.Errorf("GetString: got %q, %v", v, ok)
+	}
+	if v, ok := ext.GetInt64("quota.cpu"); !ok || v != 4 {
+		t.Errorf("GetInt64: got %d, %v", v, ok)
+	}
+	if v, ok := ext.GetBool("enabled"); !ok || !v {
+		t.Errorf("GetBool: got %v, %v", v, ok)
+	}
+	if v, ok := ext.GetTime("owner.since"); !ok || !v.Equal(now) {
+		t.Errorf("GetTime: got %v, %v", v, ok)
+	}
+	if v, ok := ext.GetMap("quota"); !ok || len(v) != 1 {
+		t.Errorf("GetMap: got %v, %v", v, ok)
+	}
+	if _, ok := ext.GetInt64("owner.name"); ok {
+		t.Errorf("GetInt64 on a string should fail")
+	}
+	if _, ok := ext.GetString("owner.missing"); ok {
+		t.Errorf("GetString on a missing path should fail")
+	}
+
+	ext.Delete("owner.name")
+	if _, ok := ext.Get("owner.name"); ok {
+		t.Errorf("Delete did not remove owner.name")
+	}
+}
+
+func TestExtendSchema(t *testing.T) {
+	schema := &ExtendSchema{
+		Fields: map[string]ExtendField{
+			"owner.name": {Type: ExtendFieldString, Required: true},
+			"quota":      {Type: ExtendFieldMap},
+			"replicas":   {Type: ExtendFieldInt64},
+		},
+		RejectUnknown: true,
+	}
+
+	ext := Extend{
+		"owner":    map[string]interface{}{"name": "colin", "mail": "a@b.c"},
+		"quota":    map[string]interface{}{"cpu": 1},
+		"replicas": 1.5,
+		"junk":     true,
+	}
+	errs := schema.Validate(ext, field.NewPath("extend"))
+	expected := []string{
+		"extend[replicas]: Invalid value: 1.5: must be of type int64",
+		"extend[junk]: Forbidden: unknown extend field",
+		"extend[owner.mail]: Forbidden: unknown extend field",
+	}
+	got := make([]string, 0, len(errs))
+	for _, err := range errs {
+		got = append(got, err.Error())
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+
+	if errs := schema.Validate(Extend{}, field.NewPath("extend")); len(errs) != 1 ||
+		errs[0].Type != field.ErrorTypeRequired {
+		t.Errorf("expected a required error, got %v", errs)
+	}
+
+	merged, unknown := Extend{}.MergeWithSchema(ext.String(), schema)
+	if !reflect.DeepEqual(unknown, []string{"junk", "owner.mail"}) {
+		t.Errorf("unexpected unknown keys: %v", unknown)
+	}
+	if _, ok := merged.Get("junk"); ok {
+		t.Errorf("unknown key was merged: %v", merged)
+	}
+	if v, ok := merged.GetString("owner.name"); !ok || v != "colin" {
+		t.Errorf("known key was not merged: %v", merged)
+	}
+}
+
+func TestExtendZeroValue(t *testing.T) {
+	var meta ObjectMeta
+	if err := meta.Extend.SetString("owner.name", "colin"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if v, ok := meta.Extend.GetString("owner.name"); !ok || v != "colin" {
+		t.Errorf("GetString: got %q, %v", v, ok)
+	}
+
+	var ext Extend
+	if _, ok := ext.Get("owner"); ok {
+		t.Errorf("Get on a nil Extend should fail")
+	}
+	ext.Delete("owner")
+
+	schema := &ExtendSchema{Fields: map[string]ExtendField{"owner": {Type: ExtendFieldString}}}
+	merged, unknown := Extend(nil).MergeWithSchema(`{"owner":"colin","junk":true}`, schema)
+	if v, ok := merged.GetString("owner"); !ok || v != "colin" || !reflect.DeepEqual(unknown, []string{"junk"}) {
+		t.Errorf("MergeWithSchema: got %v, %v", merged, unknown)
+	}
+	if merged, _ := Extend(nil).MergeWithSchema(`{"owner":"colin"}`, nil); len(merged) != 1 {
+		t.Errorf("MergeWithSchema without a schema: got %v", merged)
+	}
+}
+
+func TestExtendInt64Bounds(t *testing.T) {
+	testCases := []struct {
+		value    float64
+		expected int64
+		ok       bool
+	}{
+		{math.Pow(2, 63), 0, false},
+		{math.Nextafter(math.Pow(2, 63), 0), 1<<63 - 1024, true},
+		{-math.Pow(2, 63), math.MinInt64, true},
+		{math.Nextafter(-math.Pow(2, 63), math.Inf(-1)), 0, false},
+		{math.Inf(1), 0, false},
+		{1.5, 0, false},
+	}
+
+	for _, tc := range testCases {
+		v, ok := Extend{"n": tc.value}.GetInt64("n")
+		if v != tc.expected || ok != tc.ok {
+			t.Errorf("%v: expected %d, %v, got %d, %v", tc.value, tc.expected, tc.ok, v, ok)
+		}
+	}
+}
@@ -0,0 +1,26 @@
+// Copyright 2020 Lingfei Kong <colin404@foxmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package v1
+
+import (
+	sqldriver "database/sql/driver"
+)
+
+// Labels is the set of labels of an object. It is stored as a JSON string in a
+// single column.
+// Labels是对象的标签集合，以JSON字符串的形式存储在数据库中。
+type Labels map[string]string
+
+// Value implements the driver.Valuer interface.
+// Value用来将Labels写入数据库。
+func (l Labels) Value() (sqldriver.Value, error) {
+	return jsonValue(l)
+}
+
+// Scan implements the sql.Scanner interface.
+// Scan用来从数据库中读取Labels。
+func (l *Labels) Scan(src interface{}) error {
+	return scanJSON(src, l)
+}
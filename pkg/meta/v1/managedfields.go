@@ -0,0 +1,67 @@
+// Copyright 2020 Lingfei Kong <colin404@foxmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package v1
+
+import (
+	sqldriver "database/sql/driver"
+	"time"
+)
+
+// ManagedFieldsOperationType is the type of operation which lead to a ManagedFieldsEntry being created.
+// ManagedFieldsOperationType是用来表示字段管理者的操作类型。
+type ManagedFieldsOperationType string
+
+// These are the valid managed fields operations.
+const (
+	// ManagedFieldsOperationApply means the fields were set by a server-side apply.
+	ManagedFieldsOperationApply ManagedFieldsOperationType = "Apply"
+	// ManagedFieldsOperationUpdate means the fields were set by a create, update or patch.
+	ManagedFieldsOperationUpdate ManagedFieldsOperationType = "Update"
+)
+
+// ManagedFieldsEntry records the set of fields a manager owns on an object.
+// ManagedFieldsEntry是用来记录某个管理者所拥有的字段。
+type ManagedFieldsEntry struct {
+	// Manager is an identifier of the workflow managing these fields.
+	Manager string `json:"manager,omitempty"`
+
+	// Operation is the type of operation which lead to this entry being created.
+	Operation ManagedFieldsOperationType `json:"operation,omitempty"`
+
+	// Time is the timestamp of when these fields were last changed by the manager.
+	Time time.Time `json:"time,omitempty"`
+
+	// Fields lists the JSON pointers (RFC 6901) of the fields owned by the manager.
+	Fields []string `json:"fields,omitempty"`
+}
+
+// ManagedFields is the list of ManagedFieldsEntry of an object. It is stored
+// as a JSON string in a single column.
+// ManagedFields是对象的字段管理记录，以JSON字符串的形式存储在数据库中。
+type ManagedFields []ManagedFieldsEntry
+
+// Value implements the driver.Valuer interface.
+// Value用来将ManagedFields写入数据库。
+func (m ManagedFields) Value() (sqldriver.Value, error) {
+	return jsonValue(m)
+}
+
+// Scan implements the sql.Scanner interface.
+// Scan用来从数据库中读取ManagedFields。
+func (m *ManagedFields) Scan(src interface{}) error {
+	return scanJSON(src, m)
+}
+
+// Find returns the entry of manager for the given operation.
+// Find用来查找管理者对应操作的记录。
+func (m ManagedFields) Find(manager string, operation ManagedFieldsOperationType) (*ManagedFieldsEntry, bool) {
+	for i := range m {
+		if m[i].Manager == manager && m[i].Operation == operation {
+			return &m[i], true
+		}
+	}
+
+	return nil, false
+}
@@ -30,6 +30,12 @@ type Object interface {
 	// SetID sets the ID of the object.
 	// 设置对象的ID。
 	SetID(id uint64)
+	// GetInstanceID returns the instance ID of the object.
+	// 获取对象的实例ID。
+	GetInstanceID() string
+	// SetInstanceID sets the instance ID of the object.
+	// 设置对象的实例ID。
+	SetInstanceID(instanceID string)
 	// GetName returns the name of the object.
 	// 获取对象的名称。
 	GetName() string
@@ -48,6 +54,36 @@ type Object interface {
 	// SetUpdatedAt sets the update time of the object.
 	// 设置对象的更新时间。
 	SetUpdatedAt(updatedAt time.Time)
+	// GetManagedFields returns the managed fields of the object.
+	// 获取对象的字段管理记录。
+	GetManagedFields() ManagedFields
+	// SetManagedFields sets the managed fields of the object.
+	// 设置对象的字段管理记录。
+	SetManagedFields(managedFields ManagedFields)
+	// GetDeletionTimestamp returns the deletion timestamp of the object.
+	// 获取对象的删除时间。
+	GetDeletionTimestamp() *time.Time
+	// SetDeletionTimestamp sets the deletion timestamp of the object.
+	// 设置对象的删除时间。
+	SetDeletionTimestamp(timestamp *time.Time)
+	// GetOwnerReferences returns the owner references of the object.
+	// 获取对象的拥有者列表。
+	GetOwnerReferences() []OwnerReference
+	// SetOwnerReferences sets the owner references of the object.
+	// 设置对象的拥有者列表。
+	SetOwnerReferences(references []OwnerReference)
+	// GetFinalizers returns the finalizers of the object.
+	// 获取对象的终结器列表。
+	GetFinalizers() []string
+	// SetFinalizers sets the finalizers of the object.
+	// 设置对象的终结器列表。
+	SetFinalizers(finalizers []string)
+	// GetLabels returns the labels of the object.
+	// 获取对象的标签。
+	GetLabels() map[string]string
+	// SetLabels sets the labels of the object.
+	// 设置对象的标签。
+	SetLabels(labels map[string]string)
 }
 
 // ListInterface lets you work with list metadata from any of the versioned or
@@ -146,6 +182,14 @@ func (meta *ObjectMeta) GetID() uint64 { return meta.ID }
 // 设置对象的ID。
 func (meta *ObjectMeta) SetID(id uint64) { meta.ID = id }
 
+// GetInstanceID returns the instance ID of the object.
+// 获取对象的实例ID。
+func (meta *ObjectMeta) GetInstanceID() string { return meta.InstanceID }
+
+// SetInstanceID sets the instance ID of the object.
+// 设置对象的实例ID。
+func (meta *ObjectMeta) SetInstanceID(instanceID string) { meta.InstanceID = instanceID }
+
 // GetName returns the name of the object.
 // 获取对象的名称。
 func (meta *ObjectMeta) GetName() string { return meta.Name }
@@ -169,3 +213,49 @@ func (meta *ObjectMeta) GetUpdatedAt() time.Time { return meta.UpdatedAt }
 // SetUpdatedAt sets the update time of the object.
 // 设置对象的更新时间。
 func (meta *ObjectMeta) SetUpdatedAt(updatedAt time.Time) { meta.UpdatedAt = updatedAt }
+
+// GetManagedFields returns the managed fields of the object.
+// 获取对象的字段管理记录。
+func (meta *ObjectMeta) GetManagedFields() ManagedFields { return meta.ManagedFields }
+
+// SetManagedFields sets the managed fields of the object.
+// 设置对象的字段管理记录。
+func (meta *ObjectMeta) SetManagedFields(managedFields ManagedFields) {
+	meta.ManagedFields = managedFields
+}
+
+// GetDeletionTimestamp returns the deletion timestamp of the object.
+// 获取对象的删除时间。
+func (meta *ObjectMeta) GetDeletionTimestamp() *time.Time { return meta.DeletionTimestamp }
+
+// SetDeletionTimestamp sets the deletion timestamp of the object.
+// 设置对象的删除时间。
+func (meta *ObjectMeta) SetDeletionTimestamp(timestamp *time.Time) {
+	meta.DeletionTimestamp = timestamp
+}
+
+// GetOwnerReferences returns the owner references of the object.
+// 获取对象的拥有者列表。
+func (meta *ObjectMeta) GetOwnerReferences() []OwnerReference { return meta.OwnerReferences }
+
+// SetOwnerReferences sets the owner references of the object.
+// 设置对象的拥有者列表。
+func (meta *ObjectMeta) SetOwnerReferences(references []OwnerReference) {
+	meta.OwnerReferences = references
+}
+
+// GetFinalizers returns the finalizers of the object.
+// 获取对象的终结器列表。
+func (meta *ObjectMeta) GetFinalizers() []string { return meta.Finalizers }
+
+// SetFinalizers sets the finalizers of the object.
+// 设置对象的终结器列表。
+func (meta *ObjectMeta) SetFinalizers(finalizers []string) { meta.Finalizers = finalizers }
+
+// GetLabels returns the labels of the object.
+// 获取对象的标签。
+func (meta *ObjectMeta) GetLabels() map[string]string { return meta.Labels }
+
+// SetLabels sets the labels of the object.
+// 设置对象的标签。
+func (meta *ObjectMeta) SetLabels(labels map[string]string) { meta.Labels = labels }
@@ -0,0 +1,168 @@
+// Copyright 2020 Lingfei Kong <colin404@foxmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package v1
+
+import (
+	sqldriver "database/sql/driver"
+)
+
+// DeletionPropagation decides if a deletion will propagate to the dependents of the object,
+// and how the garbage collector will handle the propagation.
+// DeletionPropagation是用来决定删除是否以及如何传播到依赖对象。
+type DeletionPropagation string
+
+const (
+	// DeletePropagationOrphan orphans the dependents: their owner references to
+	// the deleted object are removed.
+	DeletePropagationOrphan DeletionPropagation = "Orphan"
+	// DeletePropagationBackground deletes the object immediately and lets the
+	// garbage collector delete the dependents in the background.
+	DeletePropagationBackground DeletionPropagation = "Background"
+	// DeletePropagationForeground deletes the dependents first: the object stays,
+	// with a deletion timestamp, until all of its dependents are gone.
+	DeletePropagationForeground DeletionPropagation = "Foreground"
+)
+
+const (
+	// FinalizerOrphanDependents is set by an orphan deletion until the garbage
+	// collector has removed the owner references of the dependents.
+	FinalizerOrphanDependents = "orphan"
+	// FinalizerDeleteDependents is set by a foreground deletion until the garbage
+	// collector has deleted all the dependents.
+	FinalizerDeleteDependents = "foregroundDeletion"
+)
+
+// OwnerReference contains enough information to let you identify an owning
+// object. An owning object must be of the same storage as the dependent.
+// OwnerReference是用来标识拥有者对象的结构体。
+type OwnerReference struct {
+	// Kind of the referent.
+	Kind string `json:"kind"`
+
+	// InstanceID of the referent.
+	InstanceID string `json:"instanceID"`
+
+	// Name of the referent.
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// If true, this reference points to the managing controller.
+	// +optional
+	Controller *bool `json:"controller,omitempty"`
+}
+
+// OwnerReferences is the list of owners of an object. It is stored as a JSON
+// string in a single column.
+// OwnerReferences是对象的拥有者列表，以JSON字符串的形式存储在数据库中。
+type OwnerReferences []OwnerReference
+
+// Value implements the driver.Valuer interface.
+// Value用来将OwnerReferences写入数据库。
+func (refs OwnerReferences) Value() (sqldriver.Value, error) {
+	return jsonValue(refs)
+}
+
+// Scan implements the sql.Scanner interface.
+// Scan用来从数据库中读取OwnerReferences。
+func (refs *OwnerReferences) Scan(src interface{}) error {
+	return scanJSON(src, refs)
+}
+
+// Finalizers is the list of finalizers of an object. It is stored as a JSON
+// string in a single column.
+// Finalizers是对象的终结器列表，以JSON字符串的形式存储在数据库中。
+type Finalizers []string
+
+// Value implements the driver.Valuer interface.
+// Value用来将Finalizers写入数据库。
+func (f Finalizers) Value() (sqldriver.Value, error) {
+	return jsonValue(f)
+}
+
+// Scan implements the sql.Scanner interface.
+// Scan用来从数据库中读取Finalizers。
+func (f *Finalizers) Scan(src interface{}) error {
+	return scanJSON(src, f)
+}
+
+// NewControllerRef creates an OwnerReference pointing to the given owner as its controller.
+// NewControllerRef用来创建指向控制者的OwnerReference。
+func NewControllerRef(owner Object, kind string) *OwnerReference {
+	isController := true
+
+	return &OwnerReference{
+		Kind:       kind,
+		InstanceID: owner.GetInstanceID(),
+		Name:       owner.GetName(),
+		Controller: &isController,
+	}
+}
+
+// GetControllerOf returns a pointer to a copy of the controllerRef if controllee has a controller.
+// GetControllerOf用来获取对象的控制者。
+func GetControllerOf(controllee Object) *OwnerReference {
+	for _, ref := range controllee.GetOwnerReferences() {
+		if ref.Controller != nil && *ref.Controller {
+			ref := ref
+
+			return &ref
+		}
+	}
+
+	return nil
+}
+
+// IsControlledBy checks if the object has a controllerRef set to the given owner.
+// IsControlledBy用来判断对象是否被owner控制。
+func IsControlledBy(obj Object, owner Object) bool {
+	ref := GetControllerOf(obj)
+	if ref == nil {
+		return false
+	}
+
+	return ref.InstanceID == owner.GetInstanceID()
+}
+
+// HasFinalizer returns true if obj has the given finalizer.
+// HasFinalizer用来判断对象是否有指定的终结器。
+func HasFinalizer(obj Object, finalizer string) bool {
+	for _, f := range obj.GetFinalizers() {
+		if f == finalizer {
+			return true
+		}
+	}
+
+	return false
+}
+
+// AddFinalizer adds finalizer to obj if it is not present yet. It returns true
+// if the finalizers were changed.
+// AddFinalizer用来为对象添加终结器。
+func AddFinalizer(obj Object, finalizer string) bool {
+	if HasFinalizer(obj, finalizer) {
+		return false
+	}
+	obj.SetFinalizers(append(obj.GetFinalizers(), finalizer))
+
+	return true
+}
+
+// RemoveFinalizer removes finalizer from obj. It returns true if the finalizers
+// were changed.
+// RemoveFinalizer用来移除对象的终结器。
+func RemoveFinalizer(obj Object, finalizer string) bool {
+	var kept []string
+	for _, f := range obj.GetFinalizers() {
+		if f != finalizer {
+			kept = append(kept, f)
+		}
+	}
+	if len(kept) == len(obj.GetFinalizers()) {
+		return false
+	}
+	obj.SetFinalizers(kept)
+
+	return true
+}
@@ -0,0 +1,45 @@
+// Copyright 2020 Lingfei Kong <colin404@foxmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package v1
+
+import (
+	sqldriver "database/sql/driver"
+	"fmt"
+
+	"github.com/HappyLadySauce/component-base/pkg/json"
+)
+
+// jsonValue returns the JSON string of v, used to store structured metadata in a single column.
+// jsonValue用来将v序列化为JSON字符串存储到数据库中。
+func jsonValue(v interface{}) (sqldriver.Value, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return string(data), nil
+}
+
+// scanJSON unmarshals a JSON column value into v. Empty columns leave v untouched.
+// scanJSON用来将数据库中的JSON字符串反序列化到v中。
+func scanJSON(src interface{}, v interface{}) error {
+	var data []byte
+	switch s := src.(type) {
+	case nil:
+		return nil
+	case []byte:
+		data = s
+	case string:
+		data = []byte(s)
+	default:
+		return fmt.Errorf("can not convert %v to json", src)
+	}
+
+	if len(data) == 0 {
+		return nil
+	}
+
+	return json.Unmarshal(data, v)
+}
@@ -71,8 +71,11 @@ type ListMeta struct {
 
 // ObjectMeta is metadata that all persisted resources must have, which includes all objects
 // ObjectMeta is also used by gorm.
+// Each persisted field is a column of every resource table: adding one is a schema change,
+// which must be described in CHANGELOG.md.
 // ObjectMeta是用来描述对象的元数据。
 // 结构体中包含了ID、InstanceID、Name、Extend、ExtendShadow、CreatedAt和UpdatedAt字段。
+// 每个持久化字段都是所有资源表的列，新增字段属于表结构变更，需要记录在CHANGELOG.md中。
 type ObjectMeta struct {
 	// ID is the unique in time and space value for this object. It is typically generated by
 	// the storage on successful creation of a resource and is not allowed to change on PUT
@@ -110,6 +113,10 @@ type ObjectMeta struct {
 	// 不能被更新。
 	Name string `json:"name,omitempty" gorm:"column:name;type:varchar(64);not null" validate:"name"`
 
+	// Labels are key value pairs that may be used to organize and select objects.
+	// Labels是用来组织和筛选对象的键值对。
+	Labels Labels `json:"labels,omitempty" gorm:"column:labels;type:text"`
+
 	// Extend store the fields that need to be added, but do not want to add a new table column, will not be stored in db.
 	// Extend是用来存储扩展字段的类型。
 	// 不会被存储在数据库中。
@@ -120,6 +127,16 @@ type ObjectMeta struct {
 	// 不要直接修改。
 	ExtendShadow string `json:"-" gorm:"column:extendShadow" validate:"omitempty"`
 
+	// Conditions are the latest available observations of the object's state.
+	// They are stored in ConditionsShadow, the same way Extend is.
+	// Conditions是对象状态的最新观测结果。
+	Conditions Conditions `json:"conditions,omitempty" gorm:"-"`
+
+	// ConditionsShadow is the shadow of Conditions. DO NOT modify directly.
+	// ConditionsShadow是用来存储状态条件的影子。
+	// 不要直接修改。
+	ConditionsShadow string `json:"-" gorm:"column:conditionsShadow"`
+
 	// CreatedAt is a timestamp representing the server time when this object was
 	// created. It is not guaranteed to be set in happens-before order across separate operations.
 	// Clients may not set this value. It is represented in RFC3339 form and is in UTC.
@@ -139,6 +156,32 @@ type ObjectMeta struct {
 	// UpdatedAt是用来表示对象的更新时间。
 	UpdatedAt time.Time `json:"updatedAt,omitempty" gorm:"column:updatedAt"`
 
+	// ManagedFields maps a workflow to the set of fields that are managed by that workflow.
+	// It is maintained by the field manager during apply and update operations.
+	//
+	// Populated by the system.
+	// ManagedFields是用来记录各个管理者所拥有的字段。
+	ManagedFields ManagedFields `json:"managedFields,omitempty" gorm:"column:managedFields;type:text"`
+
+	// DeletionTimestamp is the time after which this resource will be deleted. It is set
+	// when a deletion is requested while the object still has finalizers; the object is
+	// removed from storage once all finalizers have been removed.
+	//
+	// Populated by the system.
+	// Read-only.
+	// DeletionTimestamp是用来表示对象被请求删除的时间。
+	DeletionTimestamp *time.Time `json:"deletionTimestamp,omitempty" gorm:"column:deletionTimestamp"`
+
+	// OwnerReferences is the list of objects depended by this object. If ALL objects
+	// in the list have been deleted, this object will be garbage collected.
+	// OwnerReferences是对象所依赖的拥有者列表。
+	OwnerReferences OwnerReferences `json:"ownerReferences,omitempty" gorm:"column:ownerReferences;type:text"`
+
+	// Finalizers must be empty before the object is deleted from storage.
+	// Each finalizer is removed by the component responsible for it.
+	// Finalizers是对象被删除前必须清空的终结器列表。
+	Finalizers Finalizers `json:"finalizers,omitempty" gorm:"column:finalizers;type:text"`
+
 	// DeletedAt is RFC 3339 date and time at which this resource will be deleted. This
 	// field is set by the server when a graceful deletion is requested by the user, and is not
 	// directly settable by a client.
@@ -152,6 +195,7 @@ type ObjectMeta struct {
 // BeforeCreate是用来在创建数据库记录之前执行的函数。
 func (obj *ObjectMeta) BeforeCreate(tx *gorm.DB) error {
 	obj.ExtendShadow = obj.Extend.String()
+	obj.ConditionsShadow = obj.Conditions.String()
 
 	return nil
 }
@@ -160,22 +204,31 @@ func (obj *ObjectMeta) BeforeCreate(tx *gorm.DB) error {
 // BeforeUpdate是用来在更新数据库记录之前执行的函数。
 func (obj *ObjectMeta) BeforeUpdate(tx *gorm.DB) error {
 	obj.ExtendShadow = obj.Extend.String()
+	obj.ConditionsShadow = obj.Conditions.String()
 
 	return nil
 }
 
-// AfterFind run after find to unmarshal a extend shadown string into metav1.Extend struct.
+// AfterFind run after find to unmarshal a extend shadown string into metav1.Extend struct,
+// and a conditions shadow string into metav1.Conditions.
 // AfterFind是用来在查询数据库记录之后执行的函数。
 func (obj *ObjectMeta) AfterFind(tx *gorm.DB) error {
 	if err := json.Unmarshal([]byte(obj.ExtendShadow), &obj.Extend); err != nil {
 		return err
 	}
 
+	if obj.ConditionsShadow != "" {
+		if err := json.Unmarshal([]byte(obj.ConditionsShadow), &obj.Conditions); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
 // ListOptions is the query options to a standard REST list call.
 // ListOptions是用来查询列表的选项。
+// +deepcopy-gen:interfaces=github.com/HappyLadySauce/component-base/pkg/runtime.Object
 type ListOptions struct {
 	TypeMeta `json:",inline"`
 
@@ -195,10 +248,32 @@ type ListOptions struct {
 	Limit *int64 `json:"limit,omitempty" form:"limit"`
 }
 
+// WatchOptions is the query options to a standard REST watch call.
+// WatchOptions是用来监听对象变化的选项。
+// +deepcopy-gen:interfaces=github.com/HappyLadySauce/component-base/pkg/runtime.Object
+type WatchOptions struct {
+	TypeMeta `json:",inline"`
+
+	// LabelSelector restricts the events to objects matching the selector. Defaults to everything.
+	LabelSelector string `json:"labelSelector,omitempty" form:"labelSelector"`
+
+	// FieldSelector restricts the events to objects matching the selector. Defaults to everything.
+	FieldSelector string `json:"fieldSelector,omitempty" form:"fieldSelector"`
+
+	// ResourceVersion is the version after which events are sent. Events that
+	// happened before the watch started are replayed from that version on.
+	// When unset, only events that happen after the watch started are sent.
+	ResourceVersion string `json:"resourceVersion,omitempty" form:"resourceVersion"`
+
+	// TimeoutSeconds limits the duration of the watch call.
+	TimeoutSeconds *int64 `json:"timeoutSeconds,omitempty" form:"timeoutSeconds"`
+}
+
 // ExportOptions is the query options to the standard REST get call.
 // Deprecated. Planned for removal in 1.18.
 // ExportOptions是用来导出对象的选项。
 // 结构体中包含了Export和Exact两个字段。
+// +deepcopy-gen:interfaces=github.com/HappyLadySauce/component-base/pkg/runtime.Object
 type ExportOptions struct {
 	TypeMeta `json:",inline"`
 
@@ -212,21 +287,33 @@ type ExportOptions struct {
 
 // GetOptions is the standard query options to the standard REST get call.
 // GetOptions是用来查询对象的选项。
+// +deepcopy-gen:interfaces=github.com/HappyLadySauce/component-base/pkg/runtime.Object
 type GetOptions struct {
 	TypeMeta `json:",inline"`
 }
 
 // DeleteOptions may be provided when deleting an API object.
 // DeleteOptions是用来删除对象的选项。
+// +deepcopy-gen:interfaces=github.com/HappyLadySauce/component-base/pkg/runtime.Object
 type DeleteOptions struct {
 	TypeMeta `json:",inline"`
 
 	// +optional
 	Unscoped bool `json:"unscoped"`
+
+	// Whether and how garbage collection will be performed.
+	// Defaults to Background.
+	// Acceptable values are:
+	// 'Orphan' - orphan the dependents;
+	// 'Background' - allow the garbage collector to delete the dependents in the background;
+	// 'Foreground' - a cascading policy that deletes all dependents in the foreground.
+	// +optional
+	PropagationPolicy *DeletionPropagation `json:"propagationPolicy,omitempty" form:"propagationPolicy"`
 }
 
 // CreateOptions may be provided when creating an API object.
 // CreateOptions是用来创建对象的选项。
+// +deepcopy-gen:interfaces=github.com/HappyLadySauce/component-base/pkg/runtime.Object
 type CreateOptions struct {
 	TypeMeta `json:",inline"`
 
@@ -237,11 +324,17 @@ type CreateOptions struct {
 	// - All: all dry run stages will be processed
 	// +optional
 	DryRun []string `json:"dryRun,omitempty"`
+
+	// FieldManager is a name associated with the actor or entity
+	// that is making these changes.
+	// +optional
+	FieldManager string `json:"fieldManager,omitempty"`
 }
 
 // PatchOptions may be provided when patching an API object.
 // PatchOptions is meant to be a superset of UpdateOptions.
 // PatchOptions是用来更新对象的选项。
+// +deepcopy-gen:interfaces=github.com/HappyLadySauce/component-base/pkg/runtime.Object
 type PatchOptions struct {
 	TypeMeta `json:",inline"`
 
@@ -253,6 +346,11 @@ type PatchOptions struct {
 	// +optional
 	DryRun []string `json:"dryRun,omitempty"`
 
+	// FieldManager is a name associated with the actor or entity
+	// that is making these changes. It is required for apply requests.
+	// +optional
+	FieldManager string `json:"fieldManager,omitempty"`
+
 	// Force is going to "force" Apply requests. It means user will
 	// re-acquire conflicting fields owned by other people. Force
 	// flag must be unset for non-apply patch requests.
@@ -263,6 +361,7 @@ type PatchOptions struct {
 // UpdateOptions may be provided when updating an API object.
 // All fields in UpdateOptions should also be present in PatchOptions.
 // UpdateOptions是用来更新对象的选项。
+// +deepcopy-gen:interfaces=github.com/HappyLadySauce/component-base/pkg/runtime.Object
 type UpdateOptions struct {
 	TypeMeta `json:",inline"`
 
@@ -273,16 +372,23 @@ type UpdateOptions struct {
 	// - All: all dry run stages will be processed
 	// +optional
 	DryRun []string `json:"dryRun,omitempty"`
+
+	// FieldManager is a name associated with the actor or entity
+	// that is making these changes.
+	// +optional
+	FieldManager string `json:"fieldManager,omitempty"`
 }
 
 // AuthorizeOptions may be provided when authorize an API object.
 // AuthorizeOptions是用来授权对象的选项。
+// +deepcopy-gen:interfaces=github.com/HappyLadySauce/component-base/pkg/runtime.Object
 type AuthorizeOptions struct {
 	TypeMeta `json:",inline"`
 }
 
 // TableOptions are used when a Table is requested by the caller.
 // TableOptions是用来请求表格的选项。
+// +deepcopy-gen:interfaces=github.com/HappyLadySauce/component-base/pkg/runtime.Object
 type TableOptions struct {
 	TypeMeta `json:",inline"`
 
@@ -0,0 +1,129 @@
+// Copyright 2020 Lingfei Kong <colin404@foxmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package unstructured
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/HappyLadySauce/component-base/pkg/json"
+	"github.com/HappyLadySauce/component-base/pkg/runtime"
+	"github.com/HappyLadySauce/component-base/pkg/scheme"
+)
+
+// ToUnstructured converts obj, a struct or a pointer to a struct, to its
+// unstructured content, following its JSON representation. Numbers are kept as
+// json.Number so that large integers do not lose precision.
+// ToUnstructured用来将结构体转换为非结构化的内容。
+func ToUnstructured(obj interface{}) (map[string]interface{}, error) {
+	content, err := toUnstructuredValue(obj)
+	if err != nil {
+		return nil, err
+	}
+	m, ok := content.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%T is not serialized as a JSON object", obj)
+	}
+
+	return m, nil
+}
+
+// FromUnstructured converts the unstructured content u to obj, a pointer to a
+// struct, following its JSON representation.
+// FromUnstructured用来将非结构化的内容转换为结构体。
+func FromUnstructured(u map[string]interface{}, obj interface{}) error {
+	data, err := json.Marshal(u)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, obj)
+}
+
+func toUnstructuredValue(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeValue(data)
+}
+
+// decodeValue decodes the JSON data into maps, slices and json.Number values.
+func decodeValue(data []byte) (interface{}, error) {
+	var content interface{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&content); err != nil {
+		return nil, err
+	}
+
+	return content, nil
+}
+
+// Converter converts objects between their unstructured and typed forms, with
+// the types registered in a scheme.
+// Converter用来根据Scheme在非结构化对象和结构化对象之间进行转换。
+type Converter struct {
+	scheme *runtime.Scheme
+}
+
+// NewConverter returns a Converter using the types registered in s.
+// NewConverter用来创建Converter。
+func NewConverter(s *runtime.Scheme) *Converter {
+	return &Converter{scheme: s}
+}
+
+// ToTyped converts u to a new object of the type registered for its kind.
+// ToTyped用来将非结构化对象转换为其Kind对应的结构化对象。
+func (c *Converter) ToTyped(u *Unstructured) (runtime.Object, error) {
+	gvk := u.GroupVersionKind()
+	if gvk.Empty() {
+		return nil, fmt.Errorf("unstructured object has no kind")
+	}
+
+	obj, err := c.scheme.New(gvk)
+	if err != nil {
+		return nil, err
+	}
+	if err := FromUnstructured(u.Object, obj); err != nil {
+		return nil, err
+	}
+	obj.GetObjectKind().SetGroupVersionKind(gvk)
+
+	return obj, nil
+}
+
+// FromTyped converts obj to an Unstructured, with the kind registered for obj.
+// FromTyped用来将结构化对象转换为非结构化对象。
+func (c *Converter) FromTyped(obj runtime.Object) (*Unstructured, error) {
+	obj = obj.DeepCopyObject()
+	if err := c.scheme.SetGroupVersionKind(obj); err != nil {
+		return nil, err
+	}
+
+	content, err := ToUnstructured(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Unstructured{Object: content}, nil
+}
+
+// ConvertToVersion converts u to the same kind in the version target, through
+// the conversion functions registered in the scheme.
+// ConvertToVersion用来将非结构化对象转换为目标版本。
+func (c *Converter) ConvertToVersion(u *Unstructured, target scheme.GroupVersion) (*Unstructured, error) {
+	obj, err := c.ToTyped(u)
+	if err != nil {
+		return nil, err
+	}
+	out, err := c.scheme.ConvertToVersion(obj, target)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.FromTyped(out)
+}
@@ -0,0 +1,353 @@
+// Copyright 2020 Lingfei Kong <colin404@foxmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package unstructured
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/HappyLadySauce/component-base/pkg/json"
+	"github.com/HappyLadySauce/component-base/pkg/runtime"
+)
+
+// NestedFieldNoCopy returns a reference to a nested field.
+// Returns false if value is not found and an error if unable
+// to traverse obj.
+// NestedFieldNoCopy用来获取嵌套字段的引用。
+func NestedFieldNoCopy(obj map[string]interface{}, fields ...string) (interface{}, bool, error) {
+	var val interface{} = obj
+
+	for i, field := range fields {
+		if val == nil {
+			return nil, false, nil
+		}
+		m, ok := val.(map[string]interface{})
+		if !ok {
+			return nil, false, fmt.Errorf(
+				"%v accessor error: %v is of the type %T, expected map[string]interface{}",
+				jsonPath(fields[:i+1]), val, val,
+			)
+		}
+		val, ok = m[field]
+		if !ok {
+			return nil, false, nil
+		}
+	}
+
+	return val, true, nil
+}
+
+// NestedFieldCopy returns a deep copy of the value of a nested field.
+// Returns false if the value is missing.
+// No error is returned for a nil field.
+// NestedFieldCopy用来获取嵌套字段的深拷贝。
+func NestedFieldCopy(obj map[string]interface{}, fields ...string) (interface{}, bool, error) {
+	val, found, err := NestedFieldNoCopy(obj, fields...)
+	if !found || err != nil {
+		return nil, found, err
+	}
+
+	return runtime.DeepCopyJSONValue(val), true, nil
+}
+
+// NestedString returns the string value of a nested field.
+// Returns false if value is not found and an error if not a string.
+// NestedString用来获取嵌套的字符串字段。
+func NestedString(obj map[string]interface{}, fields ...string) (string, bool, error) {
+	val, found, err := NestedFieldNoCopy(obj, fields...)
+	if !found || err != nil {
+		return "", found, err
+	}
+	s, ok := val.(string)
+	if !ok {
+		return "", false, fmt.Errorf("%v accessor error: %v is of the type %T, expected string", jsonPath(fields), val, val)
+	}
+
+	return s, true, nil
+}
+
+// NestedBool returns the bool value of a nested field.
+// Returns false if value is not found and an error if not a bool.
+// NestedBool用来获取嵌套的布尔字段。
+func NestedBool(obj map[string]interface{}, fields ...string) (bool, bool, error) {
+	val, found, err := NestedFieldNoCopy(obj, fields...)
+	if !found || err != nil {
+		return false, found, err
+	}
+	b, ok := val.(bool)
+	if !ok {
+		return false, false, fmt.Errorf("%v accessor error: %v is of the type %T, expected bool", jsonPath(fields), val, val)
+	}
+
+	return b, true, nil
+}
+
+// NestedInt64 returns the int64 value of a nested field. Numbers decoded as
+// float64 or json.Number are accepted when they hold an integer.
+// Returns false if value is not found and an error if not an integer.
+// NestedInt64用来获取嵌套的整数字段。
+func NestedInt64(obj map[string]interface{}, fields ...string) (int64, bool, error) {
+	val, found, err := NestedFieldNoCopy(obj, fields...)
+	if !found || err != nil {
+		return 0, found, err
+	}
+	i, ok := toInt64(val)
+	if !ok {
+		return 0, false, fmt.Errorf("%v accessor error: %v is of the type %T, expected int64", jsonPath(fields), val, val)
+	}
+
+	return i, true, nil
+}
+
+// NestedStringSlice returns a copy of []string value of a nested field.
+// Returns false if value is not found and an error if not a []interface{} or contains non-string items in the slice.
+// NestedStringSlice用来获取嵌套的字符串切片字段。
+func NestedStringSlice(obj map[string]interface{}, fields ...string) ([]string, bool, error) {
+	val, found, err := NestedFieldNoCopy(obj, fields...)
+	if !found || err != nil {
+		return nil, found, err
+	}
+	m, ok := val.([]interface{})
+	if !ok {
+		return nil, false, fmt.Errorf(
+			"%v accessor error: %v is of the type %T, expected []interface{}",
+			jsonPath(fields), val, val,
+		)
+	}
+
+	strSlice := make([]string, 0, len(m))
+	for _, v := range m {
+		str, ok := v.(string)
+		if !ok {
+			return nil, false, fmt.Errorf(
+				"%v accessor error: contains non-string value in the slice: %v is of the type %T, expected string",
+				jsonPath(fields), v, v,
+			)
+		}
+		strSlice = append(strSlice, str)
+	}
+
+	return strSlice, true, nil
+}
+
+// NestedStringMap returns a copy of map[string]string value of a nested field.
+// Returns false if value is not found and an error if not a map[string]interface{}
+// or contains non-string values in the map.
+// NestedStringMap用来获取嵌套的字符串映射字段。
+func NestedStringMap(obj map[string]interface{}, fields ...string) (map[string]string, bool, error) {
+	m, found, err := nestedMapNoCopy(obj, fields...)
+	if !found || err != nil {
+		return nil, found, err
+	}
+
+	strMap := make(map[string]string, len(m))
+	for k, v := range m {
+		str, ok := v.(string)
+		if !ok {
+			return nil, false, fmt.Errorf(
+				"%v accessor error: contains non-string value in the map under key %q: %v is of the type %T, expected string",
+				jsonPath(fields), k, v, v,
+			)
+		}
+		strMap[k] = str
+	}
+
+	return strMap, true, nil
+}
+
+// NestedSlice returns a deep copy of []interface{} value of a nested field.
+// Returns false if value is not found and an error if not a []interface{}.
+// NestedSlice用来获取嵌套的切片字段。
+func NestedSlice(obj map[string]interface{}, fields ...string) ([]interface{}, bool, error) {
+	val, found, err := NestedFieldNoCopy(obj, fields...)
+	if !found || err != nil {
+		return nil, found, err
+	}
+	s, ok := val.([]interface{})
+	if !ok {
+		return nil, false, fmt.Errorf(
+			"%v accessor error: %v is of the type %T, expected []interface{}",
+			jsonPath(fields), val, val,
+		)
+	}
+
+	return runtime.DeepCopyJSONValue(s).([]interface{}), true, nil
+}
+
+// NestedMap returns a deep copy of map[string]interface{} value of a nested field.
+// Returns false if value is not found and an error if not a map[string]interface{}.
+// NestedMap用来获取嵌套的映射字段。
+func NestedMap(obj map[string]interface{}, fields ...string) (map[string]interface{}, bool, error) {
+	m, found, err := nestedMapNoCopy(obj, fields...)
+	if !found || err != nil {
+		return nil, found, err
+	}
+
+	return runtime.DeepCopyJSONValue(m).(map[string]interface{}), true, nil
+}
+
+// nestedMapNoCopy returns a map[string]interface{} value of a nested field.
+// Returns false if value is not found and an error if not a map[string]interface{}.
+func nestedMapNoCopy(obj map[string]interface{}, fields ...string) (map[string]interface{}, bool, error) {
+	val, found, err := NestedFieldNoCopy(obj, fields...)
+	if !found || err != nil {
+		return nil, found, err
+	}
+	m, ok := val.(map[string]interface{})
+	if !ok {
+		return nil, false, fmt.Errorf(
+			"%v accessor error: %v is of the type %T, expected map[string]interface{}",
+			jsonPath(fields), val, val,
+		)
+	}
+
+	return m, true, nil
+}
+
+// SetNestedField sets the value of a nested field to a deep copy of the value provided.
+// Returns an error if value cannot be set because one of the nesting levels is not a map[string]interface{}.
+// SetNestedField用来设置嵌套字段的值。
+func SetNestedField(obj map[string]interface{}, value interface{}, fields ...string) error {
+	return setNestedFieldNoCopy(obj, runtime.DeepCopyJSONValue(value), fields...)
+}
+
+func setNestedFieldNoCopy(obj map[string]interface{}, value interface{}, fields ...string) error {
+	m := obj
+
+	for i, field := range fields[:len(fields)-1] {
+		if val, ok := m[field]; ok && val != nil {
+			valMap, ok := val.(map[string]interface{})
+			if !ok {
+				return fmt.Errorf(
+					"value cannot be set because %v is not a map[string]interface{}",
+					jsonPath(fields[:i+1]),
+				)
+			}
+			m = valMap
+		} else {
+			newVal := make(map[string]interface{})
+			m[field] = newVal
+			m = newVal
+		}
+	}
+	m[fields[len(fields)-1]] = value
+
+	return nil
+}
+
+// SetNestedStringSlice sets the string slice value of a nested field.
+// Returns an error if value cannot be set because one of the nesting levels is not a map[string]interface{}.
+// SetNestedStringSlice用来设置嵌套的字符串切片字段。
+func SetNestedStringSlice(obj map[string]interface{}, value []string, fields ...string) error {
+	m := make([]interface{}, 0, len(value))
+	for _, v := range value {
+		m = append(m, v)
+	}
+
+	return setNestedFieldNoCopy(obj, m, fields...)
+}
+
+// SetNestedStringMap sets the map[string]string value of a nested field.
+// Returns an error if value cannot be set because one of the nesting levels is not a map[string]interface{}.
+// SetNestedStringMap用来设置嵌套的字符串映射字段。
+func SetNestedStringMap(obj map[string]interface{}, value map[string]string, fields ...string) error {
+	m := make(map[string]interface{}, len(value))
+	for k, v := range value {
+		m[k] = v
+	}
+
+	return setNestedFieldNoCopy(obj, m, fields...)
+}
+
+// RemoveNestedField removes the nested field from the obj.
+// RemoveNestedField用来删除嵌套字段。
+func RemoveNestedField(obj map[string]interface{}, fields ...string) {
+	m := obj
+	for _, field := range fields[:len(fields)-1] {
+		x, ok := m[field].(map[string]interface{})
+		if !ok {
+			return
+		}
+		m = x
+	}
+	delete(m, fields[len(fields)-1])
+}
+
+// toInt64 converts the JSON number v to an int64.
+func toInt64(v interface{}) (int64, bool) {
+	switch n := v.(type) {
+	case int64:
+		return n, true
+	case int:
+		return int64(n), true
+	case int32:
+		return int64(n), true
+	case float64:
+		if n != math.Trunc(n) || n > math.MaxInt64 || n < math.MinInt64 {
+			return 0, false
+		}
+
+		return int64(n), true
+	case json.Number:
+		i, err := n.Int64()
+
+		return i, err == nil
+	default:
+		return 0, false
+	}
+}
+
+func jsonPath(fields []string) string {
+	return "." + strings.Join(fields, ".")
+}
+
+// getNestedString returns the string value of a nested field, or the empty
+// string if it is missing or not a string.
+func getNestedString(obj map[string]interface{}, fields ...string) string {
+	val, found, err := NestedString(obj, fields...)
+	if !found || err != nil {
+		return ""
+	}
+
+	return val
+}
+
+// getNestedInt64 returns the int64 value of a nested field, or 0 if it is
+// missing or not an integer.
+func getNestedInt64(obj map[string]interface{}, fields ...string) int64 {
+	val, found, err := NestedInt64(obj, fields...)
+	if !found || err != nil {
+		return 0
+	}
+
+	return val
+}
+
+// getNestedStruct decodes the value of a nested field into out. It reports
+// whether the field was found and decoded.
+func getNestedStruct(obj map[string]interface{}, out interface{}, fields ...string) bool {
+	val, found, err := NestedFieldNoCopy(obj, fields...)
+	if !found || err != nil || val == nil {
+		return false
+	}
+
+	data, err := json.Marshal(val)
+	if err != nil {
+		return false
+	}
+
+	return json.Unmarshal(data, out) == nil
+}
+
+// setNestedStruct sets a nested field to the JSON representation of value.
+func setNestedStruct(obj map[string]interface{}, value interface{}, fields ...string) {
+	content, err := toUnstructuredValue(value)
+	if err != nil {
+		// Values set through accessors are always serializable.
+		panic(err)
+	}
+	_ = setNestedFieldNoCopy(obj, content, fields...)
+}
@@ -0,0 +1,559 @@
+// Copyright 2020 Lingfei Kong <colin404@foxmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// Package unstructured provides objects holding arbitrary JSON content, so that
+// generic tooling, such as diff, apply or audit, can work on objects without
+// their compiled types.
+package unstructured // import "github.com/HappyLadySauce/component-base/pkg/meta/v1/unstructured"
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/HappyLadySauce/component-base/pkg/json"
+	metav1 "github.com/HappyLadySauce/component-base/pkg/meta/v1"
+	"github.com/HappyLadySauce/component-base/pkg/runtime"
+	"github.com/HappyLadySauce/component-base/pkg/scheme"
+)
+
+// JSONDict is the interface of the dictionaries of yunion.io/x/jsonutils, such
+// as *jsonutils.JSONDict, that an Unstructured can be built from.
+// JSONDict是jsonutils中字典类型的接口。
+type JSONDict interface {
+	// Interface returns the content of the dictionary as a map[string]interface{}.
+	Interface() interface{}
+}
+
+// Unstructured allows objects that do not have Golang structs registered to be manipulated
+// generically. This can be used to deal with the API objects from a plug-in. Unstructured
+// objects still have functioning TypeMeta features-- kind, version, etc. The ObjectMeta
+// accessors read and write the fields of the metadata map of the object.
+// Unstructured是用来在没有Go结构体的情况下操作对象的类型。
+type Unstructured struct {
+	// Object is a JSON compatible map with string, float, int, bool, []interface{}, or
+	// map[string]interface{} children.
+	Object map[string]interface{}
+}
+
+var (
+	_ runtime.Object    = &Unstructured{}
+	_ scheme.ObjectKind = &Unstructured{}
+	_ metav1.Object     = &Unstructured{}
+	_ metav1.Type       = &Unstructured{}
+)
+
+// NewFromJSONDict returns an Unstructured holding a copy of the content of dict.
+// NewFromJSONDict用来根据jsonutils字典创建Unstructured。
+func NewFromJSONDict(dict JSONDict) (*Unstructured, error) {
+	content, ok := dict.Interface().(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%T is not a JSON dictionary", dict)
+	}
+	// The numbers of jsonutils are int64 or float64, which are already valid JSON values.
+	data, err := json.Marshal(content)
+	if err != nil {
+		return nil, err
+	}
+	u := &Unstructured{}
+	if err := u.UnmarshalJSON(data); err != nil {
+		return nil, err
+	}
+
+	return u, nil
+}
+
+// UnstructuredContent returns the content of the object, it is not copied.
+// UnstructuredContent用来获取对象的内容。
+func (u *Unstructured) UnstructuredContent() map[string]interface{} {
+	if u.Object == nil {
+		u.Object = make(map[string]interface{})
+	}
+
+	return u.Object
+}
+
+// SetUnstructuredContent sets the content of the object.
+// SetUnstructuredContent用来设置对象的内容。
+func (u *Unstructured) SetUnstructuredContent(content map[string]interface{}) {
+	u.Object = content
+}
+
+// GetObjectKind returns the ObjectKind of the object.
+// 获取对象的ObjectKind。
+func (u *Unstructured) GetObjectKind() scheme.ObjectKind { return u }
+
+// DeepCopyObject returns a deep copy of the object.
+// 获取对象的深拷贝。
+func (u *Unstructured) DeepCopyObject() runtime.Object {
+	return u.DeepCopy()
+}
+
+// DeepCopy returns a deep copy of the object.
+// 获取对象的深拷贝。
+func (u *Unstructured) DeepCopy() *Unstructured {
+	if u == nil {
+		return nil
+	}
+	out := new(Unstructured)
+	if u.Object != nil {
+		out.Object = runtime.DeepCopyJSONValue(u.Object).(map[string]interface{})
+	}
+
+	return out
+}
+
+// MarshalJSON ensures that the unstructured object produces proper JSON when
+// passed to Go's standard JSON library.
+// MarshalJSON用来将对象序列化为JSON。
+func (u *Unstructured) MarshalJSON() ([]byte, error) {
+	return json.Marshal(u.UnstructuredContent())
+}
+
+// UnmarshalJSON ensures that the unstructured object properly decodes JSON
+// when passed to Go's standard JSON library. Numbers are kept as json.Number.
+// UnmarshalJSON用来从JSON反序列化对象。
+func (u *Unstructured) UnmarshalJSON(data []byte) error {
+	content, err := decodeValue(data)
+	if err != nil {
+		return err
+	}
+	m, ok := content.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("unstructured object must be a JSON object, got %T", content)
+	}
+	u.Object = m
+
+	return nil
+}
+
+// SetGroupVersionKind satisfies the ObjectKind interface.
+// 设置对象的GroupVersionKind。
+func (u *Unstructured) SetGroupVersionKind(gvk scheme.GroupVersionKind) {
+	apiVersion, kind := gvk.ToAPIVersionAndKind()
+	u.SetAPIVersion(apiVersion)
+	u.SetKind(kind)
+}
+
+// GroupVersionKind satisfies the ObjectKind interface.
+// 获取对象的GroupVersionKind。
+func (u *Unstructured) GroupVersionKind() scheme.GroupVersionKind {
+	return scheme.FromAPIVersionAndKind(u.GetAPIVersion(), u.GetKind())
+}
+
+// GetAPIVersion returns the API version of the object.
+// 获取对象的API版本。
+func (u *Unstructured) GetAPIVersion() string { return getNestedString(u.Object, "apiVersion") }
+
+// SetAPIVersion sets the API version of the object.
+// 设置对象的API版本。
+func (u *Unstructured) SetAPIVersion(version string) { u.setNestedString(version, "apiVersion") }
+
+// GetKind returns the kind of the object.
+// 获取对象的类型。
+func (u *Unstructured) GetKind() string { return getNestedString(u.Object, "kind") }
+
+// SetKind sets the kind of the object.
+// 设置对象的类型。
+func (u *Unstructured) SetKind(kind string) { u.setNestedString(kind, "kind") }
+
+// GetID returns the ID of the object.
+// 获取对象的ID。
+func (u *Unstructured) GetID() uint64 {
+	val, found, err := NestedFieldNoCopy(u.Object, "metadata", "id")
+	if !found || err != nil {
+		return 0
+	}
+	if n, ok := val.(json.Number); ok {
+		if id, err := strconv.ParseUint(n.String(), 10, 64); err == nil {
+			return id
+		}
+	}
+
+	return uint64(getNestedInt64(u.Object, "metadata", "id"))
+}
+
+// SetID sets the ID of the object.
+// 设置对象的ID。
+func (u *Unstructured) SetID(id uint64) {
+	if id == 0 {
+		RemoveNestedField(u.Object, "metadata", "id")
+
+		return
+	}
+	u.setNestedField(json.Number(strconv.FormatUint(id, 10)), "metadata", "id")
+}
+
+// GetInstanceID returns the instance ID of the object.
+// 获取对象的实例ID。
+func (u *Unstructured) GetInstanceID() string {
+	return getNestedString(u.Object, "metadata", "instanceID")
+}
+
+// SetInstanceID sets the instance ID of the object.
+// 设置对象的实例ID。
+func (u *Unstructured) SetInstanceID(instanceID string) {
+	u.setNestedString(instanceID, "metadata", "instanceID")
+}
+
+// GetName returns the name of the object.
+// 获取对象的名称。
+func (u *Unstructured) GetName() string { return getNestedString(u.Object, "metadata", "name") }
+
+// SetName sets the name of the object.
+// 设置对象的名称。
+func (u *Unstructured) SetName(name string) { u.setNestedString(name, "metadata", "name") }
+
+// GetCreatedAt returns the creation time of the object.
+// 获取对象的创建时间。
+func (u *Unstructured) GetCreatedAt() time.Time { return u.getNestedTime("metadata", "createdAt") }
+
+// SetCreatedAt sets the creation time of the object.
+// 设置对象的创建时间。
+func (u *Unstructured) SetCreatedAt(createdAt time.Time) {
+	u.setNestedTime(createdAt, "metadata", "createdAt")
+}
+
+// GetUpdatedAt returns the update time of the object.
+// 获取对象的更新时间。
+func (u *Unstructured) GetUpdatedAt() time.Time { return u.getNestedTime("metadata", "updatedAt") }
+
+// SetUpdatedAt sets the update time of the object.
+// 设置对象的更新时间。
+func (u *Unstructured) SetUpdatedAt(updatedAt time.Time) {
+	u.setNestedTime(updatedAt, "metadata", "updatedAt")
+}
+
+// GetManagedFields returns the managed fields of the object.
+// 获取对象的字段管理记录。
+func (u *Unstructured) GetManagedFields() metav1.ManagedFields {
+	var managedFields metav1.ManagedFields
+	if !getNestedStruct(u.Object, &managedFields, "metadata", "managedFields") {
+		return nil
+	}
+
+	return managedFields
+}
+
+// SetManagedFields sets the managed fields of the object.
+// 设置对象的字段管理记录。
+func (u *Unstructured) SetManagedFields(managedFields metav1.ManagedFields) {
+	if managedFields == nil {
+		RemoveNestedField(u.Object, "metadata", "managedFields")
+
+		return
+	}
+	setNestedStruct(u.UnstructuredContent(), managedFields, "metadata", "managedFields")
+}
+
+// GetDeletionTimestamp returns the deletion timestamp of the object.
+// 获取对象的删除时间。
+func (u *Unstructured) GetDeletionTimestamp() *time.Time {
+	timestamp := u.getNestedTime("metadata", "deletionTimestamp")
+	if timestamp.IsZero() {
+		return nil
+	}
+
+	return &timestamp
+}
+
+// SetDeletionTimestamp sets the deletion timestamp of the object.
+// 设置对象的删除时间。
+func (u *Unstructured) SetDeletionTimestamp(timestamp *time.Time) {
+	if timestamp == nil {
+		RemoveNestedField(u.Object, "metadata", "deletionTimestamp")
+
+		return
+	}
+	u.setNestedTime(*timestamp, "metadata", "deletionTimestamp")
+}
+
+// GetOwnerReferences returns the owner references of the object.
+// 获取对象的拥有者列表。
+func (u *Unstructured) GetOwnerReferences() []metav1.OwnerReference {
+	var references []metav1.OwnerReference
+	if !getNestedStruct(u.Object, &references, "metadata", "ownerReferences") {
+		return nil
+	}
+
+	return references
+}
+
+// SetOwnerReferences sets the owner references of the object.
+// 设置对象的拥有者列表。
+func (u *Unstructured) SetOwnerReferences(references []metav1.OwnerReference) {
+	if references == nil {
+		RemoveNestedField(u.Object, "metadata", "ownerReferences")
+
+		return
+	}
+	setNestedStruct(u.UnstructuredContent(), references, "metadata", "ownerReferences")
+}
+
+// GetFinalizers returns the finalizers of the object.
+// 获取对象的终结器列表。
+func (u *Unstructured) GetFinalizers() []string {
+	val, _, _ := NestedStringSlice(u.Object, "metadata", "finalizers")
+
+	return val
+}
+
+// SetFinalizers sets the finalizers of the object.
+// 设置对象的终结器列表。
+func (u *Unstructured) SetFinalizers(finalizers []string) {
+	if finalizers == nil {
+		RemoveNestedField(u.Object, "metadata", "finalizers")
+
+		return
+	}
+	_ = SetNestedStringSlice(u.UnstructuredContent(), finalizers, "metadata", "finalizers")
+}
+
+// GetLabels returns the labels of the object.
+// 获取对象的标签。
+func (u *Unstructured) GetLabels() map[string]string {
+	val, _, _ := NestedStringMap(u.Object, "metadata", "labels")
+
+	return val
+}
+
+// SetLabels sets the labels of the object.
+// 设置对象的标签。
+func (u *Unstructured) SetLabels(labels map[string]string) {
+	if labels == nil {
+		RemoveNestedField(u.Object, "metadata", "labels")
+
+		return
+	}
+	_ = SetNestedStringMap(u.UnstructuredContent(), labels, "metadata", "labels")
+}
+
+func (u *Unstructured) setNestedField(value interface{}, fields ...string) {
+	_ = setNestedFieldNoCopy(u.UnstructuredContent(), value, fields...)
+}
+
+func (u *Unstructured) setNestedString(value string, fields ...string) {
+	if len(value) == 0 {
+		RemoveNestedField(u.UnstructuredContent(), fields...)
+
+		return
+	}
+	u.setNestedField(value, fields...)
+}
+
+func (u *Unstructured) getNestedTime(fields ...string) time.Time {
+	var t time.Time
+	if !getNestedStruct(u.Object, &t, fields...) {
+		return time.Time{}
+	}
+
+	return t
+}
+
+func (u *Unstructured) setNestedTime(value time.Time, fields ...string) {
+	if value.IsZero() {
+		RemoveNestedField(u.UnstructuredContent(), fields...)
+
+		return
+	}
+	setNestedStruct(u.UnstructuredContent(), value, fields...)
+}
+
+// UnstructuredList allows lists that do not have Golang structs
+// registered to be manipulated generically. This can be used to deal
+// with the API lists from a plug-in.
+// UnstructuredList是用来在没有Go结构体的情况下操作列表的类型。
+type UnstructuredList struct {
+	// Object holds the fields of the list other than its items, such as its
+	// type and its ListMeta.
+	Object map[string]interface{}
+
+	// Items is a list of unstructured objects.
+	Items []Unstructured `json:"items"`
+}
+
+var (
+	_ runtime.Object       = &UnstructuredList{}
+	_ scheme.ObjectKind    = &UnstructuredList{}
+	_ metav1.ListInterface = &UnstructuredList{}
+	_ metav1.Type          = &UnstructuredList{}
+)
+
+// UnstructuredContent returns the content of the list, the items included.
+// UnstructuredContent用来获取列表的内容，包括列表项。
+func (u *UnstructuredList) UnstructuredContent() map[string]interface{} {
+	out := make(map[string]interface{}, len(u.Object)+1)
+	for k, v := range u.Object {
+		out[k] = v
+	}
+
+	items := make([]interface{}, 0, len(u.Items))
+	for _, item := range u.Items {
+		items = append(items, item.UnstructuredContent())
+	}
+	out["items"] = items
+
+	return out
+}
+
+// SetUnstructuredContent sets the content of the list, the items included.
+// SetUnstructuredContent用来设置列表的内容，包括列表项。
+func (u *UnstructuredList) SetUnstructuredContent(content map[string]interface{}) error {
+	items, _, err := NestedFieldNoCopy(content, "items")
+	if err != nil {
+		return err
+	}
+
+	u.Object = make(map[string]interface{}, len(content))
+	for k, v := range content {
+		if k != "items" {
+			u.Object[k] = v
+		}
+	}
+
+	u.Items = nil
+	if items == nil {
+		return nil
+	}
+	slice, ok := items.([]interface{})
+	if !ok {
+		return fmt.Errorf("items is of the type %T, expected []interface{}", items)
+	}
+	u.Items = make([]Unstructured, 0, len(slice))
+	for i, item := range slice {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("items[%d] is of the type %T, expected map[string]interface{}", i, item)
+		}
+		u.Items = append(u.Items, Unstructured{Object: m})
+	}
+
+	return nil
+}
+
+// EachListItem calls fn on each item of the list, stopping at the first error.
+// EachListItem用来遍历列表项。
+func (u *UnstructuredList) EachListItem(fn func(runtime.Object) error) error {
+	for i := range u.Items {
+		if err := fn(&u.Items[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetObjectKind returns the ObjectKind of the list.
+// 获取列表的ObjectKind。
+func (u *UnstructuredList) GetObjectKind() scheme.ObjectKind { return u }
+
+// DeepCopyObject returns a deep copy of the list.
+// 获取列表的深拷贝。
+func (u *UnstructuredList) DeepCopyObject() runtime.Object {
+	return u.DeepCopy()
+}
+
+// DeepCopy returns a deep copy of the list.
+// 获取列表的深拷贝。
+func (u *UnstructuredList) DeepCopy() *UnstructuredList {
+	if u == nil {
+		return nil
+	}
+	out := new(UnstructuredList)
+	if u.Object != nil {
+		out.Object = runtime.DeepCopyJSONValue(u.Object).(map[string]interface{})
+	}
+	if u.Items != nil {
+		out.Items = make([]Unstructured, len(u.Items))
+		for i := range u.Items {
+			out.Items[i] = *u.Items[i].DeepCopy()
+		}
+	}
+
+	return out
+}
+
+// MarshalJSON ensures that the unstructured list produces proper JSON when
+// passed to Go's standard JSON library.
+// MarshalJSON用来将列表序列化为JSON。
+func (u *UnstructuredList) MarshalJSON() ([]byte, error) {
+	return json.Marshal(u.UnstructuredContent())
+}
+
+// UnmarshalJSON ensures that the unstructured list properly decodes JSON
+// when passed to Go's standard JSON library.
+// UnmarshalJSON用来从JSON反序列化列表。
+func (u *UnstructuredList) UnmarshalJSON(data []byte) error {
+	content, err := decodeValue(data)
+	if err != nil {
+		return err
+	}
+	m, ok := content.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("unstructured list must be a JSON object, got %T", content)
+	}
+
+	return u.SetUnstructuredContent(m)
+}
+
+// SetGroupVersionKind satisfies the ObjectKind interface.
+// 设置列表的GroupVersionKind。
+func (u *UnstructuredList) SetGroupVersionKind(gvk scheme.GroupVersionKind) {
+	apiVersion, kind := gvk.ToAPIVersionAndKind()
+	u.SetAPIVersion(apiVersion)
+	u.SetKind(kind)
+}
+
+// GroupVersionKind satisfies the ObjectKind interface.
+// 获取列表的GroupVersionKind。
+func (u *UnstructuredList) GroupVersionKind() scheme.GroupVersionKind {
+	return scheme.FromAPIVersionAndKind(u.GetAPIVersion(), u.GetKind())
+}
+
+// GetAPIVersion returns the API version of the list.
+// 获取列表的API版本。
+func (u *UnstructuredList) GetAPIVersion() string { return getNestedString(u.Object, "apiVersion") }
+
+// SetAPIVersion sets the API version of the list.
+// 设置列表的API版本。
+func (u *UnstructuredList) SetAPIVersion(version string) { u.setNestedField(version, "apiVersion") }
+
+// GetKind returns the kind of the list.
+// 获取列表的类型。
+func (u *UnstructuredList) GetKind() string { return getNestedString(u.Object, "kind") }
+
+// SetKind sets the kind of the list.
+// 设置列表的类型。
+func (u *UnstructuredList) SetKind(kind string) { u.setNestedField(kind, "kind") }
+
+// GetListMeta returns the ListMeta of the list.
+// 获取列表的ListMeta。
+func (u *UnstructuredList) GetListMeta() metav1.ListInterface { return u }
+
+// GetTotalCount returns the total count of the list.
+// 获取列表的总数。
+func (u *UnstructuredList) GetTotalCount() int64 { return getNestedInt64(u.Object, "totalCount") }
+
+// SetTotalCount sets the total count of the list.
+// 设置列表的总数。
+func (u *UnstructuredList) SetTotalCount(count int64) {
+	if count == 0 {
+		RemoveNestedField(u.Object, "totalCount")
+
+		return
+	}
+	u.setNestedField(count, "totalCount")
+}
+
+func (u *UnstructuredList) setNestedField(value interface{}, fields ...string) {
+	if u.Object == nil {
+		u.Object = make(map[string]interface{})
+	}
+	if s, ok := value.(string); ok && len(s) == 0 {
+		RemoveNestedField(u.Object, fields...)
+
+		return
+	}
+	_ = setNestedFieldNoCopy(u.Object, value, fields...)
+}
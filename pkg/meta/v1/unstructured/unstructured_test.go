@@ -0,0 +1,269 @@
+// Copyright 2020 Lingfei Kong <colin404@foxmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package unstructured
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/HappyLadySauce/component-base/pkg/json"
+	metav1 "github.com/HappyLadySauce/component-base/pkg/meta/v1"
+	"github.com/HappyLadySauce/component-base/pkg/runtime"
+	"github.com/HappyLadySauce/component-base/pkg/scheme"
+)
+
+var testGroupVersion = scheme.GroupVersion{Group: "iam.example.com", Version: "v1"}
+
+type Secret struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Username string `json:"username"`
+	Expires  int64  `json:"expires,omitempty"`
+}
+
+func (s *Secret) DeepCopyObject() runtime.Object {
+	out := *s
+	out.ObjectMeta = *s.ObjectMeta.DeepCopy()
+
+	return &out
+}
+
+// jsonDict mimics the dictionaries of jsonutils, whose numbers are int64 or float64.
+type jsonDict map[string]interface{}
+
+func (d jsonDict) Interface() interface{} { return map[string]interface{}(d) }
+
+func TestNestedFields(t *testing.T) {
+	obj := map[string]interface{}{
+		"spec": map[string]interface{}{
+			"name":     "foo",
+			"enabled":  true,
+			"replicas": json.Number("3"),
+			"ratio":    float64(2),
+			"tags":     []interface{}{"a", "b"},
+			"labels":   map[string]interface{}{"app": "iam"},
+		},
+	}
+
+	if s, found, err := NestedString(obj, "spec", "name"); s != "foo" || !found || err != nil {
+		t.Errorf("unexpected string %q, %v, %v", s, found, err)
+	}
+	if b, found, err := NestedBool(obj, "spec", "enabled"); !b || !found || err != nil {
+		t.Errorf("unexpected bool %v, %v, %v", b, found, err)
+	}
+	for _, field := range []string{"replicas", "ratio"} {
+		if _, found, err := NestedInt64(obj, "spec", field); !found || err != nil {
+			t.Errorf("%s: unexpected int64 %v, %v", field, found, err)
+		}
+	}
+	if tags, _, err := NestedStringSlice(obj, "spec", "tags"); !reflect.DeepEqual(tags, []string{"a", "b"}) {
+		t.Errorf("unexpected slice %v, %v", tags, err)
+	}
+	labels, _, err := NestedStringMap(obj, "spec", "labels")
+	if !reflect.DeepEqual(labels, map[string]string{"app": "iam"}) {
+		t.Errorf("unexpected map %v, %v", labels, err)
+	}
+	if _, found, err := NestedString(obj, "spec", "missing"); found || err != nil {
+		t.Errorf("expected a missing field, got %v, %v", found, err)
+	}
+	if _, _, err := NestedString(obj, "spec", "name", "first"); err == nil {
+		t.Errorf("expected an error traversing a string")
+	}
+	if _, _, err := NestedInt64(obj, "spec", "name"); err == nil {
+		t.Errorf("expected an error for a string")
+	}
+
+	// The copies do not share memory with obj.
+	spec, _, _ := NestedMap(obj, "spec")
+	spec["name"] = "bar"
+	if s, _, _ := NestedString(obj, "spec", "name"); s != "foo" {
+		t.Errorf("expected a deep copy, got %q", s)
+	}
+
+	if err := SetNestedField(obj, "bar", "spec", "template", "name"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s, _, _ := NestedString(obj, "spec", "template", "name"); s != "bar" {
+		t.Errorf("unexpected string %q", s)
+	}
+	if err := SetNestedField(obj, "bar", "spec", "name", "first"); err == nil {
+		t.Errorf("expected an error setting a field under a string")
+	}
+
+	RemoveNestedField(obj, "spec", "template", "name")
+	RemoveNestedField(obj, "spec", "missing", "name")
+	if template, found, _ := NestedMap(obj, "spec", "template"); !found || len(template) != 0 {
+		t.Errorf("unexpected template %v", template)
+	}
+}
+
+func TestUnstructuredAccessors(t *testing.T) {
+	u := &Unstructured{}
+	u.SetGroupVersionKind(testGroupVersion.WithKind("Secret"))
+	u.SetID(1<<63 + 1)
+	u.SetName("foo")
+	u.SetInstanceID("secret-1")
+	u.SetLabels(map[string]string{"app": "iam"})
+	u.SetFinalizers([]string{"iam.example.com/cleanup"})
+	createdAt := time.Date(2020, 8, 1, 10, 0, 0, 0, time.UTC)
+	u.SetCreatedAt(createdAt)
+	u.SetDeletionTimestamp(&createdAt)
+	u.SetOwnerReferences([]metav1.OwnerReference{{Kind: "User", Name: "colin", InstanceID: "user-1"}})
+	u.SetManagedFields(metav1.ManagedFields{{Manager: "iamctl", Fields: []string{"/name"}}})
+
+	data, err := json.Marshal(u)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out := &Unstructured{}
+	if err := json.Unmarshal(data, out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if out.GroupVersionKind() != testGroupVersion.WithKind("Secret") || out.GetKind() != "Secret" {
+		t.Errorf("unexpected kind %v", out.GroupVersionKind())
+	}
+	if out.GetID() != 1<<63+1 || out.GetName() != "foo" || out.GetInstanceID() != "secret-1" {
+		t.Errorf("unexpected identity %d, %q, %q", out.GetID(), out.GetName(), out.GetInstanceID())
+	}
+	if !out.GetCreatedAt().Equal(createdAt) || !out.GetDeletionTimestamp().Equal(createdAt) {
+		t.Errorf("unexpected times %v, %v", out.GetCreatedAt(), out.GetDeletionTimestamp())
+	}
+	if !out.GetUpdatedAt().IsZero() {
+		t.Errorf("unexpected update time %v", out.GetUpdatedAt())
+	}
+	if !reflect.DeepEqual(out.GetLabels(), u.GetLabels()) || !reflect.DeepEqual(out.GetFinalizers(), u.GetFinalizers()) {
+		t.Errorf("unexpected labels %v or finalizers %v", out.GetLabels(), out.GetFinalizers())
+	}
+	references := out.GetOwnerReferences()
+	if !reflect.DeepEqual(references, u.GetOwnerReferences()) || references[0].Name != "colin" {
+		t.Errorf("unexpected owner references %v", references)
+	}
+	if managedFields := out.GetManagedFields(); len(managedFields) != 1 || managedFields[0].Manager != "iamctl" {
+		t.Errorf("unexpected managed fields %v", managedFields)
+	}
+
+	out.SetDeletionTimestamp(nil)
+	out.SetLabels(nil)
+	out.SetName("")
+	for _, field := range []string{"deletionTimestamp", "labels", "name"} {
+		if _, found, _ := NestedFieldNoCopy(out.Object, "metadata", field); found {
+			t.Errorf("expected %s to be removed", field)
+		}
+	}
+	if _, ok := out.Object["name"]; ok {
+		t.Errorf("expected the metadata not to be written at the top level")
+	}
+
+	clone := u.DeepCopyObject().(*Unstructured)
+	clone.SetName("bar")
+	if u.GetName() != "foo" {
+		t.Errorf("expected a deep copy, got %q", u.GetName())
+	}
+}
+
+func TestNewFromJSONDict(t *testing.T) {
+	u, err := NewFromJSONDict(jsonDict{
+		"kind":     "Secret",
+		"metadata": map[string]interface{}{"id": int64(3)},
+		"spec":     map[string]interface{}{"ratio": 0.5},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if u.GetKind() != "Secret" || u.GetID() != 3 {
+		t.Errorf("unexpected object %v", u.Object)
+	}
+	if ratio, _, _ := NestedFieldNoCopy(u.Object, "spec", "ratio"); ratio != json.Number("0.5") {
+		t.Errorf("unexpected ratio %#v", ratio)
+	}
+}
+
+func TestUnstructuredList(t *testing.T) {
+	data := `{"apiVersion":"iam.example.com/v1","kind":"SecretList","totalCount":2,` +
+		`"items":[{"kind":"Secret","metadata":{"name":"foo"}},{"kind":"Secret","metadata":{"name":"bar"}}]}`
+
+	list := &UnstructuredList{}
+	if err := json.Unmarshal([]byte(data), list); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if list.GetKind() != "SecretList" || list.GetListMeta().GetTotalCount() != 2 || len(list.Items) != 2 {
+		t.Fatalf("unexpected list %#v", list)
+	}
+
+	var names []string
+	_ = list.EachListItem(func(obj runtime.Object) error {
+		names = append(names, obj.(metav1.Object).GetName())
+
+		return nil
+	})
+	if !reflect.DeepEqual(names, []string{"foo", "bar"}) {
+		t.Errorf("unexpected names %v", names)
+	}
+
+	clone := list.DeepCopy()
+	clone.SetTotalCount(3)
+	clone.Items[0].SetName("baz")
+	if list.GetTotalCount() != 2 || list.Items[0].GetName() != "foo" {
+		t.Errorf("expected a deep copy")
+	}
+
+	encoded, err := json.Marshal(clone)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := `{"apiVersion":"iam.example.com/v1","items":[{"kind":"Secret","metadata":{"name":"baz"}},` +
+		`{"kind":"Secret","metadata":{"name":"bar"}}],"kind":"SecretList","totalCount":3}`
+	if string(encoded) != expected {
+		t.Errorf("expected %s, got %s", expected, encoded)
+	}
+
+	if err := json.Unmarshal([]byte(`{"items":{}}`), list); err == nil {
+		t.Errorf("expected an error for malformed items")
+	}
+}
+
+func TestConverter(t *testing.T) {
+	s := runtime.NewScheme()
+	s.AddKnownTypes(testGroupVersion, &Secret{})
+	c := NewConverter(s)
+
+	u := &Unstructured{}
+	if err := u.UnmarshalJSON([]byte(`{"kind":"Secret","apiVersion":"iam.example.com/v1","metadata":` +
+		`{"id":9007199254740993,"name":"foo","createdAt":"2020-08-01T10:00:00Z"},"username":"colin"}`)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	obj, err := c.ToTyped(u)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	secret, ok := obj.(*Secret)
+	if !ok || secret.Username != "colin" || secret.ID != 9007199254740993 || secret.CreatedAt.IsZero() {
+		t.Fatalf("unexpected object %#v", obj)
+	}
+
+	secret.Expires = 3600
+	back, err := c.FromTyped(secret)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expires, _, _ := NestedInt64(back.Object, "expires"); expires != 3600 || back.GetID() != 9007199254740993 {
+		t.Errorf("unexpected object %v", back.Object)
+	}
+	if back.GroupVersionKind() != testGroupVersion.WithKind("Secret") {
+		t.Errorf("unexpected kind %v", back.GroupVersionKind())
+	}
+
+	u.SetKind("Policy")
+	if _, err := c.ToTyped(u); !runtime.IsNotRegisteredError(err) {
+		t.Errorf("expected a not registered error, got %v", err)
+	}
+	if _, err := c.ToTyped(&Unstructured{}); err == nil {
+		t.Errorf("expected an error for an object without kind")
+	}
+}
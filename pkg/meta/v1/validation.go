@@ -0,0 +1,27 @@
+// Copyright 2020 Lingfei Kong <colin404@foxmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package v1
+
+import (
+	"github.com/HappyLadySauce/component-base/pkg/validation/field"
+)
+
+// ValidateObjectMetaUpdate validates that the read-only fields of an object
+// (ID, InstanceID and CreatedAt) were not changed by an update.
+// ValidateObjectMetaUpdate用来校验更新时只读字段没有被修改。
+func ValidateObjectMetaUpdate(newMeta, oldMeta Object, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if newMeta.GetID() != oldMeta.GetID() {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("id"), "field is immutable"))
+	}
+	if newMeta.GetInstanceID() != oldMeta.GetInstanceID() {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("instanceID"), "field is immutable"))
+	}
+	if !newMeta.GetCreatedAt().Equal(oldMeta.GetCreatedAt()) {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("createdAt"), "field is immutable"))
+	}
+
+	return allErrs
+}
@@ -0,0 +1,219 @@
+// Copyright 2020 Lingfei Kong <colin404@foxmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package openapi
+
+import (
+	"fmt"
+
+	metav1 "github.com/HappyLadySauce/component-base/pkg/meta/v1"
+	"github.com/HappyLadySauce/component-base/pkg/scheme"
+)
+
+// Verbs supported by a resource.
+const (
+	VerbList   = "list"
+	VerbCreate = "create"
+	VerbGet    = "get"
+	VerbUpdate = "update"
+	VerbPatch  = "patch"
+	VerbDelete = "delete"
+	VerbWatch  = "watch"
+)
+
+// DefaultVerbs are the verbs of a resource added without verbs.
+var DefaultVerbs = []string{VerbList, VerbCreate, VerbGet, VerbUpdate, VerbPatch, VerbDelete}
+
+// Resource is a REST resource serving a registered kind.
+// Resource是提供某个注册类型的REST资源。
+type Resource struct {
+	// Kind is the kind of the objects of the resource.
+	Kind scheme.GroupVersionKind
+	// Name is the plural name of the resource, guessed from the kind when empty.
+	Name string
+	// SingularName is the singular name of the resource, guessed from the kind when empty.
+	SingularName string
+	// ShortNames are the aliases of the resource.
+	ShortNames []string
+	// Categories are the groups of resources the resource belongs to.
+	Categories []string
+	// Verbs are the verbs supported by the resource, DefaultVerbs when empty.
+	Verbs []string
+}
+
+// AddResource adds a resource to the discovery document and the paths of the
+// OpenAPI document. An error is returned if its kind is not registered.
+// AddResource用来添加REST资源。
+func (g *Generator) AddResource(r Resource) error {
+	if !g.scheme.Recognizes(r.Kind) {
+		return fmt.Errorf("kind %s is not registered", r.Kind)
+	}
+
+	plural, singular := scheme.UnsafeGuessKindToResource(r.Kind)
+	if r.Name == "" {
+		r.Name = plural.Resource
+	}
+	if r.SingularName == "" {
+		r.SingularName = singular.Resource
+	}
+	if len(r.Verbs) == 0 {
+		r.Verbs = DefaultVerbs
+	}
+	g.resources = append(g.resources, r)
+
+	return nil
+}
+
+// APIResource describes a resource in the discovery document.
+// APIResource是发现文档中的资源。
+type APIResource struct {
+	Name         string   `json:"name"`
+	SingularName string   `json:"singularName"`
+	Kind         string   `json:"kind"`
+	Verbs        []string `json:"verbs"`
+	ShortNames   []string `json:"shortNames,omitempty"`
+	Categories   []string `json:"categories,omitempty"`
+}
+
+// APIResourceList lists the resources of a group version.
+// APIResourceList是某个组版本下的资源列表。
+type APIResourceList struct {
+	metav1.TypeMeta `json:",inline"`
+
+	GroupVersion string        `json:"groupVersion"`
+	Resources    []APIResource `json:"resources"`
+}
+
+// GroupVersionForDiscovery is a version of a group.
+// GroupVersionForDiscovery是组中的一个版本。
+type GroupVersionForDiscovery struct {
+	GroupVersion string `json:"groupVersion"`
+	Version      string `json:"version"`
+}
+
+// APIGroup describes the versions of a group.
+// APIGroup描述了一个组的所有版本。
+type APIGroup struct {
+	metav1.TypeMeta `json:",inline"`
+
+	Name             string                     `json:"name"`
+	Versions         []GroupVersionForDiscovery `json:"versions"`
+	PreferredVersion GroupVersionForDiscovery   `json:"preferredVersion"`
+}
+
+// APIGroupList lists the groups serving resources.
+// APIGroupList是提供资源的组列表。
+type APIGroupList struct {
+	metav1.TypeMeta `json:",inline"`
+
+	Groups []APIGroup `json:"groups"`
+}
+
+// APIGroups returns the groups serving the added resources. The versions of a
+// group follow the priority of the scheme, the first one being preferred.
+// APIGroups用来返回提供资源的组列表。
+func (g *Generator) APIGroups() *APIGroupList {
+	list := &APIGroupList{TypeMeta: metav1.TypeMeta{Kind: "APIGroupList", APIVersion: "v1"}, Groups: []APIGroup{}}
+	for _, gv := range g.groupVersions() {
+		var group *APIGroup
+		for i := range list.Groups {
+			if list.Groups[i].Name == gv.Group {
+				group = &list.Groups[i]
+			}
+		}
+		if group == nil {
+			list.Groups = append(list.Groups, APIGroup{
+				TypeMeta:         metav1.TypeMeta{Kind: "APIGroup", APIVersion: "v1"},
+				Name:             gv.Group,
+				PreferredVersion: versionForDiscovery(gv),
+			})
+			group = &list.Groups[len(list.Groups)-1]
+		}
+		group.Versions = append(group.Versions, versionForDiscovery(gv))
+	}
+
+	return list
+}
+
+// APIGroup returns the group name, or nil if it serves no resources.
+// APIGroup用来返回指定的组。
+func (g *Generator) APIGroup(name string) *APIGroup {
+	for _, group := range g.APIGroups().Groups {
+		if group.Name == name {
+			group := group
+
+			return &group
+		}
+	}
+
+	return nil
+}
+
+// APIResources returns the resources of the group version gv, or nil if it
+// serves no resources.
+// APIResources用来返回组版本下的资源列表。
+func (g *Generator) APIResources(gv scheme.GroupVersion) *APIResourceList {
+	var resources []APIResource
+	for _, r := range g.resources {
+		if r.Kind.GroupVersion() != gv {
+			continue
+		}
+		resources = append(resources, APIResource{
+			Name:         r.Name,
+			SingularName: r.SingularName,
+			Kind:         r.Kind.Kind,
+			Verbs:        r.Verbs,
+			ShortNames:   r.ShortNames,
+			Categories:   r.Categories,
+		})
+	}
+	if resources == nil {
+		return nil
+	}
+
+	return &APIResourceList{
+		TypeMeta:     metav1.TypeMeta{Kind: "APIResourceList", APIVersion: "v1"},
+		GroupVersion: gv.String(),
+		Resources:    resources,
+	}
+}
+
+// RESTMapper returns a RESTMapper of the added resources, preferring the
+// versions of the scheme in priority order.
+// RESTMapper用来返回已添加资源的RESTMapper。
+func (g *Generator) RESTMapper() *scheme.DefaultRESTMapper {
+	mapper := scheme.NewDefaultRESTMapper(g.groupVersions())
+	for _, r := range g.resources {
+		gv := r.Kind.GroupVersion()
+		mapper.AddSpecific(r.Kind, gv.WithResource(r.Name), gv.WithResource(r.SingularName))
+		gr := scheme.GroupResource{Group: gv.Group, Resource: r.Name}
+		mapper.AddShortNames(gr, r.ShortNames...)
+		mapper.AddCategories(gr, r.Categories...)
+	}
+
+	return mapper
+}
+
+// groupVersions returns the group versions serving resources, in the priority
+// order of the scheme.
+func (g *Generator) groupVersions() []scheme.GroupVersion {
+	served := map[scheme.GroupVersion]bool{}
+	for _, r := range g.resources {
+		served[r.Kind.GroupVersion()] = true
+	}
+
+	var gvs []scheme.GroupVersion
+	for _, gv := range g.scheme.PrioritizedVersionsAllGroups() {
+		if served[gv] {
+			gvs = append(gvs, gv)
+			delete(served, gv)
+		}
+	}
+
+	return gvs
+}
+
+func versionForDiscovery(gv scheme.GroupVersion) GroupVersionForDiscovery {
+	return GroupVersionForDiscovery{GroupVersion: gv.String(), Version: gv.Version}
+}
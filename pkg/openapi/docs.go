@@ -0,0 +1,132 @@
+// Copyright 2020 Lingfei Kong <colin404@foxmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package openapi
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"strings"
+)
+
+// swaggerModel is the annotation naming the schema of a type. The type name is
+// used when no name follows it.
+const swaggerModel = "swagger:model"
+
+// TypeDocs is the documentation of a type, parsed from its doc comments.
+// TypeDocs是从注释中解析的类型文档。
+type TypeDocs struct {
+	// Description is the doc comment of the type, without its annotations.
+	Description string
+	// Model is the name of the schema of the type, set by a swagger:model annotation.
+	Model string
+	// Fields are the doc comments of the fields of a struct, by Go field name.
+	Fields map[string]string
+}
+
+// Docs are the documentation of the types of a package, by type name.
+// Docs是包中各类型的文档。
+type Docs map[string]*TypeDocs
+
+// ParseDocs parses the doc comments of the types declared by the Go files of
+// the directory dir, test files excluded.
+// ParseDocs用来解析目录中Go文件的类型注释。
+func ParseDocs(dir string) (Docs, error) {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, dir, func(info os.FileInfo) bool {
+		return !strings.HasSuffix(info.Name(), "_test.go")
+	}, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	docs := Docs{}
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Files {
+			for _, decl := range file.Decls {
+				gen, ok := decl.(*ast.GenDecl)
+				if !ok || gen.Tok != token.TYPE {
+					continue
+				}
+				for _, spec := range gen.Specs {
+					typeSpec := spec.(*ast.TypeSpec)
+					doc := typeSpec.Doc
+					if doc == nil && len(gen.Specs) == 1 {
+						doc = gen.Doc
+					}
+					docs[typeSpec.Name.Name] = parseTypeDocs(doc, typeSpec)
+				}
+			}
+		}
+	}
+
+	return docs, nil
+}
+
+func parseTypeDocs(doc *ast.CommentGroup, spec *ast.TypeSpec) *TypeDocs {
+	typeDocs := &TypeDocs{Fields: map[string]string{}}
+
+	var lines []string
+	for _, line := range strings.Split(doc.Text(), "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(line, swaggerModel):
+			typeDocs.Model = strings.TrimSpace(strings.TrimPrefix(line, swaggerModel))
+			if typeDocs.Model == "" {
+				typeDocs.Model = spec.Name.Name
+			}
+		case strings.HasPrefix(line, "+"):
+			// Code generation markers, such as +deepcopy-gen, are not documentation.
+		case line != "":
+			lines = append(lines, line)
+		}
+	}
+	typeDocs.Description = strings.Join(lines, "\n")
+
+	if st, ok := spec.Type.(*ast.StructType); ok {
+		for _, f := range st.Fields.List {
+			text := f.Doc.Text()
+			if text == "" {
+				text = f.Comment.Text()
+			}
+			text = strings.TrimSpace(text)
+			if text == "" {
+				continue
+			}
+			for _, name := range fieldNames(f) {
+				typeDocs.Fields[name] = text
+			}
+		}
+	}
+
+	return typeDocs
+}
+
+// fieldNames returns the Go names of the fields declared by f.
+func fieldNames(f *ast.Field) []string {
+	if len(f.Names) > 0 {
+		names := make([]string, 0, len(f.Names))
+		for _, name := range f.Names {
+			names = append(names, name.Name)
+		}
+
+		return names
+	}
+
+	// Embedded fields are named after their type.
+	t := f.Type
+	if star, ok := t.(*ast.StarExpr); ok {
+		t = star.X
+	}
+	switch t := t.(type) {
+	case *ast.Ident:
+		return []string{t.Name}
+	case *ast.SelectorExpr:
+		return []string{t.Sel.Name}
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,58 @@
+// Copyright 2020 Lingfei Kong <colin404@foxmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package openapi
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/HappyLadySauce/component-base/pkg/core"
+	"github.com/HappyLadySauce/component-base/pkg/scheme"
+)
+
+// InstallHandlers serves the discovery document and the OpenAPI v3 document of g:
+// GET /apis lists the groups, GET /apis/{group} describes a group, GET
+// /apis/{group}/{version} and GET /api/{version} list the resources of a group
+// version, and GET /openapi/v3 returns the OpenAPI document described by info.
+// InstallHandlers用来注册发现文档和OpenAPI文档的处理函数。
+func (g *Generator) InstallHandlers(r gin.IRoutes, info Info) {
+	r.GET("/apis", func(c *gin.Context) {
+		c.JSON(http.StatusOK, g.APIGroups())
+	})
+	r.GET("/apis/:group", func(c *gin.Context) {
+		group := g.APIGroup(c.Param("group"))
+		if group == nil {
+			writeError(c, http.StatusNotFound, fmt.Errorf("group %q not found", c.Param("group")))
+
+			return
+		}
+		c.JSON(http.StatusOK, group)
+	})
+	r.GET("/apis/:group/:version", func(c *gin.Context) {
+		g.serveResources(c, scheme.GroupVersion{Group: c.Param("group"), Version: c.Param("version")})
+	})
+	r.GET("/api/:version", func(c *gin.Context) {
+		g.serveResources(c, scheme.GroupVersion{Version: c.Param("version")})
+	})
+	r.GET("/openapi/v3", func(c *gin.Context) {
+		c.JSON(http.StatusOK, g.OpenAPI(info))
+	})
+}
+
+func (g *Generator) serveResources(c *gin.Context, gv scheme.GroupVersion) {
+	resources := g.APIResources(gv)
+	if resources == nil {
+		writeError(c, http.StatusNotFound, fmt.Errorf("group version %q not found", gv))
+
+		return
+	}
+	c.JSON(http.StatusOK, resources)
+}
+
+func writeError(c *gin.Context, status int, err error) {
+	c.JSON(status, core.ErrResponse{Code: status, Message: err.Error()})
+}
@@ -0,0 +1,134 @@
+// Copyright 2020 Lingfei Kong <colin404@foxmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package openapi
+
+import (
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/HappyLadySauce/component-base/pkg/core"
+)
+
+const mediaTypeJSON = "application/json"
+
+var errResponseType = reflect.TypeOf(core.ErrResponse{})
+
+// OpenAPI returns the OpenAPI v3 document of the added resources, with the
+// schemas of all the registered kinds.
+// OpenAPI用来生成OpenAPI v3文档。
+func (g *Generator) OpenAPI(info Info) *Document {
+	b := g.newBuilder()
+	for _, gvk := range g.kinds() {
+		b.define(g.scheme.AllKnownTypes()[gvk], DefinitionName(gvk))
+	}
+	errSchema := b.schemaFor(errResponseType)
+
+	paths := map[string]*PathItem{}
+	for _, r := range g.resources {
+		collection, item := resourcePaths(r)
+		if _, ok := paths[collection]; !ok {
+			paths[collection] = &PathItem{}
+		}
+		if _, ok := paths[item]; !ok {
+			paths[item] = &PathItem{Parameters: []*Parameter{{
+				Name:        "name",
+				In:          "path",
+				Description: "name of the " + r.Kind.Kind,
+				Required:    true,
+				Schema:      &Schema{Type: "string"},
+			}}}
+		}
+		g.addOperations(paths[collection], paths[item], r, errSchema)
+	}
+
+	return &Document{
+		OpenAPI:    Version,
+		Info:       info,
+		Paths:      paths,
+		Components: Components{Schemas: b.schemas},
+	}
+}
+
+// resourcePaths returns the paths of the collection and of the items of r.
+func resourcePaths(r Resource) (collection, item string) {
+	gv := r.Kind.GroupVersion()
+	if gv.Group == "" {
+		collection = "/api/" + gv.Version + "/" + r.Name
+	} else {
+		collection = "/apis/" + gv.Group + "/" + gv.Version + "/" + r.Name
+	}
+
+	return collection, collection + "/{name}"
+}
+
+func (g *Generator) addOperations(collection, item *PathItem, r Resource, errSchema *Schema) {
+	object := RefTo(DefinitionName(r.Kind))
+	list := &Schema{Type: "array", Items: object}
+	if listKind := r.Kind.GroupVersion().WithKind(r.Kind.Kind + "List"); g.scheme.Recognizes(listKind) {
+		list = RefTo(DefinitionName(listKind))
+	}
+
+	tags := []string{r.Kind.GroupVersion().String()}
+	suffix := operationSuffix(r)
+	operation := func(verb, summary string, status int, response *Schema, body bool) *Operation {
+		op := &Operation{
+			OperationID: verb + suffix,
+			Summary:     summary,
+			Tags:        tags,
+			Responses: map[string]*Response{
+				strconv.Itoa(status): jsonResponse(http.StatusText(status), response),
+				"default":            jsonResponse("error", errSchema),
+			},
+		}
+		if body {
+			op.RequestBody = &RequestBody{Required: true, Content: map[string]*MediaType{mediaTypeJSON: {Schema: object}}}
+		}
+
+		return op
+	}
+
+	for _, verb := range r.Verbs {
+		switch verb {
+		case VerbList:
+			collection.Get = operation("list", "list objects of kind "+r.Kind.Kind, http.StatusOK, list, false)
+		case VerbCreate:
+			collection.Post = operation("create", "create a "+r.Kind.Kind, http.StatusCreated, object, true)
+		case VerbGet:
+			item.Get = operation("read", "read the specified "+r.Kind.Kind, http.StatusOK, object, false)
+		case VerbUpdate:
+			item.Put = operation("replace", "replace the specified "+r.Kind.Kind, http.StatusOK, object, true)
+		case VerbPatch:
+			item.Patch = operation("patch", "partially update the specified "+r.Kind.Kind, http.StatusOK, object, true)
+			item.Patch.RequestBody.Content = map[string]*MediaType{
+				"application/json-patch+json":  {Schema: &Schema{}},
+				"application/merge-patch+json": {Schema: &Schema{}},
+			}
+		case VerbDelete:
+			item.Delete = operation("delete", "delete the specified "+r.Kind.Kind, http.StatusOK, nil, false)
+		}
+	}
+}
+
+// operationSuffix returns the suffix of the operation IDs of r, such as V1Secret
+// or IamV1Secret.
+func operationSuffix(r Resource) string {
+	var parts []string
+	for _, part := range strings.FieldsFunc(r.Kind.Group, func(c rune) bool { return c == '.' || c == '-' }) {
+		parts = append(parts, strings.Title(part))
+	}
+
+	return strings.Join(parts, "") + strings.Title(r.Kind.Version) + r.Kind.Kind
+}
+
+func jsonResponse(description string, schema *Schema) *Response {
+	response := &Response{Description: description}
+	if schema != nil {
+		response.Content = map[string]*MediaType{mediaTypeJSON: {Schema: schema}}
+	}
+
+	return response
+}
@@ -0,0 +1,344 @@
+// Copyright 2020 Lingfei Kong <colin404@foxmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	metav1 "github.com/HappyLadySauce/component-base/pkg/meta/v1"
+	"github.com/HappyLadySauce/component-base/pkg/runtime"
+	"github.com/HappyLadySauce/component-base/pkg/scheme"
+	"github.com/HappyLadySauce/component-base/pkg/validation"
+)
+
+type Secret struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Username is the owner of the secret.
+	Username    string   `json:"username" validate:"required"`
+	SecretKey   string   `json:"secretKey,omitempty" validate:"omitempty,min=8,max=32"`
+	Expires     int64    `json:"expires,omitempty" validate:"gte=0"`
+	Description string   `json:"description,omitempty" validate:"description"`
+	Algorithm   string   `json:"algorithm,omitempty" validate:"oneof=hmac rsa"`
+	Emails      []string `json:"emails,omitempty" validate:"max=3,dive,email"`
+	Data        []byte   `json:"data,omitempty"`
+}
+
+func (s *Secret) DeepCopyObject() runtime.Object {
+	out := *s
+
+	return &out
+}
+
+type SecretList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:",inline"`
+
+	Items []Secret `json:"items"`
+}
+
+func (s *SecretList) DeepCopyObject() runtime.Object {
+	out := *s
+
+	return &out
+}
+
+var (
+	iamV1  = scheme.GroupVersion{Group: "iam.example.com", Version: "v1"}
+	iamV2  = scheme.GroupVersion{Group: "iam.example.com", Version: "v2"}
+	coreV1 = scheme.GroupVersion{Version: "v1"}
+)
+
+func newTestGenerator(t *testing.T) *Generator {
+	s := runtime.NewScheme()
+	s.AddKnownTypes(iamV2, &Secret{})
+	s.AddKnownTypes(iamV1, &Secret{}, &SecretList{})
+	s.AddKnownTypes(coreV1, &Secret{})
+
+	g := NewGenerator(s)
+	for pkgPath, dir := range map[string]string{
+		"github.com/HappyLadySauce/component-base/pkg/openapi": ".",
+		"github.com/HappyLadySauce/component-base/pkg/core":    "../core",
+	} {
+		docs, err := ParseDocs(dir)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		g.AddDocs(pkgPath, docs)
+	}
+	// Test types are not parsed by ParseDocs.
+	g.AddDocs("github.com/HappyLadySauce/component-base/pkg/openapi", Docs{
+		"Secret": {
+			Description: "Secret is a secret.",
+			Fields:      map[string]string{"ObjectMeta": "Object metadata."},
+		},
+	})
+
+	for _, r := range []Resource{
+		{Kind: iamV1.WithKind("Secret"), ShortNames: []string{"sec"}},
+		{Kind: iamV2.WithKind("Secret"), Verbs: []string{VerbGet, VerbList}},
+		{Kind: coreV1.WithKind("Secret")},
+	} {
+		if err := g.AddResource(r); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	return g
+}
+
+func TestParseDocs(t *testing.T) {
+	docs, err := ParseDocs("../core")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	errResponse := docs["ErrResponse"]
+	if errResponse == nil || errResponse.Model != "ErrResponse" {
+		t.Fatalf("unexpected docs %#v", errResponse)
+	}
+	if errResponse.Description != "ErrResponse defines the return messages when an error occurred.\n"+
+		"Reference will be omitted if it does not exist." {
+		t.Errorf("unexpected description %q", errResponse.Description)
+	}
+	if errResponse.Fields["Code"] != "Code defines the business error code." {
+		t.Errorf("unexpected field docs %v", errResponse.Fields)
+	}
+}
+
+func TestSchemas(t *testing.T) {
+	schemas := newTestGenerator(t).Schemas()
+
+	secret := schemas["iam.example.com.v1.Secret"]
+	if secret == nil {
+		t.Fatalf("missing schema in %v", schemas)
+	}
+	if secret.Description != "Secret is a secret." {
+		t.Errorf("unexpected description %q", secret.Description)
+	}
+	if expected := []string{"username"}; !reflect.DeepEqual(secret.Required, expected) {
+		t.Errorf("expected required %v, got %v", expected, secret.Required)
+	}
+	if _, ok := schemas["v1.Secret"]; !ok {
+		t.Errorf("missing schema of the core kind")
+	}
+
+	expected := map[string]string{
+		"kind":        `{"type":"string"}`,
+		"metadata":    `{"allOf":[{"$ref":"#/components/schemas/v1.ObjectMeta"}],"description":"Object metadata."}`,
+		"username":    `{"type":"string"}`,
+		"secretKey":   `{"type":"string","minLength":8,"maxLength":32}`,
+		"expires":     `{"type":"integer","format":"int64","minimum":0}`,
+		"description": `{"type":"string","maxLength":255}`,
+		"algorithm":   `{"type":"string","enum":["hmac","rsa"]}`,
+		"emails":      `{"type":"array","items":{"type":"string"},"maxItems":3}`,
+		"data":        `{"type":"string","format":"byte"}`,
+	}
+	for name, schema := range expected {
+		data, _ := json.Marshal(secret.Properties[name])
+		if string(data) != schema {
+			t.Errorf("%s: expected %s, got %s", name, schema, data)
+		}
+	}
+
+	meta := schemas["v1.ObjectMeta"]
+	if meta == nil {
+		t.Fatalf("missing schema of ObjectMeta in %v", schemas)
+	}
+	if name := meta.Properties["name"]; name.Pattern != qualifiedNamePattern || *name.MaxLength != 317 {
+		t.Errorf("unexpected schema of name %#v", name)
+	}
+	if createdAt := meta.Properties["createdAt"]; createdAt.Format != "date-time" {
+		t.Errorf("unexpected schema of createdAt %#v", createdAt)
+	}
+}
+
+func TestQualifiedNamePattern(t *testing.T) {
+	pattern := regexp.MustCompile(qualifiedNamePattern)
+	for _, name := range []string{"MyName", "my.name", "123-abc", "example.com/MyName", "a.b-c/d_e"} {
+		if len(validation.IsQualifiedName(name)) != 0 || !pattern.MatchString(name) {
+			t.Errorf("%s: expected a valid name", name)
+		}
+	}
+	for _, name := range []string{"", "-a", "a/", "/a", "Example.com/a", "a/b/c"} {
+		if len(validation.IsQualifiedName(name)) == 0 || pattern.MatchString(name) {
+			t.Errorf("%s: expected an invalid name", name)
+		}
+	}
+}
+
+type embeddedBase struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type embedFirst struct {
+	embeddedBase
+	ID int `json:"id"`
+}
+
+func TestPromotedFieldsDominance(t *testing.T) {
+	b := &builder{g: NewGenerator(runtime.NewScheme()), schemas: map[string]*Schema{}, names: map[reflect.Type]string{}}
+	s := b.structSchema(reflect.TypeOf(embedFirst{}))
+
+	// The outer field hides the promoted one, even when the embedded struct is declared first.
+	if id := s.Properties["id"]; id == nil || id.Type != "integer" {
+		t.Errorf("expected id to be an integer, got %#v", id)
+	}
+	if name := s.Properties["name"]; name == nil || name.Type != "string" {
+		t.Errorf("expected the promoted name, got %#v", name)
+	}
+}
+
+func TestApplyRulesInOrder(t *testing.T) {
+	testCases := []struct {
+		tag       string
+		maxLength int64
+	}{
+		{"name,max=32", 32},
+		{"max=32,name", qualifiedNameMaxLength},
+		{"description,len=8", 8},
+		{"len=8,description", descriptionMaxLength},
+	}
+
+	for _, tc := range testCases {
+		// Repeated, since rules applied in map order would only fail now and then.
+		for i := 0; i < 10; i++ {
+			s := &Schema{Type: "string"}
+			applyRules(s, validateRules(tc.tag))
+			if s.MaxLength == nil || *s.MaxLength != tc.maxLength {
+				t.Fatalf("%s: expected maxLength %d, got %v", tc.tag, tc.maxLength, s.MaxLength)
+			}
+		}
+	}
+}
+
+func TestOpenAPI(t *testing.T) {
+	doc := newTestGenerator(t).OpenAPI(Info{Title: "iam", Version: "v1"})
+
+	if doc.OpenAPI != Version {
+		t.Errorf("unexpected version %q", doc.OpenAPI)
+	}
+	if _, ok := doc.Components.Schemas["ErrResponse"]; !ok {
+		t.Errorf("missing schema of ErrResponse")
+	}
+
+	collection := doc.Paths["/apis/iam.example.com/v1/secrets"]
+	if collection == nil || collection.Get == nil || collection.Post == nil {
+		t.Fatalf("unexpected collection path %#v", collection)
+	}
+	if collection.Get.OperationID != "listIamExampleComV1Secret" {
+		t.Errorf("unexpected operation id %q", collection.Get.OperationID)
+	}
+	list := collection.Get.Responses["200"].Content[mediaTypeJSON].Schema
+	if list.Ref != RefTo("iam.example.com.v1.SecretList").Ref {
+		t.Errorf("unexpected list schema %#v", list)
+	}
+
+	item := doc.Paths["/apis/iam.example.com/v2/secrets/{name}"]
+	if item == nil || item.Get == nil || item.Put != nil || item.Delete != nil {
+		t.Errorf("unexpected item path %#v", item)
+	}
+	if _, ok := doc.Paths["/api/v1/secrets/{name}"]; !ok {
+		t.Errorf("missing path of the core group")
+	}
+}
+
+func TestAddResourceNotRegistered(t *testing.T) {
+	g := NewGenerator(runtime.NewScheme())
+	if err := g.AddResource(Resource{Kind: iamV1.WithKind("Secret")}); err == nil {
+		t.Errorf("expected an error")
+	}
+}
+
+func TestRESTMapper(t *testing.T) {
+	mapper := newTestGenerator(t).RESTMapper()
+
+	gvk, err := mapper.KindFor(scheme.GroupVersionResource{Group: "iam.example.com", Resource: "sec"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Short names resolve to the preferred version.
+	if gvk != iamV2.WithKind("Secret") {
+		t.Errorf("unexpected kind %v", gvk)
+	}
+}
+
+func TestInstallHandlers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	newTestGenerator(t).InstallHandlers(r, Info{Title: "iam", Version: "v1"})
+
+	get := func(path string, v interface{}) int {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		if v != nil && w.Code == http.StatusOK {
+			if err := json.Unmarshal(w.Body.Bytes(), v); err != nil {
+				t.Fatalf("%s: unexpected error: %v", path, err)
+			}
+		}
+
+		return w.Code
+	}
+
+	var groups APIGroupList
+	if code := get("/apis", &groups); code != http.StatusOK {
+		t.Fatalf("unexpected status %d", code)
+	}
+	expectedGroups := []APIGroup{
+		{
+			TypeMeta: metav1.TypeMeta{Kind: "APIGroup", APIVersion: "v1"},
+			Name:     "iam.example.com",
+			Versions: []GroupVersionForDiscovery{
+				{GroupVersion: "iam.example.com/v2", Version: "v2"},
+				{GroupVersion: "iam.example.com/v1", Version: "v1"},
+			},
+			PreferredVersion: GroupVersionForDiscovery{GroupVersion: "iam.example.com/v2", Version: "v2"},
+		},
+		{
+			TypeMeta:         metav1.TypeMeta{Kind: "APIGroup", APIVersion: "v1"},
+			Versions:         []GroupVersionForDiscovery{{GroupVersion: "v1", Version: "v1"}},
+			PreferredVersion: GroupVersionForDiscovery{GroupVersion: "v1", Version: "v1"},
+		},
+	}
+	if !reflect.DeepEqual(groups.Groups, expectedGroups) {
+		t.Errorf("expected %#v, got %#v", expectedGroups, groups.Groups)
+	}
+
+	var resources APIResourceList
+	if code := get("/apis/iam.example.com/v1", &resources); code != http.StatusOK {
+		t.Fatalf("unexpected status %d", code)
+	}
+	expectedResource := APIResource{
+		Name:         "secrets",
+		SingularName: "secret",
+		Kind:         "Secret",
+		Verbs:        DefaultVerbs,
+		ShortNames:   []string{"sec"},
+	}
+	if len(resources.Resources) != 1 || !reflect.DeepEqual(resources.Resources[0], expectedResource) {
+		t.Errorf("unexpected resources %#v", resources.Resources)
+	}
+
+	for path, expected := range map[string]int{
+		"/apis/iam.example.com":    http.StatusOK,
+		"/api/v1":                  http.StatusOK,
+		"/openapi/v3":              http.StatusOK,
+		"/apis/unknown":            http.StatusNotFound,
+		"/apis/iam.example.com/v3": http.StatusNotFound,
+	} {
+		if code := get(path, nil); code != expected {
+			t.Errorf("%s: expected status %d, got %d", path, expected, code)
+		}
+	}
+}
@@ -0,0 +1,396 @@
+// Copyright 2020 Lingfei Kong <colin404@foxmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// Package openapi generates the OpenAPI v3 document and the discovery document
+// of the types registered in a scheme, so that API specs follow the Go types.
+package openapi // import "github.com/HappyLadySauce/component-base/pkg/openapi"
+
+import (
+	"encoding/json"
+	"path"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	utiljson "github.com/HappyLadySauce/component-base/pkg/json"
+	"github.com/HappyLadySauce/component-base/pkg/runtime"
+	"github.com/HappyLadySauce/component-base/pkg/scheme"
+	"github.com/HappyLadySauce/component-base/pkg/validation"
+)
+
+// Generator generates the OpenAPI v3 schemas of the types registered in a
+// scheme, along with the paths and the discovery document of the resources
+// added to it.
+// Generator是用来根据Scheme中注册的类型生成OpenAPI v3文档和发现文档的生成器。
+type Generator struct {
+	scheme    *runtime.Scheme
+	docs      map[string]Docs
+	resources []Resource
+}
+
+// NewGenerator returns a Generator of the types registered in s.
+// NewGenerator用来创建Generator。
+func NewGenerator(s *runtime.Scheme) *Generator {
+	return &Generator{scheme: s, docs: map[string]Docs{}}
+}
+
+// AddDocs adds the documentation of the types of the package pkgPath, usually
+// parsed by ParseDocs. Types are described by their doc comments, and the schemas
+// of the types annotated with swagger:model are named after their model.
+// AddDocs用来添加某个包中类型的文档。
+func (g *Generator) AddDocs(pkgPath string, docs Docs) {
+	g.docs[pkgPath] = docs
+}
+
+// Schemas returns the schemas of the registered kinds, by DefinitionName, along
+// with the schemas of the named structs they reference.
+// Schemas用来返回注册类型的模式。
+func (g *Generator) Schemas() map[string]*Schema {
+	b := g.newBuilder()
+	for _, gvk := range g.kinds() {
+		b.define(g.scheme.AllKnownTypes()[gvk], DefinitionName(gvk))
+	}
+
+	return b.schemas
+}
+
+// DefinitionName returns the name of the schema of the kind gvk.
+// DefinitionName用来返回Kind对应的模式名称。
+func DefinitionName(gvk scheme.GroupVersionKind) string {
+	if gvk.Group == "" {
+		return gvk.Version + "." + gvk.Kind
+	}
+
+	return gvk.Group + "." + gvk.Version + "." + gvk.Kind
+}
+
+// kinds returns the registered external kinds, sorted.
+func (g *Generator) kinds() []scheme.GroupVersionKind {
+	var kinds []scheme.GroupVersionKind
+	for gvk := range g.scheme.AllKnownTypes() {
+		if gvk.Version != runtime.APIVersionInternal {
+			kinds = append(kinds, gvk)
+		}
+	}
+	sort.Slice(kinds, func(i, j int) bool { return kinds[i].String() < kinds[j].String() })
+
+	return kinds
+}
+
+func (g *Generator) newBuilder() *builder {
+	b := &builder{g: g, schemas: map[string]*Schema{}, names: map[reflect.Type]string{}}
+	for _, gvk := range g.kinds() {
+		t := g.scheme.AllKnownTypes()[gvk]
+		if _, ok := b.names[t]; !ok {
+			b.names[t] = DefinitionName(gvk)
+		}
+	}
+
+	return b
+}
+
+// typeDocs returns the documentation of t, if any.
+func (g *Generator) typeDocs(t reflect.Type) *TypeDocs {
+	if docs, ok := g.docs[t.PkgPath()]; ok {
+		return docs[t.Name()]
+	}
+
+	return nil
+}
+
+var (
+	timeType      = reflect.TypeOf(time.Time{})
+	marshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+)
+
+// builder builds the schemas of the types of a Generator.
+type builder struct {
+	g       *Generator
+	schemas map[string]*Schema
+	// names are the schema names of the registered types.
+	names map[reflect.Type]string
+}
+
+// nameOf returns the name of the schema of the named struct t.
+func (b *builder) nameOf(t reflect.Type) string {
+	if name, ok := b.names[t]; ok {
+		return name
+	}
+	if docs := b.g.typeDocs(t); docs != nil && docs.Model != "" {
+		return docs.Model
+	}
+
+	return path.Base(t.PkgPath()) + "." + t.Name()
+}
+
+// define adds the schema of the struct t, named name, and returns a reference to it.
+func (b *builder) define(t reflect.Type, name string) *Schema {
+	if _, ok := b.schemas[name]; !ok {
+		// The schema is added before it is built to stop recursive types.
+		s := &Schema{}
+		b.schemas[name] = s
+		*s = *b.structSchema(t)
+	}
+
+	return RefTo(name)
+}
+
+// schemaFor returns the schema of the JSON representation of t.
+func (b *builder) schemaFor(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case t.Implements(marshalerType) || reflect.PtrTo(t).Implements(marshalerType):
+		// The representation of a custom marshaler is unknown.
+		return &Schema{}
+	}
+
+	//nolint: exhaustive
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+
+		return &Schema{Type: "array", Items: b.schemaFor(t.Elem())}
+	case reflect.Array:
+		return &Schema{Type: "array", Items: b.schemaFor(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: b.schemaFor(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return b.structSchema(t)
+		}
+
+		return b.define(t, b.nameOf(t))
+	default:
+		// Interfaces hold any value.
+		return &Schema{}
+	}
+}
+
+// structSchema returns the schema of the struct t, following the JSON names of
+// its fields and promoting the fields of its embedded structs.
+func (b *builder) structSchema(t reflect.Type) *Schema {
+	s := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	if docs := b.g.typeDocs(t); docs != nil {
+		s.Description = docs.Description
+	}
+	b.addFields(s, t)
+	sort.Strings(s.Required)
+
+	return s
+}
+
+// addFields adds the fields of the struct t to s. Like encoding/json, the fields
+// of embedded structs are promoted unless a shallower field of the same name
+// hides them, whatever the order of the declarations.
+func (b *builder) addFields(s *Schema, t reflect.Type) {
+	fields := utiljson.Fields(t)
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		index := fields[name]
+		f := t.FieldByIndex(index)
+		opts := strings.Split(f.Tag.Get("json"), ",")
+
+		// The docs of a promoted field are those of the struct declaring it.
+		owner := t
+		if len(index) > 1 {
+			owner = t.FieldByIndex(index[:len(index)-1]).Type
+			if owner.Kind() == reflect.Ptr {
+				owner = owner.Elem()
+			}
+		}
+		docs := b.g.typeDocs(owner)
+
+		prop := b.schemaFor(f.Type)
+		rules := validateRules(f.Tag.Get("validate"))
+		if prop.Ref == "" {
+			applyRules(prop, rules)
+		}
+		if docs != nil && docs.Fields[f.Name] != "" {
+			if prop.Ref != "" {
+				// The siblings of $ref are ignored.
+				prop = &Schema{AllOf: []*Schema{prop}}
+			}
+			prop.Description = docs.Fields[f.Name]
+		}
+		s.Properties[name] = prop
+
+		if hasRule(rules, "required") || !hasOption(opts[1:], "omitempty") {
+			s.Required = append(s.Required, name)
+		}
+	}
+}
+
+func hasOption(opts []string, option string) bool {
+	for _, opt := range opts {
+		if opt == option {
+			return true
+		}
+	}
+
+	return false
+}
+
+// validateRule is a rule of a validate struct tag, such as min=8.
+type validateRule struct {
+	name  string
+	param string
+}
+
+// validateRules returns the rules of a validate struct tag, in the order of the
+// tag. The rules applying to the elements of the field, after dive, are ignored.
+func validateRules(tag string) []validateRule {
+	var rules []validateRule
+	for _, rule := range strings.Split(tag, ",") {
+		if rule == "dive" {
+			break
+		}
+		name, param := rule, ""
+		if i := strings.Index(rule, "="); i >= 0 {
+			name, param = rule[:i], rule[i+1:]
+		}
+		if name != "" {
+			rules = append(rules, validateRule{name: name, param: param})
+		}
+	}
+
+	return rules
+}
+
+func hasRule(rules []validateRule, name string) bool {
+	for _, rule := range rules {
+		if rule.name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Constraints of the custom validators of pkg/validation.
+const (
+	// qualifiedNamePattern matches the names accepted by the name validator: a name
+	// with an optional DNS subdomain prefix and '/', such as example.com/MyName.
+	qualifiedNamePattern = "^(" + validation.DNS1123SubdomainFmt + "/)?" + validation.QualifiedNameFmt + "$"
+	// qualifiedNameMaxLength is the length of the longest prefixed name.
+	qualifiedNameMaxLength = int64(validation.DNS1123SubdomainMaxLength + 1 + validation.QualifiedNameMaxLength)
+	// descriptionMaxLength is the length accepted by the description validator.
+	descriptionMaxLength = validation.MaxDescriptionLength
+)
+
+// validatorFormats are the formats of the validators checking a well-known format.
+var validatorFormats = map[string]string{
+	"email":    "email",
+	"url":      "uri",
+	"uri":      "uri",
+	"uuid":     "uuid",
+	"ipv4":     "ipv4",
+	"ipv6":     "ipv6",
+	"hostname": "hostname",
+	"datetime": "date-time",
+}
+
+// applyRules adds the constraints checked by the validator rules to s, in order:
+// a rule overrides the constraints set by the previous ones, as with name,max=32.
+func applyRules(s *Schema, rules []validateRule) {
+	for _, rule := range rules {
+		name, param := rule.name, rule.param
+		if format, ok := validatorFormats[name]; ok && s.Type == "string" {
+			s.Format = format
+		}
+
+		switch name {
+		case "name":
+			s.Pattern = qualifiedNamePattern
+			s.MaxLength = int64Ptr(qualifiedNameMaxLength)
+		case "description":
+			s.MaxLength = int64Ptr(descriptionMaxLength)
+		case "min", "gte":
+			setBound(s, param, true)
+		case "max", "lte":
+			setBound(s, param, false)
+		case "len":
+			setBound(s, param, true)
+			setBound(s, param, false)
+		case "oneof":
+			for _, value := range strings.Fields(param) {
+				s.Enum = append(s.Enum, enumValue(s, value))
+			}
+		}
+	}
+}
+
+// setBound sets the lower or upper bound of s: its length, its number of items
+// or its value, depending on its type.
+func setBound(s *Schema, param string, lower bool) {
+	n, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		return
+	}
+
+	switch s.Type {
+	case "string":
+		if lower {
+			s.MinLength = int64Ptr(int64(n))
+		} else {
+			s.MaxLength = int64Ptr(int64(n))
+		}
+	case "array":
+		if lower {
+			s.MinItems = int64Ptr(int64(n))
+		} else {
+			s.MaxItems = int64Ptr(int64(n))
+		}
+	case "integer", "number":
+		if lower {
+			s.Minimum = &n
+		} else {
+			s.Maximum = &n
+		}
+	}
+}
+
+func enumValue(s *Schema, value string) interface{} {
+	switch s.Type {
+	case "integer":
+		if i, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return i
+		}
+	case "number":
+		if f, err := strconv.ParseFloat(value, 64); err == nil {
+			return f
+		}
+	}
+
+	return value
+}
+
+func int64Ptr(i int64) *int64 {
+	return &i
+}
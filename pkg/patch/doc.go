@@ -0,0 +1,11 @@
+// Copyright 2020 Lingfei Kong <colin404@foxmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// Package patch applies RFC 6902 JSON Patch and RFC 7386 JSON Merge Patch
+// documents to API objects.
+//
+// Documents are decoded with component-base/pkg/json into the generic
+// map[string]interface{} / []interface{} model, with numbers kept as json.Number
+// so that large integer IDs survive a round trip.
+package patch // import "github.com/HappyLadySauce/component-base/pkg/patch"
@@ -0,0 +1,325 @@
+// Copyright 2020 Lingfei Kong <colin404@foxmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package patch
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/HappyLadySauce/component-base/pkg/json"
+)
+
+// Operation is a single RFC 6902 JSON Patch operation.
+// Operation是JSON Patch中的单个操作。
+type Operation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	From  string          `json:"from,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// String returns the string format of Operation.
+func (o Operation) String() string {
+	if o.From != "" {
+		return fmt.Sprintf("%s %s from %s", o.Op, o.Path, o.From)
+	}
+
+	return o.Op + " " + o.Path
+}
+
+// JSONPatch is an ordered list of RFC 6902 operations.
+// JSONPatch是有序的JSON Patch操作列表。
+type JSONPatch []Operation
+
+// DecodeJSONPatch decodes an RFC 6902 patch document.
+// DecodeJSONPatch用来解码JSON Patch文档。
+func DecodeJSONPatch(data []byte) (JSONPatch, error) {
+	var p JSONPatch
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("invalid JSON patch: %w", err)
+	}
+
+	return p, nil
+}
+
+// Apply applies the patch to a decoded document and returns the patched document.
+// Operations are applied in order and the first failing operation aborts the patch.
+// The document may be modified in place.
+// Apply用来将JSON Patch应用到文档上。
+func (p JSONPatch) Apply(doc interface{}) (interface{}, error) {
+	var err error
+	for i, op := range p {
+		if doc, err = applyOperation(doc, op); err != nil {
+			return nil, fmt.Errorf("operation %d (%s): %w", i, op, err)
+		}
+	}
+
+	return doc, nil
+}
+
+func applyOperation(doc interface{}, op Operation) (interface{}, error) {
+	path, err := parsePointer(op.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch op.Op {
+	case "add":
+		value, err := op.value()
+		if err != nil {
+			return nil, err
+		}
+
+		return add(doc, path, value)
+	case "remove":
+		doc, _, err = remove(doc, path)
+
+		return doc, err
+	case "replace":
+		value, err := op.value()
+		if err != nil {
+			return nil, err
+		}
+		if len(path) == 0 {
+			return value, nil
+		}
+		if doc, _, err = remove(doc, path); err != nil {
+			return nil, err
+		}
+
+		return add(doc, path, value)
+	case "move":
+		from, err := parsePointer(op.From)
+		if err != nil {
+			return nil, err
+		}
+		if isProperPrefix(from, path) {
+			return nil, fmt.Errorf("cannot move %q into one of its children", op.From)
+		}
+		doc, value, err := remove(doc, from)
+		if err != nil {
+			return nil, err
+		}
+
+		return add(doc, path, value)
+	case "copy":
+		from, err := parsePointer(op.From)
+		if err != nil {
+			return nil, err
+		}
+		value, err := get(doc, from)
+		if err != nil {
+			return nil, err
+		}
+
+		return add(doc, path, deepCopy(value))
+	case "test":
+		value, err := op.value()
+		if err != nil {
+			return nil, err
+		}
+		actual, err := get(doc, path)
+		if err != nil {
+			return nil, err
+		}
+		if !Equal(actual, value) {
+			return nil, fmt.Errorf("test failed: value at %q does not match", op.Path)
+		}
+
+		return doc, nil
+	default:
+		return nil, fmt.Errorf("unsupported operation %q", op.Op)
+	}
+}
+
+// value decodes the value member of the operation. It is required for add,
+// replace and test, and may be an explicit null.
+func (o Operation) value() (interface{}, error) {
+	if len(o.Value) == 0 {
+		return nil, fmt.Errorf("missing value")
+	}
+
+	return Decode(o.Value)
+}
+
+// get returns the value referenced by path.
+func get(doc interface{}, path []string) (interface{}, error) {
+	node := doc
+	for i, token := range path {
+		switch n := node.(type) {
+		case map[string]interface{}:
+			child, ok := n[token]
+			if !ok {
+				return nil, fmt.Errorf("path %q not found", formatPointer(path[:i+1]))
+			}
+			node = child
+		case []interface{}:
+			index, err := arrayIndex(token, len(n), false)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", formatPointer(path[:i+1]), err)
+			}
+			node = n[index]
+		default:
+			return nil, fmt.Errorf("path %q not found", formatPointer(path[:i+1]))
+		}
+	}
+
+	return node, nil
+}
+
+// modify walks to the parent of the last token in path and calls fn with it.
+// The possibly replaced parent is stored back into its own parent, so that
+// arrays may grow and shrink.
+func modify(
+	node interface{},
+	path []string,
+	depth int,
+	fn func(parent interface{}, token string) (interface{}, error),
+) (interface{}, error) {
+	if depth == len(path)-1 {
+		return fn(node, path[depth])
+	}
+
+	token := path[depth]
+	switch n := node.(type) {
+	case map[string]interface{}:
+		child, ok := n[token]
+		if !ok {
+			return nil, fmt.Errorf("path %q not found", formatPointer(path[:depth+1]))
+		}
+		child, err := modify(child, path, depth+1, fn)
+		if err != nil {
+			return nil, err
+		}
+		n[token] = child
+
+		return n, nil
+	case []interface{}:
+		index, err := arrayIndex(token, len(n), false)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", formatPointer(path[:depth+1]), err)
+		}
+		child, err := modify(n[index], path, depth+1, fn)
+		if err != nil {
+			return nil, err
+		}
+		n[index] = child
+
+		return n, nil
+	default:
+		return nil, fmt.Errorf("path %q not found", formatPointer(path[:depth+1]))
+	}
+}
+
+// add implements the RFC 6902 add operation.
+func add(doc interface{}, path []string, value interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+
+	return modify(doc, path, 0, func(parent interface{}, token string) (interface{}, error) {
+		switch p := parent.(type) {
+		case map[string]interface{}:
+			p[token] = value
+
+			return p, nil
+		case []interface{}:
+			index, err := arrayIndex(token, len(p), true)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", formatPointer(path), err)
+			}
+			p = append(p, nil)
+			copy(p[index+1:], p[index:])
+			p[index] = value
+
+			return p, nil
+		default:
+			return nil, fmt.Errorf("cannot add %q: parent is not an object or array", formatPointer(path))
+		}
+	})
+}
+
+// remove implements the RFC 6902 remove operation and returns the removed value.
+func remove(doc interface{}, path []string) (interface{}, interface{}, error) {
+	if len(path) == 0 {
+		return nil, nil, fmt.Errorf("cannot remove the whole document")
+	}
+
+	var removed interface{}
+	doc, err := modify(doc, path, 0, func(parent interface{}, token string) (interface{}, error) {
+		switch p := parent.(type) {
+		case map[string]interface{}:
+			value, ok := p[token]
+			if !ok {
+				return nil, fmt.Errorf("path %q not found", formatPointer(path))
+			}
+			removed = value
+			delete(p, token)
+
+			return p, nil
+		case []interface{}:
+			index, err := arrayIndex(token, len(p), false)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", formatPointer(path), err)
+			}
+			removed = p[index]
+
+			return append(p[:index], p[index+1:]...), nil
+		default:
+			return nil, fmt.Errorf("path %q not found", formatPointer(path))
+		}
+	})
+
+	return doc, removed, err
+}
+
+// isProperPrefix returns true if prefix is a proper prefix of path.
+func isProperPrefix(prefix, path []string) bool {
+	if len(prefix) >= len(path) {
+		return false
+	}
+	for i := range prefix {
+		if prefix[i] != path[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Decode decodes a JSON document into the generic document model used by this package.
+// Decode用来将JSON文档解码为通用的文档模型。
+func Decode(data []byte) (interface{}, error) {
+	var doc interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, err
+	}
+
+	return doc, nil
+}
+
+// deepCopy returns a deep copy of a decoded document.
+func deepCopy(doc interface{}) interface{} {
+	switch d := doc.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(d))
+		for k, v := range d {
+			out[k] = deepCopy(v)
+		}
+
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(d))
+		for i, v := range d {
+			out[i] = deepCopy(v)
+		}
+
+		return out
+	default:
+		return d
+	}
+}
@@ -0,0 +1,149 @@
+// Copyright 2020 Lingfei Kong <colin404@foxmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package patch
+
+import (
+	"math/big"
+
+	"github.com/HappyLadySauce/component-base/pkg/json"
+)
+
+// MergePatch applies an RFC 7386 JSON Merge Patch to a decoded document and returns
+// the patched document. Null members of the patch remove the corresponding member
+// of the target. The target may be modified in place.
+// MergePatch用来将JSON Merge Patch应用到文档上。
+func MergePatch(target, patch interface{}) interface{} {
+	p, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+
+	t, ok := target.(map[string]interface{})
+	if !ok {
+		t = make(map[string]interface{}, len(p))
+	}
+
+	for k, v := range p {
+		if v == nil {
+			delete(t, k)
+
+			continue
+		}
+		t[k] = MergePatch(t[k], v)
+	}
+
+	return t
+}
+
+// CreateMergePatch returns the RFC 7386 JSON Merge Patch which turns the decoded
+// document original into modified. Members removed from an object are set to null,
+// and anything which is not an object is replaced as a whole.
+// CreateMergePatch用来生成将original变为modified的JSON Merge Patch。
+func CreateMergePatch(original, modified interface{}) interface{} {
+	o, ok := original.(map[string]interface{})
+	m, ok2 := modified.(map[string]interface{})
+	if !ok || !ok2 {
+		return modified
+	}
+
+	diff := map[string]interface{}{}
+	for k, v := range m {
+		ov, exists := o[k]
+		if !exists {
+			diff[k] = v
+
+			continue
+		}
+		if Equal(ov, v) {
+			continue
+		}
+		if _, isMap := v.(map[string]interface{}); isMap {
+			diff[k] = CreateMergePatch(ov, v)
+		} else {
+			diff[k] = v
+		}
+	}
+	for k := range o {
+		if _, exists := m[k]; !exists {
+			diff[k] = nil
+		}
+	}
+
+	return diff
+}
+
+// CreateMergePatchBytes is CreateMergePatch for encoded JSON documents.
+// CreateMergePatchBytes用来为JSON文档生成JSON Merge Patch。
+func CreateMergePatchBytes(original, modified []byte) ([]byte, error) {
+	o, err := Decode(original)
+	if err != nil {
+		return nil, err
+	}
+	m, err := Decode(modified)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(CreateMergePatch(o, m))
+}
+
+// Equal reports whether two decoded documents are equal. Numbers are compared by
+// value, so 1 and 1.0 are equal.
+// Equal用来判断两个文档是否相等。
+func Equal(a, b interface{}) bool {
+	switch x := a.(type) {
+	case map[string]interface{}:
+		y, ok := b.(map[string]interface{})
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for k, v := range x {
+			w, ok := y[k]
+			if !ok || !Equal(v, w) {
+				return false
+			}
+		}
+
+		return true
+	case []interface{}:
+		y, ok := b.([]interface{})
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for i := range x {
+			if !Equal(x[i], y[i]) {
+				return false
+			}
+		}
+
+		return true
+	case json.Number:
+		y, ok := b.(json.Number)
+		if !ok {
+			return false
+		}
+
+		return numberEqual(x, y)
+	default:
+		return a == b
+	}
+}
+
+func numberEqual(a, b json.Number) bool {
+	if a == b {
+		return true
+	}
+
+	x, ok := new(big.Float).SetString(a.String())
+	if !ok {
+		return false
+	}
+	y, ok := new(big.Float).SetString(b.String())
+	if !ok {
+		return false
+	}
+
+	return x.Cmp(y) == 0
+}
@@ -0,0 +1,119 @@
+// Copyright 2020 Lingfei Kong <colin404@foxmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package patch
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/HappyLadySauce/component-base/pkg/json"
+	metav1 "github.com/HappyLadySauce/component-base/pkg/meta/v1"
+	"github.com/HappyLadySauce/component-base/pkg/validation/field"
+)
+
+// ApplyBytes applies a patch of the given type to a JSON document and returns the
+// patched JSON document.
+// ApplyBytes用来将补丁应用到JSON文档上。
+func ApplyBytes(original []byte, pt PatchType, patch []byte) ([]byte, error) {
+	doc, err := Decode(original)
+	if err != nil {
+		return nil, fmt.Errorf("invalid original document: %w", err)
+	}
+
+	switch pt {
+	case JSONPatchType:
+		p, err := DecodeJSONPatch(patch)
+		if err != nil {
+			return nil, err
+		}
+		if doc, err = p.Apply(doc); err != nil {
+			return nil, err
+		}
+	case MergePatchType:
+		p, err := Decode(patch)
+		if err != nil {
+			return nil, fmt.Errorf("invalid merge patch: %w", err)
+		}
+		doc = MergePatch(doc, p)
+	case ApplyPatchType:
+		return nil, fmt.Errorf("apply patches require a field manager")
+	default:
+		return nil, fmt.Errorf("unsupported patch type %q", pt)
+	}
+
+	return json.Marshal(doc)
+}
+
+// Apply applies a patch of the given type to obj, which must be a non-nil pointer.
+// The patched document is decoded into a fresh value of the same type, so fields
+// that are not serialized (json:"-") are reset. If obj carries ObjectMeta, a patch
+// that changes its read-only fields is rejected and obj is left untouched.
+// Apply用来将补丁应用到对象上，不允许修改ObjectMeta的只读字段。
+func Apply(obj interface{}, pt PatchType, patch []byte) error {
+	v := reflect.ValueOf(obj)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return fmt.Errorf("patch target must be a non-nil pointer, got %T", obj)
+	}
+
+	original, err := json.Marshal(obj)
+	if err != nil {
+		return err
+	}
+
+	patched, err := ApplyBytes(original, pt, patch)
+	if err != nil {
+		return err
+	}
+
+	out := reflect.New(v.Elem().Type())
+	if err := json.Unmarshal(patched, out.Interface()); err != nil {
+		return fmt.Errorf("patched object is invalid: %w", err)
+	}
+
+	if errs := validateReadOnly(out.Interface(), obj); len(errs) > 0 {
+		return errs.ToAggregate()
+	}
+
+	v.Elem().Set(out.Elem())
+
+	return nil
+}
+
+// validateReadOnly rejects changes to the read-only ObjectMeta fields.
+func validateReadOnly(newObj, oldObj interface{}) field.ErrorList {
+	newAccessor, ok := newObj.(metav1.ObjectMetaAccessor)
+	if !ok {
+		return nil
+	}
+	oldAccessor, ok := oldObj.(metav1.ObjectMetaAccessor)
+	if !ok {
+		return nil
+	}
+
+	return metav1.ValidateObjectMetaUpdate(
+		newAccessor.GetObjectMeta(),
+		oldAccessor.GetObjectMeta(),
+		field.NewPath("metadata"),
+	)
+}
+
+// ValidatePatchOptions validates PatchOptions for a patch of the given type.
+// Force and a missing FieldManager are only meaningful for apply patches.
+// ValidatePatchOptions用来校验PatchOptions。
+func ValidatePatchOptions(opts *metav1.PatchOptions, pt PatchType) field.ErrorList {
+	allErrs := opts.Validate()
+	if pt == ApplyPatchType {
+		if opts.FieldManager == "" {
+			allErrs = append(
+				allErrs,
+				field.Required(field.NewPath("fieldManager"), "is required for apply patch"),
+			)
+		}
+	} else if opts.Force {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("force"), "may not be specified for non-apply patch"))
+	}
+
+	return allErrs
+}
@@ -0,0 +1,277 @@
+// Copyright 2020 Lingfei Kong <colin404@foxmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package patch
+
+import (
+	"strings"
+	"testing"
+
+	metav1 "github.com/HappyLadySauce/component-base/pkg/meta/v1"
+)
+
+func TestJSONPatch(t *testing.T) {
+	testCases := []struct {
+		doc      string
+		patch    string
+		expected string
+		err      string
+	}{
+		{
+			doc:      `{"foo":"bar"}`,
+			patch:    `[{"op":"add","path":"/baz","value":"qux"}]`,
+			expected: `{"baz":"qux","foo":"bar"}`,
+		},
+		{
+			doc:      `{"foo":["bar","baz"]}`,
+			patch:    `[{"op":"add","path":"/foo/1","value":"qux"}]`,
+			expected: `{"foo":["bar","qux","baz"]}`,
+		},
+		{
+			doc:      `{"foo":["bar"]}`,
+			patch:    `[{"op":"add","path":"/foo/-","value":["abc","def"]}]`,
+			expected: `{"foo":["bar",["abc","def"]]}`,
+		},
+		{
+			doc:      `{"baz":"qux","foo":"bar"}`,
+			patch:    `[{"op":"remove","path":"/baz"}]`,
+			expected: `{"foo":"bar"}`,
+		},
+		{
+			doc:      `{"foo":["bar","qux","baz"]}`,
+			patch:    `[{"op":"remove","path":"/foo/1"}]`,
+			expected: `{"foo":["bar","baz"]}`,
+		},
+		{
+			doc:      `{"baz":"qux","foo":"bar"}`,
+			patch:    `[{"op":"replace","path":"/baz","value":"boo"}]`,
+			expected: `{"baz":"boo","foo":"bar"}`,
+		},
+		{
+			doc:      `{"foo":{"bar":"baz","waldo":"fred"},"qux":{"corge":"grault"}}`,
+			patch:    `[{"op":"move","from":"/foo/waldo","path":"/qux/thud"}]`,
+			expected: `{"foo":{"bar":"baz"},"qux":{"corge":"grault","thud":"fred"}}`,
+		},
+		{
+			doc:      `{"foo":["all","grass","cows","eat"]}`,
+			patch:    `[{"op":"move","from":"/foo/1","path":"/foo/3"}]`,
+			expected: `{"foo":["all","cows","eat","grass"]}`,
+		},
+		{
+			doc:      `{"foo":{"bar":1}}`,
+			patch:    `[{"op":"copy","from":"/foo","path":"/baz"},{"op":"replace","path":"/baz/bar","value":2}]`,
+			expected: `{"baz":{"bar":2},"foo":{"bar":1}}`,
+		},
+		{
+			doc:      `{"baz":"qux","foo":["a",2,"c"]}`,
+			patch:    `[{"op":"test","path":"/baz","value":"qux"},{"op":"test","path":"/foo/1","value":2.0}]`,
+			expected: `{"baz":"qux","foo":["a",2,"c"]}`,
+		},
+		{
+			doc:      `{"/":1,"~":2}`,
+			patch:    `[{"op":"replace","path":"/~1","value":3},{"op":"remove","path":"/~0"}]`,
+			expected: `{"/":3}`,
+		},
+		{
+			doc:      `{"id":18446744073709551615}`,
+			patch:    `[{"op":"add","path":"/name","value":null}]`,
+			expected: `{"id":18446744073709551615,"name":null}`,
+		},
+		{
+			doc:   `{"baz":"qux"}`,
+			patch: `[{"op":"test","path":"/baz","value":"bar"}]`,
+			err:   "test failed",
+		},
+		{
+			doc:   `{"foo":"bar"}`,
+			patch: `[{"op":"add","path":"/baz/bat","value":"qux"}]`,
+			err:   `path "/baz" not found`,
+		},
+		{
+			doc:   `{"foo":["bar"]}`,
+			patch: `[{"op":"replace","path":"/foo/01","value":"qux"}]`,
+			err:   "invalid array index",
+		},
+		{
+			doc:   `{"foo":"bar"}`,
+			patch: `[{"op":"add","path":"/baz"}]`,
+			err:   "missing value",
+		},
+		{
+			doc:   `{"foo":{"bar":1}}`,
+			patch: `[{"op":"move","from":"/foo","path":"/foo/bar/baz"}]`,
+			err:   "into one of its children",
+		},
+		{
+			doc:   `{"foo":"bar"}`,
+			patch: `[{"op":"frobnicate","path":"/foo"}]`,
+			err:   "unsupported operation",
+		},
+	}
+
+	for i, tc := range testCases {
+		out, err := ApplyBytes([]byte(tc.doc), JSONPatchType, []byte(tc.patch))
+		if tc.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Errorf("%d: expected error containing %q, got %v", i, tc.err, err)
+			}
+
+			continue
+		}
+		if err != nil {
+			t.Errorf("%d: unexpected error: %v", i, err)
+
+			continue
+		}
+		if string(out) != tc.expected {
+			t.Errorf("%d: expected %s, got %s", i, tc.expected, out)
+		}
+	}
+}
+
+func TestMergePatch(t *testing.T) {
+	testCases := []struct {
+		doc      string
+		patch    string
+		expected string
+	}{
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{`{"a":"b"}`, `{"a":null}`, `{}`},
+		{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+		{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`["a","b"]`, `["c","d"]`, `["c","d"]`},
+		{`{"a":"b"}`, `["c"]`, `["c"]`},
+		{`{"e":null}`, `{"a":1}`, `{"a":1,"e":null}`},
+		{`[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+		{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+	}
+
+	for i, tc := range testCases {
+		out, err := ApplyBytes([]byte(tc.doc), MergePatchType, []byte(tc.patch))
+		if err != nil {
+			t.Errorf("%d: unexpected error: %v", i, err)
+
+			continue
+		}
+		if string(out) != tc.expected {
+			t.Errorf("%d: expected %s, got %s", i, tc.expected, out)
+		}
+	}
+}
+
+func TestCreateMergePatch(t *testing.T) {
+	testCases := []struct {
+		original string
+		modified string
+		expected string
+	}{
+		{`{"a":"b"}`, `{"a":"b"}`, `{}`},
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{}`, `{"a":null}`},
+		{`{"a":{"b":"c","d":1}}`, `{"a":{"b":"c","d":2.0,"e":[1]}}`, `{"a":{"d":2.0,"e":[1]}}`},
+		{`{"a":[1,2]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`{"a":"b"}`, `["c"]`, `["c"]`},
+	}
+
+	for i, tc := range testCases {
+		patch, err := CreateMergePatchBytes([]byte(tc.original), []byte(tc.modified))
+		if err != nil {
+			t.Errorf("%d: unexpected error: %v", i, err)
+
+			continue
+		}
+		if string(patch) != tc.expected {
+			t.Errorf("%d: expected %s, got %s", i, tc.expected, patch)
+		}
+
+		out, err := ApplyBytes([]byte(tc.original), MergePatchType, patch)
+		if err != nil {
+			t.Errorf("%d: unexpected error: %v", i, err)
+
+			continue
+		}
+		want, _ := Decode([]byte(tc.modified))
+		got, _ := Decode(out)
+		if !Equal(want, got) {
+			t.Errorf("%d: expected %s, got %s", i, tc.modified, out)
+		}
+	}
+}
+
+type testObject struct {
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Description string            `json:"description,omitempty"`
+	Tags        map[string]string `json:"tags,omitempty"`
+}
+
+func TestApply(t *testing.T) {
+	newObject := func() *testObject {
+		return &testObject{
+			ObjectMeta:  metav1.ObjectMeta{ID: 1, InstanceID: "secret-xyz", Name: "foo"},
+			Description: "old",
+			Tags:        map[string]string{"a": "1", "b": "2"},
+		}
+	}
+
+	obj := newObject()
+	if err := Apply(obj, MergePatchType, []byte(`{"description":"new","tags":{"a":null}}`)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if obj.Description != "new" || len(obj.Tags) != 1 || obj.Tags["b"] != "2" {
+		t.Errorf("unexpected result: %#v", obj)
+	}
+
+	obj = newObject()
+	if err := Apply(obj, JSONPatchType, []byte(`[{"op":"replace","path":"/metadata/name","value":"bar"}]`)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if obj.Name != "bar" || obj.ID != 1 {
+		t.Errorf("unexpected result: %#v", obj)
+	}
+
+	readOnly := []struct {
+		pt    PatchType
+		patch string
+		field string
+	}{
+		{JSONPatchType, `[{"op":"replace","path":"/metadata/id","value":2}]`, "metadata.id"},
+		{JSONPatchType, `[{"op":"remove","path":"/metadata/instanceID"}]`, "metadata.instanceID"},
+		{MergePatchType, `{"metadata":{"createdAt":"2020-01-01T00:00:00Z"}}`, "metadata.createdAt"},
+	}
+	for _, tc := range readOnly {
+		obj = newObject()
+		err := Apply(obj, tc.pt, []byte(tc.patch))
+		if err == nil || !strings.Contains(err.Error(), tc.field) {
+			t.Errorf("expected error for %s, got %v", tc.field, err)
+		}
+		if obj.Description != "old" || obj.InstanceID != "secret-xyz" {
+			t.Errorf("object was modified by a rejected patch: %#v", obj)
+		}
+	}
+}
+
+func TestPatchTypeForContentType(t *testing.T) {
+	testCases := []struct {
+		contentType string
+		expected    PatchType
+		valid       bool
+	}{
+		{"application/json-patch+json", JSONPatchType, true},
+		{"application/merge-patch+json; charset=utf-8", MergePatchType, true},
+		{"application/json", "", false},
+		{"", "", false},
+	}
+
+	for _, tc := range testCases {
+		pt, err := PatchTypeForContentType(tc.contentType)
+		if (err == nil) != tc.valid || pt != tc.expected {
+			t.Errorf("%q: expected %q (valid=%v), got %q, %v", tc.contentType, tc.expected, tc.valid, pt, err)
+		}
+	}
+}
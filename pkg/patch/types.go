@@ -0,0 +1,50 @@
+// Copyright 2020 Lingfei Kong <colin404@foxmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package patch
+
+import (
+	"fmt"
+	"mime"
+)
+
+// PatchType identifies the format of a patch document. Its value is the media type
+// a client sends in the Content-Type header.
+// PatchType是用来表示补丁格式的类型。
+type PatchType string
+
+// These are the supported patch types.
+const (
+	// JSONPatchType is an RFC 6902 JSON Patch.
+	JSONPatchType PatchType = "application/json-patch+json"
+	// MergePatchType is an RFC 7386 JSON Merge Patch.
+	MergePatchType PatchType = "application/merge-patch+json"
+	// ApplyPatchType is a partial configuration in YAML or JSON which is merged by
+	// server-side apply, see package component-base/pkg/fieldmanager.
+	ApplyPatchType PatchType = "application/apply-patch+yaml"
+)
+
+// SupportedPatchTypes returns the patch types which can be selected by Content-Type.
+// SupportedPatchTypes返回支持的补丁类型。
+func SupportedPatchTypes() []string {
+	return []string{string(JSONPatchType), string(MergePatchType), string(ApplyPatchType)}
+}
+
+// PatchTypeForContentType returns the PatchType selected by a Content-Type header.
+// Media type parameters such as charset are ignored.
+// PatchTypeForContentType根据Content-Type返回补丁类型。
+func PatchTypeForContentType(contentType string) (PatchType, error) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return "", fmt.Errorf("invalid Content-Type %q: %w", contentType, err)
+	}
+
+	for _, t := range SupportedPatchTypes() {
+		if mediaType == t {
+			return PatchType(t), nil
+		}
+	}
+
+	return "", fmt.Errorf("unsupported patch type %q, supported types: %v", mediaType, SupportedPatchTypes())
+}
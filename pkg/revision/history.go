@@ -0,0 +1,128 @@
+// Copyright 2020 Lingfei Kong <colin404@foxmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package revision
+
+import (
+	"fmt"
+	"reflect"
+
+	"gorm.io/gorm"
+
+	"github.com/HappyLadySauce/component-base/pkg/json"
+	metav1 "github.com/HappyLadySauce/component-base/pkg/meta/v1"
+)
+
+// List returns the revisions of obj, oldest first.
+// List用来查询对象的所有修订版本。
+func List(db *gorm.DB, obj metav1.Object) ([]Revision, error) {
+	resource, err := resourceOf(db, obj)
+	if err != nil {
+		return nil, err
+	}
+
+	var list []Revision
+	err = db.Session(&gorm.Session{NewDB: true}).
+		Where("resource = ? AND objectID = ?", resource, obj.GetID()).
+		Order("revision").
+		Find(&list).Error
+
+	return list, err
+}
+
+// Get returns the given revision of obj, or gorm.ErrRecordNotFound.
+// Get用来查询对象的指定修订版本。
+func Get(db *gorm.DB, obj metav1.Object, revision int64) (*Revision, error) {
+	resource, err := resourceOf(db, obj)
+	if err != nil {
+		return nil, err
+	}
+
+	rev := &Revision{}
+	err = db.Session(&gorm.Session{NewDB: true}).
+		Where("resource = ? AND objectID = ? AND revision = ?", resource, obj.GetID(), revision).
+		First(rev).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return rev, nil
+}
+
+// Rollback saves obj with the content of the given revision and records the
+// write as a new revision. The identity of obj and its fields which are not
+// serialized (json:"-") are kept. On success obj holds the saved object.
+// Rollback用来将对象回滚到指定的修订版本。
+func Rollback(db *gorm.DB, obj metav1.Object, revision int64) error {
+	rev, err := Get(db, obj, revision)
+	if err != nil {
+		return err
+	}
+
+	restored, err := restore(obj, rev.Snapshot)
+	if err != nil {
+		return err
+	}
+	if err := db.Set(operationKey, OperationRollback).Save(restored).Error; err != nil {
+		return err
+	}
+	reflect.ValueOf(obj).Elem().Set(reflect.ValueOf(restored).Elem())
+
+	return nil
+}
+
+// resourceOf returns the table of obj.
+func resourceOf(db *gorm.DB, obj metav1.Object) (string, error) {
+	if db.Statement.Table != "" {
+		return db.Statement.Table, nil
+	}
+
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(obj); err != nil {
+		return "", err
+	}
+
+	return stmt.Schema.Table, nil
+}
+
+// restore decodes snapshot into a new object of the type of current, keeping the
+// identity and the unserialized fields of current.
+func restore(current metav1.Object, snapshot string) (metav1.Object, error) {
+	v := reflect.ValueOf(current)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("rollback target must be a non-nil pointer to a struct, got %T", current)
+	}
+
+	out := reflect.New(v.Elem().Type())
+	if err := json.Unmarshal([]byte(snapshot), out.Interface()); err != nil {
+		return nil, fmt.Errorf("invalid snapshot: %w", err)
+	}
+	copyHidden(out.Elem(), v.Elem())
+
+	restored, _ := out.Interface().(metav1.Object)
+	restored.SetID(current.GetID())
+	restored.SetInstanceID(current.GetInstanceID())
+	restored.SetCreatedAt(current.GetCreatedAt())
+
+	return restored, nil
+}
+
+// copyHidden copies the fields of src which are not serialized (json:"-") to dst,
+// looking into embedded structs such as ObjectMeta.
+func copyHidden(dst, src reflect.Value) {
+	t := src.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !dst.Field(i).CanSet() {
+			continue
+		}
+
+		switch {
+		case f.Tag.Get("json") == "-":
+			dst.Field(i).Set(src.Field(i))
+		case f.Anonymous && f.Type.Kind() == reflect.Struct:
+			copyHidden(dst.Field(i), src.Field(i))
+		}
+	}
+}
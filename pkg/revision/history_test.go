@@ -0,0 +1,77 @@
+// Copyright 2020 Lingfei Kong <colin404@foxmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package revision
+
+import (
+	"errors"
+	"testing"
+
+	"gorm.io/gorm"
+
+	metav1 "github.com/HappyLadySauce/component-base/pkg/meta/v1"
+)
+
+func TestGet(t *testing.T) {
+	db := newTestDB(t)
+
+	secret := &testSecret{ObjectMeta: metav1.ObjectMeta{InstanceID: "secret-1", Name: "foo"}, Description: "v1"}
+	if err := db.Create(secret).Error; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	rev, err := Get(db, secret, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rev.Revision != 1 || rev.Operation != OperationCreate {
+		t.Errorf("unexpected revision %+v", rev)
+	}
+
+	if _, err := Get(db, secret, 2); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("expected gorm.ErrRecordNotFound, got %v", err)
+	}
+}
+
+func TestRollback(t *testing.T) {
+	db := newTestDB(t)
+
+	secret := &testSecret{
+		ObjectMeta:  metav1.ObjectMeta{InstanceID: "secret-1", Name: "foo"},
+		Description: "v1",
+		SecretKey:   "key",
+	}
+	if err := db.Create(secret).Error; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	secret.Name = "bar"
+	secret.Description = "v2"
+	if err := db.Save(secret).Error; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := Rollback(db, secret, 1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if secret.Name != "foo" || secret.Description != "v1" || secret.SecretKey != "key" {
+		t.Errorf("unexpected rolled back object %+v", secret)
+	}
+
+	stored := &testSecret{}
+	if err := db.First(stored, secret.ID).Error; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stored.Name != "foo" || stored.Description != "v1" || stored.SecretKey != "key" {
+		t.Errorf("unexpected stored object %+v", stored)
+	}
+
+	list := listRevisions(t, db, secret)
+	if len(list) != 3 || list[2].Revision != 3 || list[2].Operation != OperationRollback {
+		t.Errorf("expected the rollback to be recorded as revision 3, got %+v", list)
+	}
+
+	if err := Rollback(db, secret, 4); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("expected gorm.ErrRecordNotFound, got %v", err)
+	}
+}
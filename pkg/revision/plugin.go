@@ -0,0 +1,157 @@
+// Copyright 2020 Lingfei Kong <colin404@foxmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package revision
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"gorm.io/gorm"
+
+	"github.com/HappyLadySauce/component-base/pkg/json"
+	metav1 "github.com/HappyLadySauce/component-base/pkg/meta/v1"
+)
+
+// operationKey is the gorm setting overriding the operation of a write.
+const operationKey = "revision:operation"
+
+// ActorFunc returns the actor of the writes made with the given context.
+type ActorFunc func(ctx context.Context) string
+
+// Plugin is a gorm plugin recording a Revision for every write of an object
+// carrying ObjectMeta. Writes of objects without an ID, such as batch updates
+// of a model, are not recorded.
+// Plugin是用来在每次写对象时记录修订版本的gorm插件。
+type Plugin struct {
+	actor ActorFunc
+}
+
+var _ gorm.Plugin = &Plugin{}
+
+// New creates a Plugin which takes the actor from the context set by WithActor.
+// New用来创建Plugin。
+func New() *Plugin {
+	return NewWithActorFunc(func(ctx context.Context) string {
+		actor, _ := ActorFrom(ctx)
+
+		return actor
+	})
+}
+
+// NewWithActorFunc creates a Plugin which takes the actor from the context with actor.
+// NewWithActorFunc用来创建使用指定方式获取操作者的Plugin。
+func NewWithActorFunc(actor ActorFunc) *Plugin {
+	return &Plugin{actor: actor}
+}
+
+// Name implements the gorm.Plugin interface.
+func (p *Plugin) Name() string {
+	return "revision"
+}
+
+// Initialize implements the gorm.Plugin interface. The callbacks run after the
+// write, inside its transaction.
+func (p *Plugin) Initialize(db *gorm.DB) error {
+	err := db.Callback().Create().
+		After("gorm:create").
+		Before("gorm:commit_or_rollback_transaction").
+		Register("revision:create", p.record(OperationCreate))
+	if err != nil {
+		return err
+	}
+
+	return db.Callback().Update().
+		After("gorm:update").
+		Before("gorm:commit_or_rollback_transaction").
+		Register("revision:update", p.record(OperationUpdate))
+}
+
+// record returns the callback writing the revisions of the objects of a statement.
+func (p *Plugin) record(op Operation) func(db *gorm.DB) {
+	return func(db *gorm.DB) {
+		if db.Error != nil || db.RowsAffected == 0 || db.Statement.Schema == nil {
+			return
+		}
+		if value, ok := db.Get(operationKey); ok {
+			op, _ = value.(Operation)
+		}
+
+		for _, obj := range objects(db.Statement.ReflectValue) {
+			if err := p.write(db, op, obj); err != nil {
+				_ = db.AddError(fmt.Errorf("failed to record revision: %w", err))
+
+				return
+			}
+		}
+	}
+}
+
+// write records the revision of obj. The object is read back so the snapshot
+// holds the persisted values, including those not set by a partial update.
+func (p *Plugin) write(db *gorm.DB, op Operation, obj metav1.Object) error {
+	tx := db.Session(&gorm.Session{NewDB: true})
+	resource := db.Statement.Table
+
+	fresh := reflect.New(db.Statement.Schema.ModelType).Interface()
+	if err := tx.Table(resource).First(fresh, obj.GetID()).Error; err != nil {
+		return err
+	}
+	snapshot, err := json.Marshal(fresh)
+	if err != nil {
+		return err
+	}
+
+	var prevs []Revision
+	err = tx.Where("resource = ? AND objectID = ?", resource, obj.GetID()).
+		Order("revision desc").
+		Limit(1).
+		Find(&prevs).Error
+	if err != nil {
+		return err
+	}
+	var prev *Revision
+	if len(prevs) > 0 {
+		prev = &prevs[0]
+	}
+
+	rev, err := newRevision(prev, resource, obj, snapshot, op, p.actor(db.Statement.Context))
+	if err != nil {
+		return err
+	}
+
+	return tx.Create(rev).Error
+}
+
+// objects returns the objects carrying ObjectMeta with an ID held by value,
+// which is either a struct or a slice of structs.
+func objects(value reflect.Value) []metav1.Object {
+	var list []metav1.Object
+	add := func(v reflect.Value) {
+		for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+			if v.IsNil() {
+				return
+			}
+			v = v.Elem()
+		}
+		if !v.CanAddr() {
+			return
+		}
+		if obj, ok := v.Addr().Interface().(metav1.Object); ok && obj.GetID() != 0 {
+			list = append(list, obj)
+		}
+	}
+
+	switch value.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			add(value.Index(i))
+		}
+	default:
+		add(value)
+	}
+
+	return list
+}
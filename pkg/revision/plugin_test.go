@@ -0,0 +1,136 @@
+// Copyright 2020 Lingfei Kong <colin404@foxmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package revision
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	metav1 "github.com/HappyLadySauce/component-base/pkg/meta/v1"
+)
+
+func newTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{
+		Logger: logger.Discard,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// A single connection keeps the in-memory database alive for the whole test.
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	if err := db.AutoMigrate(&testSecret{}, &Revision{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := db.Use(New()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return db
+}
+
+func listRevisions(t *testing.T, db *gorm.DB, obj metav1.Object) []Revision {
+	t.Helper()
+
+	list, err := List(db, obj)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return list
+}
+
+func TestPluginRecordsWrites(t *testing.T) {
+	db := newTestDB(t).WithContext(WithActor(context.Background(), "colin"))
+
+	secret := &testSecret{ObjectMeta: metav1.ObjectMeta{InstanceID: "secret-1", Name: "foo"}, Description: "v1"}
+	if err := db.Create(secret).Error; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	secret.Description = "v2"
+	if err := db.Save(secret).Error; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	list := listRevisions(t, db, secret)
+	if len(list) != 2 {
+		t.Fatalf("expected 2 revisions, got %d", len(list))
+	}
+	for i, op := range []Operation{OperationCreate, OperationUpdate} {
+		rev := list[i]
+		if rev.Revision != int64(i+1) || rev.Operation != op || rev.Actor != "colin" {
+			t.Errorf("unexpected revision %d: %+v", i+1, rev)
+		}
+		if rev.Resource != "test_secrets" || rev.ObjectID != secret.ID || rev.InstanceID != "secret-1" {
+			t.Errorf("unexpected object of revision %d: %+v", i+1, rev)
+		}
+	}
+	if !strings.Contains(list[1].Diff, `"description":"v2"`) {
+		t.Errorf("unexpected diff %s", list[1].Diff)
+	}
+}
+
+func TestPluginSkipsFailedWrites(t *testing.T) {
+	db := newTestDB(t)
+
+	secret := &testSecret{ObjectMeta: metav1.ObjectMeta{InstanceID: "secret-1", Name: "foo"}}
+	if err := db.Create(secret).Error; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The instanceID is unique, so the create fails.
+	dup := &testSecret{ObjectMeta: metav1.ObjectMeta{InstanceID: "secret-1", Name: "bar"}}
+	if err := db.Create(dup).Error; err == nil {
+		t.Fatalf("expected an error for a duplicated instanceID")
+	}
+
+	// The revision is written in the transaction of the update, so it is rolled back with it.
+	abort := errors.New("abort")
+	err := db.Transaction(func(tx *gorm.DB) error {
+		secret.Description = "v2"
+		if err := tx.Save(secret).Error; err != nil {
+			return err
+		}
+
+		return abort
+	})
+	if !errors.Is(err, abort) {
+		t.Fatalf("expected the transaction to be aborted, got %v", err)
+	}
+
+	var count int64
+	if err := db.Model(&Revision{}).Count(&count).Error; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("expected only the revision of the first create, got %d", count)
+	}
+}
+
+func TestRevisionUniqueIndex(t *testing.T) {
+	db := newTestDB(t)
+
+	rev := &Revision{Resource: "test_secrets", ObjectID: 1, Revision: 1, Operation: OperationCreate}
+	if err := db.Create(rev).Error; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	dup := &Revision{Resource: "test_secrets", ObjectID: 1, Revision: 1, Operation: OperationUpdate}
+	if err := db.Create(dup).Error; err == nil {
+		t.Errorf("expected an error for a duplicated revision")
+	}
+}
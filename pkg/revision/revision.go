@@ -0,0 +1,131 @@
+// Copyright 2020 Lingfei Kong <colin404@foxmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// Package revision keeps the history of the objects persisted with gorm. Once
+// the Plugin is registered, every create and update of an object carrying
+// ObjectMeta writes a Revision row in the same transaction as the write and its
+// BeforeCreate/BeforeUpdate hooks, so the history can not diverge from the data.
+//
+// The revision table must be migrated by the caller:
+//
+//	db.AutoMigrate(&revision.Revision{})
+//	db.Use(revision.New())
+package revision
+
+import (
+	"context"
+	"time"
+
+	metav1 "github.com/HappyLadySauce/component-base/pkg/meta/v1"
+	"github.com/HappyLadySauce/component-base/pkg/patch"
+)
+
+// Operation is the type of write which lead to a Revision being created.
+// Operation是用来表示产生修订记录的写操作类型。
+type Operation string
+
+// These are the valid revision operations.
+const (
+	// OperationCreate means the object was created.
+	OperationCreate Operation = "Create"
+	// OperationUpdate means the object was updated or patched.
+	OperationUpdate Operation = "Update"
+	// OperationRollback means the object was rolled back to a previous revision.
+	OperationRollback Operation = "Rollback"
+)
+
+// Revision is a snapshot of an object taken after a write.
+// Revision是对象在一次写操作之后的快照。
+type Revision struct {
+	// ID is the unique id of the revision row.
+	ID uint64 `json:"id,omitempty" gorm:"primary_key;AUTO_INCREMENT;column:id"`
+
+	// Resource is the table of the object.
+	Resource string `json:"resource" gorm:"column:resource;type:varchar(64);not null;uniqueIndex:idx_revision_object"`
+
+	// ObjectID is the ID of the object.
+	ObjectID uint64 `json:"objectID" gorm:"column:objectID;not null;uniqueIndex:idx_revision_object"`
+
+	// InstanceID is the InstanceID of the object.
+	InstanceID string `json:"instanceID,omitempty" gorm:"column:instanceID;type:varchar(32)"`
+
+	// Revision is the sequence number of the revision, starting at 1 for each object.
+	// It is unique for the object, so concurrent writes can not record the same revision.
+	Revision int64 `json:"revision" gorm:"column:revision;not null;uniqueIndex:idx_revision_object"`
+
+	// Operation is the write which created the revision.
+	Operation Operation `json:"operation" gorm:"column:operation;type:varchar(16);not null"`
+
+	// Actor is the user who made the write, taken from the request context.
+	Actor string `json:"actor,omitempty" gorm:"column:actor;type:varchar(64)"`
+
+	// Snapshot is the JSON encoding of the object as persisted by the write.
+	Snapshot string `json:"snapshot" gorm:"column:snapshot;type:text"`
+
+	// Diff is the JSON Merge Patch (RFC 7386) from the previous revision to this
+	// one. It is empty for the first revision of an object.
+	Diff string `json:"diff,omitempty" gorm:"column:diff;type:text"`
+
+	// CreatedAt is the time of the write.
+	CreatedAt time.Time `json:"createdAt,omitempty" gorm:"column:createdAt"`
+}
+
+// TableName maps to mysql table name.
+func (r *Revision) TableName() string {
+	return "revision"
+}
+
+// actorKey is the context key of the actor.
+type actorKey struct{}
+
+// WithActor returns a copy of ctx carrying actor, which is recorded in the
+// revisions written with a gorm session using that context.
+// WithActor用来在上下文中保存操作者。
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFrom returns the actor carried by ctx.
+// ActorFrom用来从上下文中获取操作者。
+func ActorFrom(ctx context.Context) (string, bool) {
+	if ctx == nil {
+		return "", false
+	}
+	actor, ok := ctx.Value(actorKey{}).(string)
+
+	return actor, ok
+}
+
+// newRevision builds the revision which follows prev, which is nil for the first
+// revision of obj, from the snapshot of obj.
+func newRevision(
+	prev *Revision,
+	resource string,
+	obj metav1.Object,
+	snapshot []byte,
+	op Operation,
+	actor string,
+) (*Revision, error) {
+	rev := &Revision{
+		Resource:   resource,
+		ObjectID:   obj.GetID(),
+		InstanceID: obj.GetInstanceID(),
+		Revision:   1,
+		Operation:  op,
+		Actor:      actor,
+		Snapshot:   string(snapshot),
+	}
+	if prev == nil {
+		return rev, nil
+	}
+
+	diff, err := patch.CreateMergePatchBytes([]byte(prev.Snapshot), snapshot)
+	if err != nil {
+		return nil, err
+	}
+	rev.Revision = prev.Revision + 1
+	rev.Diff = string(diff)
+
+	return rev, nil
+}
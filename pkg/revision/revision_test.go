@@ -0,0 +1,88 @@
+// Copyright 2020 Lingfei Kong <colin404@foxmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package revision
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/HappyLadySauce/component-base/pkg/json"
+	metav1 "github.com/HappyLadySauce/component-base/pkg/meta/v1"
+)
+
+type testSecret struct {
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Description string `json:"description,omitempty"`
+	SecretKey   string `json:"-"`
+}
+
+func TestActor(t *testing.T) {
+	if _, ok := ActorFrom(context.Background()); ok {
+		t.Errorf("expected no actor")
+	}
+
+	actor, ok := ActorFrom(WithActor(context.Background(), "colin"))
+	if !ok || actor != "colin" {
+		t.Errorf("expected actor colin, got %q", actor)
+	}
+}
+
+func TestNewRevision(t *testing.T) {
+	obj := &testSecret{ObjectMeta: metav1.ObjectMeta{ID: 1, InstanceID: "secret-1"}, Description: "v1"}
+	v1, _ := json.Marshal(obj)
+	first, err := newRevision(nil, "secret", obj, v1, OperationCreate, "colin")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := &Revision{
+		Resource:   "secret",
+		ObjectID:   1,
+		InstanceID: "secret-1",
+		Revision:   1,
+		Operation:  OperationCreate,
+		Actor:      "colin",
+		Snapshot:   string(v1),
+	}
+	if !reflect.DeepEqual(first, expected) {
+		t.Errorf("expected %+v, got %+v", expected, first)
+	}
+
+	obj.Description = ""
+	v2, _ := json.Marshal(obj)
+	second, err := newRevision(first, "secret", obj, v2, OperationUpdate, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if second.Revision != 2 || second.Diff != `{"description":null}` || second.Snapshot != string(v2) {
+		t.Errorf("unexpected revision %+v", second)
+	}
+}
+
+func TestRestore(t *testing.T) {
+	current := &testSecret{
+		ObjectMeta:  metav1.ObjectMeta{ID: 1, InstanceID: "secret-1", Name: "foo", ExtendShadow: "{}"},
+		Description: "v2",
+		SecretKey:   "key",
+	}
+	restored, err := restore(current, `{"metadata":{"id":2,"name":"bar"},"description":"v1"}`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := &testSecret{
+		ObjectMeta:  metav1.ObjectMeta{ID: 1, InstanceID: "secret-1", Name: "bar", ExtendShadow: "{}"},
+		Description: "v1",
+		SecretKey:   "key",
+	}
+	if !reflect.DeepEqual(restored, expected) {
+		t.Errorf("expected %+v, got %+v", expected, restored)
+	}
+
+	if _, err := restore(current, `{"description":1}`); err == nil {
+		t.Errorf("expected an error for an invalid snapshot")
+	}
+}
@@ -0,0 +1,295 @@
+// Copyright 2020 Lingfei Kong <colin404@foxmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package runtime
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/ghodss/yaml"
+
+	"github.com/HappyLadySauce/component-base/pkg/json"
+	"github.com/HappyLadySauce/component-base/pkg/scheme"
+)
+
+// CodecFactory creates the codecs of the objects registered in a scheme.
+// CodecFactory是用来创建Scheme中对象编解码器的工厂。
+type CodecFactory struct {
+	scheme  *Scheme
+	accepts []SerializerInfo
+}
+
+var _ NegotiatedSerializer = CodecFactory{}
+
+// NewCodecFactory creates a CodecFactory for the objects registered in s. It
+// supports JSON, YAML and CBOR.
+// NewCodecFactory用来创建CodecFactory，支持JSON、YAML和CBOR。
+func NewCodecFactory(s *Scheme) CodecFactory {
+	return CodecFactory{
+		scheme: s,
+		accepts: []SerializerInfo{
+			{
+				MediaType:        ContentTypeJSON,
+				EncodesAsText:    true,
+				Serializer:       NewJSONSerializer(false),
+				PrettySerializer: NewJSONSerializer(true),
+				StreamSerializer: &StreamSerializerInfo{
+					EncodesAsText: true,
+					Serializer:    NewJSONSerializer(false),
+					Framer:        NewlineFramer,
+				},
+			},
+			{
+				MediaType:     ContentTypeYAML,
+				EncodesAsText: true,
+				Serializer:    NewYAMLSerializer(),
+				StreamSerializer: &StreamSerializerInfo{
+					EncodesAsText: true,
+					Serializer:    NewYAMLSerializer(),
+					Framer:        YAMLFramer,
+				},
+			},
+			{
+				MediaType:  ContentTypeCBOR,
+				Serializer: NewCBORSerializer(),
+				StreamSerializer: &StreamSerializerInfo{
+					Serializer: NewCBORSerializer(),
+					Framer:     LengthDelimitedFramer,
+				},
+			},
+		},
+	}
+}
+
+// SupportedMediaTypes implements the NegotiatedSerializer interface.
+func (f CodecFactory) SupportedMediaTypes() []SerializerInfo {
+	return f.accepts
+}
+
+// UniversalDeserializer returns a codec decoding JSON or YAML into objects of the
+// kind they declare, without conversion.
+// UniversalDeserializer用来返回按照声明的类型解码JSON或YAML的解码器。
+func (f CodecFactory) UniversalDeserializer() *Codec {
+	return f.CodecForVersions(nil, scheme.GroupVersion{}, scheme.GroupVersion{})
+}
+
+// UniversalDecoder returns a codec decoding JSON or YAML into objects of the kind
+// they declare, and converting them to version. Objects are not converted when
+// version is empty.
+// UniversalDecoder用来返回解码JSON或YAML并转换为指定版本的解码器。
+func (f CodecFactory) UniversalDecoder(version scheme.GroupVersion) *Codec {
+	return f.CodecForVersions(nil, scheme.GroupVersion{}, version)
+}
+
+// StrictUniversalDeserializer returns a codec decoding JSON or YAML into objects
+// of the kind they declare, without conversion, and reporting the unknown fields,
+// duplicate keys and case-mismatched keys of the data as NewStrictJSONSerializer does.
+// StrictUniversalDeserializer用来返回严格解码JSON或YAML的解码器。
+func (f CodecFactory) StrictUniversalDeserializer(mode json.StrictMode, warn WarningHandler) *Codec {
+	codec := f.UniversalDeserializer()
+	codec.jsonDecoder = NewStrictJSONSerializer(mode, warn)
+
+	return codec
+}
+
+// EncoderForVersion implements the NegotiatedSerializer interface. The returned
+// encoder converts objects to version and sets their kind before encoding them with enc.
+// EncoderForVersion用来返回将对象转换为指定版本后再编码的编码器。
+func (f CodecFactory) EncoderForVersion(enc Encoder, version scheme.GroupVersion) Encoder {
+	return f.CodecForVersions(enc, version, scheme.GroupVersion{})
+}
+
+// DecoderToVersion implements the NegotiatedSerializer interface. The returned
+// decoder decodes objects with dec into the kind they declare, and converts them
+// to version.
+// DecoderToVersion用来返回解码后将对象转换为指定版本的解码器。
+func (f CodecFactory) DecoderToVersion(dec Decoder, version scheme.GroupVersion) Decoder {
+	codec := f.CodecForVersions(nil, scheme.GroupVersion{}, version)
+	codec.decoder = dec
+
+	return codec
+}
+
+// CodecForVersions returns a codec encoding objects with enc, JSON when enc is
+// nil, in encodeVersion, and decoding them in decodeVersion. An empty version
+// disables the conversion.
+// CodecForVersions用来返回在指定版本之间编解码的编解码器。
+func (f CodecFactory) CodecForVersions(enc Encoder, encodeVersion, decodeVersion scheme.GroupVersion) *Codec {
+	if enc == nil {
+		enc = NewJSONSerializer(false)
+	}
+
+	return &Codec{
+		scheme:        f.scheme,
+		encoder:       enc,
+		jsonDecoder:   NewJSONSerializer(false),
+		encodeVersion: encodeVersion,
+		decodeVersion: decodeVersion,
+	}
+}
+
+// Codec encodes and decodes the objects of a scheme, converting them between
+// versions. Unless it is given a decoder, it decodes JSON as well as YAML. The
+// apiVersion and kind found in the data pick the type to decode into.
+// Codec是用来编解码Scheme中对象的编解码器。
+type Codec struct {
+	scheme  *Scheme
+	encoder Encoder
+	decoder Decoder
+	// jsonDecoder decodes the data, converted to JSON, when there is no decoder.
+	jsonDecoder   Decoder
+	encodeVersion scheme.GroupVersion
+	decodeVersion scheme.GroupVersion
+}
+
+var (
+	_ Encoder       = &Codec{}
+	_ Decoder       = &Codec{}
+	_ ObjectDecoder = &Codec{}
+)
+
+// Encode implements the Encoder interface. Registered objects are converted to
+// the encode version of the codec and their kind is set. The encoded object is
+// not modified.
+func (c *Codec) Encode(v interface{}) ([]byte, error) {
+	obj, ok := v.(Object)
+	if !ok {
+		return c.encoder.Encode(v)
+	}
+
+	var err error
+	if c.encodeVersion.Empty() {
+		obj = obj.DeepCopyObject()
+		err = c.scheme.SetGroupVersionKind(obj)
+	} else {
+		obj, err = c.scheme.ConvertToVersion(obj, c.encodeVersion)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return c.encoder.Encode(obj)
+}
+
+// Decode implements the Decoder interface. When v is an Object, the decoded
+// object is converted into it; other values are decoded as is.
+func (c *Codec) Decode(data []byte, v interface{}) error {
+	into, ok := v.(Object)
+	if !ok {
+		dec, data, err := c.decoderFor(data)
+		if err != nil {
+			return err
+		}
+
+		return dec.Decode(data, v)
+	}
+
+	obj, _, err := c.DecodeObject(data)
+	if err != nil {
+		return err
+	}
+
+	return c.scheme.Convert(obj, into)
+}
+
+// DecodeObject decodes data into a new object of the kind declared by its
+// apiVersion and kind fields. The object is defaulted and converted to the decode
+// version of the codec. The kind found in data is returned along with the object.
+// DecodeObject用来按照数据中声明的类型解码对象。
+func (c *Codec) DecodeObject(data []byte) (Object, *scheme.GroupVersionKind, error) {
+	dec, data, err := c.decoderFor(data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	gvk, err := sniffGroupVersionKind(dec, data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	obj, err := c.scheme.DecodeToVersion(dec, data, gvk, c.decodeVersion)
+	if err != nil {
+		return nil, &gvk, err
+	}
+
+	return obj, &gvk, nil
+}
+
+// decoderFor returns the decoder of data, and data in the format of the decoder.
+func (c *Codec) decoderFor(data []byte) (Decoder, []byte, error) {
+	if c.decoder != nil {
+		return c.decoder, data, nil
+	}
+
+	js, err := ToJSON(data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return c.jsonDecoder, js, nil
+}
+
+// ToJSON converts YAML data to JSON. JSON data is returned unchanged.
+// ToJSON用来将YAML数据转换为JSON。
+func ToJSON(data []byte) ([]byte, error) {
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		return data, nil
+	}
+
+	return yaml.YAMLToJSON(data)
+}
+
+// SniffGroupVersionKind returns the kind declared by the apiVersion and kind
+// fields of the JSON data.
+// SniffGroupVersionKind用来获取JSON数据中声明的类型。
+func SniffGroupVersionKind(data []byte) (scheme.GroupVersionKind, error) {
+	return sniffGroupVersionKind(NewJSONSerializer(false), data)
+}
+
+// sniffGroupVersionKind returns the kind declared by the apiVersion and kind
+// fields of data, decoded with dec.
+func sniffGroupVersionKind(dec Decoder, data []byte) (scheme.GroupVersionKind, error) {
+	typeMeta := struct {
+		APIVersion string `json:"apiVersion,omitempty"`
+		Kind       string `json:"kind,omitempty"`
+	}{}
+	// The other fields of data are not issues of the type information.
+	if d, ok := dec.(lenientDecoder); ok {
+		dec = d.lenient()
+	}
+	if err := dec.Decode(data, &typeMeta); err != nil {
+		return scheme.GroupVersionKind{}, fmt.Errorf("couldn't get version/kind: %w", err)
+	}
+
+	gv, err := scheme.ParseGroupVersion(typeMeta.APIVersion)
+	if err != nil {
+		return scheme.GroupVersionKind{}, err
+	}
+	gvk := gv.WithKind(typeMeta.Kind)
+	if len(gvk.Kind) == 0 || len(gvk.Version) == 0 {
+		return gvk, &missingKindErr{data: string(data)}
+	}
+
+	return gvk, nil
+}
+
+// missingKindErr is returned when the serialized data does not declare its kind.
+type missingKindErr struct {
+	data string
+}
+
+func (e *missingKindErr) Error() string {
+	return fmt.Sprintf("Object 'apiVersion' or 'Kind' is missing in '%s'", e.data)
+}
+
+// IsMissingKind returns true if the error indicates that the serialized data
+// does not declare its apiVersion or kind.
+// IsMissingKind用来判断错误是否为缺少apiVersion或Kind。
+func IsMissingKind(err error) bool {
+	var e *missingKindErr
+
+	return errors.As(err, &e)
+}
@@ -0,0 +1,129 @@
+// Copyright 2020 Lingfei Kong <colin404@foxmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package runtime
+
+import (
+	"testing"
+
+	"github.com/HappyLadySauce/component-base/pkg/json"
+	"github.com/HappyLadySauce/component-base/pkg/scheme"
+	"github.com/HappyLadySauce/component-base/pkg/validation/field"
+)
+
+func TestCodecDecodeObject(t *testing.T) {
+	codecs := NewCodecFactory(newConversionScheme(t))
+
+	testCases := []struct {
+		name string
+		data string
+	}{
+		{"json", `{"apiVersion":"iam.example.com/v2","kind":"Secret","name":"foo"}`},
+		{"yaml", "apiVersion: iam.example.com/v2\nkind: Secret\nname: foo\n"},
+	}
+	for _, tc := range testCases {
+		obj, gvk, err := codecs.UniversalDeserializer().DecodeObject([]byte(tc.data))
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.name, err)
+		}
+		if *gvk != testGroupVersionV2.WithKind("Secret") {
+			t.Errorf("%s: unexpected kind %v", tc.name, gvk)
+		}
+		if secret, ok := obj.(*secretV2); !ok || secret.Name != "foo" || secret.ExpiresAt != 3600 {
+			t.Errorf("%s: unexpected object %#v", tc.name, obj)
+		}
+	}
+
+	obj, gvk, err := codecs.UniversalDecoder(testGroupVersion).DecodeObject([]byte(testCases[1].data))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if secret, ok := obj.(*Secret); !ok || secret.Name != "foo" || secret.APIVersion != "iam.example.com/v1" ||
+		gvk.Version != "v2" {
+		t.Errorf("unexpected object %#v from %v", obj, gvk)
+	}
+
+	errorCases := map[string]func(error) bool{
+		`{"name":"foo"}`: IsMissingKind,
+		`{"apiVersion":"iam.example.com/v3","kind":"Secret"}`:          IsNotRegisteredError,
+		`{"apiVersion":"iam.example.com/v1","kind":"Secret","name":1}`: func(err error) bool { return err != nil },
+	}
+	for data, check := range errorCases {
+		if _, _, err := codecs.UniversalDeserializer().DecodeObject([]byte(data)); !check(err) {
+			t.Errorf("%s: unexpected error %v", data, err)
+		}
+	}
+}
+
+func TestCodecDecode(t *testing.T) {
+	codecs := NewCodecFactory(newConversionScheme(t))
+	data := []byte("apiVersion: iam.example.com/v2\nkind: Secret\nname: foo\nexpiresAt: 10\n")
+
+	hub := &secretHub{}
+	if err := codecs.UniversalDeserializer().Decode(data, hub); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if hub.Name != "foo" || hub.Expires != 10 {
+		t.Errorf("unexpected object %#v", hub)
+	}
+
+	m := map[string]interface{}{}
+	if err := codecs.UniversalDeserializer().Decode(data, &m); err != nil || m["name"] != "foo" {
+		t.Errorf("unexpected result %v, %v", m, err)
+	}
+}
+
+func TestCodecEncode(t *testing.T) {
+	codecs := NewCodecFactory(newConversionScheme(t))
+
+	secret := &Secret{Name: "foo"}
+	data, err := codecs.EncoderForVersion(nil, testGroupVersionV2).Encode(secret)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(data) != `{"apiVersion":"iam.example.com/v2","kind":"Secret","name":"foo"}` {
+		t.Errorf("unexpected encoding %s", data)
+	}
+	if secret.APIVersion != "" {
+		t.Errorf("expected the encoded object not to be modified")
+	}
+
+	data, err = codecs.CodecForVersions(nil, scheme.GroupVersion{}, scheme.GroupVersion{}).Encode(secret)
+	if err != nil || string(data) != `{"apiVersion":"iam.example.com/v1","kind":"Secret","name":"foo"}` {
+		t.Errorf("unexpected encoding %s, %v", data, err)
+	}
+
+	if _, err := codecs.EncoderForVersion(nil, testGroupVersionV2).Encode(&Policy{}); err == nil {
+		t.Errorf("expected an error for a kind missing in the version")
+	}
+}
+
+func TestStrictUniversalDeserializer(t *testing.T) {
+	var warnings field.ErrorList
+	codecs := NewCodecFactory(newTestScheme())
+	warn := codecs.StrictUniversalDeserializer(json.StrictModeWarn, func(errs field.ErrorList) {
+		warnings = append(warnings, errs...)
+	})
+	strict := codecs.StrictUniversalDeserializer(json.StrictModeError, nil)
+
+	data := "apiVersion: iam.example.com/v1\nkind: Secret\nname: foo\nnmae: bar\n"
+	obj, _, err := warn.DecodeObject([]byte(data))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if secret, ok := obj.(*Secret); !ok || secret.Name != "foo" {
+		t.Errorf("unexpected object %#v", obj)
+	}
+	if len(warnings) != 1 || warnings[0].Field != "nmae" {
+		t.Errorf("expected a warning on nmae, got %v", warnings)
+	}
+
+	if _, _, err := strict.DecodeObject([]byte(data)); !json.IsStrictDecodingError(err) {
+		t.Errorf("expected a strict decoding error, got %v", err)
+	}
+	valid := `{"apiVersion":"iam.example.com/v1","kind":"Secret","name":"foo"}`
+	if _, _, err := strict.DecodeObject([]byte(valid)); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
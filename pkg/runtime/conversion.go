@@ -0,0 +1,207 @@
+// Copyright 2020 Lingfei Kong <colin404@foxmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package runtime
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/HappyLadySauce/component-base/pkg/scheme"
+)
+
+// APIVersionInternal is the version of the internal hub types. Every versioned
+// type of a kind converts to and from the hub type of the kind, so a kind with N
+// versions needs 2N conversion functions instead of N*(N-1).
+// APIVersionInternal是内部中心类型的版本。
+const APIVersionInternal = "__internal"
+
+// ConversionFunc converts in into out. in and out are pointers to the types the
+// function was registered for.
+// ConversionFunc是用来在两个类型之间进行转换的函数。
+type ConversionFunc func(in, out interface{}) error
+
+// DefaultingFunc sets the default values of obj, a pointer to the type the
+// function was registered for.
+// DefaultingFunc是用来设置对象默认值的函数。
+type DefaultingFunc func(obj interface{})
+
+// typePair is the key of a conversion function.
+type typePair struct {
+	source reflect.Type
+	dest   reflect.Type
+}
+
+// AddConversionFunc registers fn to convert objects of the type of a into
+// objects of the type of b. a and b must be pointers to registered types.
+// AddConversionFunc用来注册类型之间的转换函数。
+func (s *Scheme) AddConversionFunc(a, b Object, fn ConversionFunc) error {
+	ta, tb := reflect.TypeOf(a), reflect.TypeOf(b)
+	if ta.Kind() != reflect.Ptr || tb.Kind() != reflect.Ptr {
+		return fmt.Errorf("conversion types must be pointers, got %v and %v", ta, tb)
+	}
+	if fn == nil {
+		return fmt.Errorf("conversion function from %v to %v is nil", ta, tb)
+	}
+
+	s.conversionFuncs[typePair{source: ta.Elem(), dest: tb.Elem()}] = fn
+
+	return nil
+}
+
+// AddTypeDefaultingFunc registers fn to set the default values of the objects of
+// the type of obj. Objects are defaulted when they are decoded.
+// AddTypeDefaultingFunc用来注册类型的默认值设置函数。
+func (s *Scheme) AddTypeDefaultingFunc(obj Object, fn DefaultingFunc) {
+	s.defaultingFuncs[reflect.TypeOf(obj)] = fn
+}
+
+// Default sets the default values of obj, if a defaulting function is registered
+// for its type.
+// Default用来设置对象的默认值。
+func (s *Scheme) Default(obj Object) {
+	if fn, ok := s.defaultingFuncs[reflect.TypeOf(obj)]; ok {
+		fn(obj)
+	}
+}
+
+// Convert converts in into out, which must both be pointers to registered types.
+// A function registered for the pair of types is used when there is one,
+// otherwise in is converted to the internal hub type of its kind, and the hub
+// object to out. Objects of the same type are deep copied.
+// Convert用来将in转换为out，没有直接的转换函数时会经过内部中心类型进行转换。
+func (s *Scheme) Convert(in, out Object) error {
+	tin, tout := reflect.TypeOf(in), reflect.TypeOf(out)
+	if tin.Kind() != reflect.Ptr || tout.Kind() != reflect.Ptr {
+		return fmt.Errorf("conversion types must be pointers, got %v and %v", tin, tout)
+	}
+
+	if tin == tout {
+		reflect.ValueOf(out).Elem().Set(reflect.ValueOf(in.DeepCopyObject()).Elem())
+
+		return nil
+	}
+	if fn, ok := s.conversionFuncs[typePair{source: tin.Elem(), dest: tout.Elem()}]; ok {
+		return fn(in, out)
+	}
+
+	inKind, err := s.objectKind(in)
+	if err != nil {
+		return err
+	}
+	outKind, err := s.objectKind(out)
+	if err != nil {
+		return err
+	}
+
+	hubKind := scheme.GroupVersionKind{Group: inKind.Group, Version: APIVersionInternal, Kind: inKind.Kind}
+	hubType, ok := s.gvkToType[hubKind]
+	if !ok || hubType == tin.Elem() || hubType == tout.Elem() {
+		return &conversionNotFoundErr{from: inKind, to: outKind}
+	}
+	toHub, ok := s.conversionFuncs[typePair{source: tin.Elem(), dest: hubType}]
+	if !ok {
+		return &conversionNotFoundErr{from: inKind, to: hubKind}
+	}
+	fromHub, ok := s.conversionFuncs[typePair{source: hubType, dest: tout.Elem()}]
+	if !ok {
+		return &conversionNotFoundErr{from: hubKind, to: outKind}
+	}
+
+	hub := reflect.New(hubType).Interface()
+	if err := toHub(in, hub); err != nil {
+		return err
+	}
+
+	return fromHub(hub, out)
+}
+
+// ConvertToVersion returns in converted to the same kind in the version target.
+// The kind of the returned object is set.
+// ConvertToVersion用来将对象转换为目标版本。
+func (s *Scheme) ConvertToVersion(in Object, target scheme.GroupVersion) (Object, error) {
+	inKind, err := s.objectKind(in)
+	if err != nil {
+		return nil, err
+	}
+
+	outKind := target.WithKind(inKind.Kind)
+	out, err := s.New(outKind)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.Convert(in, out); err != nil {
+		return nil, err
+	}
+	out.GetObjectKind().SetGroupVersionKind(outKind)
+
+	return out, nil
+}
+
+// DecodeToVersion decodes data, the serialized form of an object of kind gvk,
+// with dec. The object is defaulted and converted to the version target. An
+// empty target keeps the decoded version.
+// DecodeToVersion用来解码对象，设置默认值并转换为目标版本。
+func (s *Scheme) DecodeToVersion(
+	dec Decoder,
+	data []byte,
+	gvk scheme.GroupVersionKind,
+	target scheme.GroupVersion,
+) (Object, error) {
+	obj, err := s.New(gvk)
+	if err != nil {
+		return nil, err
+	}
+	if err := dec.Decode(data, obj); err != nil {
+		return nil, err
+	}
+	// The serialized form may omit or carry a different type information.
+	obj.GetObjectKind().SetGroupVersionKind(gvk)
+	s.Default(obj)
+
+	if target.Empty() || target == gvk.GroupVersion() {
+		return obj, nil
+	}
+
+	return s.ConvertToVersion(obj, target)
+}
+
+// objectKind returns the kind of obj: the kind set on obj if it is registered
+// for its type, the first kind registered for its type otherwise.
+func (s *Scheme) objectKind(obj Object) (scheme.GroupVersionKind, error) {
+	gvks, err := s.ObjectKinds(obj)
+	if err != nil {
+		return scheme.GroupVersionKind{}, err
+	}
+
+	current := obj.GetObjectKind().GroupVersionKind()
+	for _, gvk := range gvks {
+		if gvk == current {
+			return gvk, nil
+		}
+	}
+
+	return gvks[0], nil
+}
+
+// conversionNotFoundErr is returned when no conversion function is registered
+// between two kinds.
+type conversionNotFoundErr struct {
+	from scheme.GroupVersionKind
+	to   scheme.GroupVersionKind
+}
+
+func (e *conversionNotFoundErr) Error() string {
+	return fmt.Sprintf("no conversion function is registered from %q to %q", e.from, e.to)
+}
+
+// IsConversionNotFoundError returns true if the error indicates that no conversion
+// function is registered between two kinds.
+// IsConversionNotFoundError用来判断错误是否为缺少转换函数。
+func IsConversionNotFoundError(err error) bool {
+	var e *conversionNotFoundErr
+
+	return errors.As(err, &e)
+}
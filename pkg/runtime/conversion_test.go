@@ -0,0 +1,166 @@
+// Copyright 2020 Lingfei Kong <colin404@foxmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package runtime
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/HappyLadySauce/component-base/pkg/scheme"
+)
+
+// secretHub is the internal version of the Secret kind.
+type secretHub struct {
+	testTypeMeta
+
+	Name    string
+	Expires int64
+}
+
+func (obj *secretHub) DeepCopyObject() Object {
+	out := *obj
+
+	return &out
+}
+
+// secretV2 renames Expires of the v1 Secret.
+type secretV2 struct {
+	testTypeMeta `json:",inline"`
+
+	Name      string `json:"name,omitempty"`
+	ExpiresAt int64  `json:"expiresAt,omitempty"`
+}
+
+func (obj *secretV2) DeepCopyObject() Object {
+	out := *obj
+
+	return &out
+}
+
+var testGroupVersionInternal = scheme.GroupVersion{Group: "iam.example.com", Version: APIVersionInternal}
+
+func newConversionScheme(t *testing.T) *Scheme {
+	s := NewScheme()
+	s.AddKnownTypes(testGroupVersion, &Secret{}, &Policy{})
+	s.AddKnownTypeWithName(testGroupVersionV2.WithKind("Secret"), &secretV2{})
+	s.AddKnownTypeWithName(testGroupVersionInternal.WithKind("Secret"), &secretHub{})
+
+	funcs := []struct {
+		a, b Object
+		fn   ConversionFunc
+	}{
+		{&Secret{}, &secretHub{}, func(in, out interface{}) error {
+			out.(*secretHub).Name = in.(*Secret).Name
+
+			return nil
+		}},
+		{&secretHub{}, &Secret{}, func(in, out interface{}) error {
+			out.(*Secret).Name = in.(*secretHub).Name
+
+			return nil
+		}},
+		{&secretV2{}, &secretHub{}, func(in, out interface{}) error {
+			out.(*secretHub).Name, out.(*secretHub).Expires = in.(*secretV2).Name, in.(*secretV2).ExpiresAt
+
+			return nil
+		}},
+		{&secretHub{}, &secretV2{}, func(in, out interface{}) error {
+			out.(*secretV2).Name, out.(*secretV2).ExpiresAt = in.(*secretHub).Name, in.(*secretHub).Expires
+
+			return nil
+		}},
+	}
+	for _, f := range funcs {
+		if err := s.AddConversionFunc(f.a, f.b, f.fn); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	s.AddTypeDefaultingFunc(&secretV2{}, func(obj interface{}) {
+		if secret := obj.(*secretV2); secret.ExpiresAt == 0 {
+			secret.ExpiresAt = 3600
+		}
+	})
+
+	return s
+}
+
+func TestConvert(t *testing.T) {
+	s := newConversionScheme(t)
+
+	// v2 -> internal -> v1.
+	v1 := &Secret{}
+	if err := s.Convert(&secretV2{Name: "foo", ExpiresAt: 10}, v1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if v1.Name != "foo" {
+		t.Errorf("unexpected object %#v", v1)
+	}
+
+	// Direct conversion.
+	hub := &secretHub{}
+	if err := s.Convert(&secretV2{Name: "bar", ExpiresAt: 10}, hub); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if hub.Name != "bar" || hub.Expires != 10 {
+		t.Errorf("unexpected object %#v", hub)
+	}
+
+	// Same type.
+	copied := &Secret{}
+	if err := s.Convert(&Secret{Name: "foo"}, copied); err != nil || copied.Name != "foo" {
+		t.Errorf("unexpected result %#v, %v", copied, err)
+	}
+
+	err := s.Convert(&Policy{}, &Secret{})
+	if !IsConversionNotFoundError(err) || !strings.Contains(err.Error(), `"iam.example.com/v1, Kind=Policy"`) ||
+		!strings.Contains(err.Error(), `"iam.example.com/v1, Kind=Secret"`) {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestConvertToVersion(t *testing.T) {
+	s := newConversionScheme(t)
+
+	obj, err := s.ConvertToVersion(&Secret{Name: "foo"}, testGroupVersionV2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	v2, ok := obj.(*secretV2)
+	if !ok || v2.Name != "foo" || v2.GroupVersionKind() != testGroupVersionV2.WithKind("Secret") {
+		t.Errorf("unexpected object %#v", obj)
+	}
+
+	_, err = s.ConvertToVersion(&Policy{}, testGroupVersionV2)
+	if !IsNotRegisteredError(err) {
+		t.Errorf("expected a not registered error, got %v", err)
+	}
+}
+
+func TestDecodeToVersion(t *testing.T) {
+	s := newConversionScheme(t)
+	dec := NewJSONSerializer(false)
+	data := []byte(`{"apiVersion":"iam.example.com/v2","kind":"Secret","name":"foo"}`)
+
+	obj, err := s.DecodeToVersion(dec, data, testGroupVersionV2.WithKind("Secret"), testGroupVersionInternal)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	hub, ok := obj.(*secretHub)
+	if !ok || hub.Name != "foo" || hub.Expires != 3600 {
+		t.Errorf("expected a defaulted internal object, got %#v", obj)
+	}
+
+	obj, err = s.DecodeToVersion(dec, data, testGroupVersionV2.WithKind("Secret"), scheme.GroupVersion{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if v2, ok := obj.(*secretV2); !ok || v2.ExpiresAt != 3600 {
+		t.Errorf("expected a defaulted v2 object, got %#v", obj)
+	}
+
+	if _, err := s.DecodeToVersion(dec, data, testGroupVersionV2.WithKind("Policy"), testGroupVersion); err == nil {
+		t.Errorf("expected an error for an unregistered kind")
+	}
+}
@@ -0,0 +1,129 @@
+// Copyright 2020 Lingfei Kong <colin404@foxmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package runtime
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/HappyLadySauce/component-base/pkg/json"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// DeepCopyJSONValue deep copies the passed value, usually a valid JSON representation,
+// i.e. maps, slices, strings, numbers, bools and nil. Values of other types, such as the
+// ones stored through the typed accessors of Extend, keep their type: maps keyed by
+// strings (Extend included), slices and arrays are copied element by element, scalars
+// and time.Time by value, and anything else through a JSON round trip. It panics only on
+// values which have no JSON representation, such as channels and functions.
+// It is used by generated deep copy functions for interface{} values, such as Extend.
+func DeepCopyJSONValue(x interface{}) interface{} {
+	switch x := x.(type) {
+	case map[string]interface{}:
+		if x == nil {
+			// Typed nil - an interface{} that contains a type map[string]interface{} with a value of nil
+			return x
+		}
+		clone := make(map[string]interface{}, len(x))
+		for k, v := range x {
+			clone[k] = DeepCopyJSONValue(v)
+		}
+
+		return clone
+	case []interface{}:
+		if x == nil {
+			// Typed nil - an interface{} that contains a type []interface{} with a value of nil
+			return x
+		}
+		clone := make([]interface{}, len(x))
+		for i, v := range x {
+			clone[i] = DeepCopyJSONValue(v)
+		}
+
+		return clone
+	case string, int64, bool, float64, nil, json.Number,
+		int, int32, float32, time.Time:
+		return x
+	default:
+		return deepCopyValue(reflect.ValueOf(x)).Interface()
+	}
+}
+
+// deepCopyValue returns a deep copy of v, of the same type.
+func deepCopyValue(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+		return v
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			break
+		}
+		if v.IsNil() {
+			return v
+		}
+		clone := reflect.MakeMapWithSize(v.Type(), v.Len())
+		for iter := v.MapRange(); iter.Next(); {
+			clone.SetMapIndex(iter.Key(), deepCopyElem(iter.Value()))
+		}
+
+		return clone
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		clone := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			clone.Index(i).Set(deepCopyElem(v.Index(i)))
+		}
+
+		return clone
+	case reflect.Array:
+		clone := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.Len(); i++ {
+			clone.Index(i).Set(deepCopyElem(v.Index(i)))
+		}
+
+		return clone
+	case reflect.Struct:
+		if v.Type() == timeType {
+			return v
+		}
+	}
+
+	return deepCopyJSONRoundTrip(v)
+}
+
+// deepCopyElem returns a deep copy of an element of a map, slice or array, which
+// may be an interface.
+func deepCopyElem(v reflect.Value) reflect.Value {
+	if v.Kind() != reflect.Interface {
+		return deepCopyValue(v)
+	}
+	if v.IsNil() {
+		return reflect.Zero(v.Type())
+	}
+
+	return reflect.ValueOf(DeepCopyJSONValue(v.Interface()))
+}
+
+// deepCopyJSONRoundTrip copies v by marshaling it to JSON and unmarshaling the
+// result into a new value of the same type.
+func deepCopyJSONRoundTrip(v reflect.Value) reflect.Value {
+	data, err := json.Marshal(v.Interface())
+	if err != nil {
+		panic(fmt.Errorf("cannot deep copy %s: %w", v.Type(), err))
+	}
+	clone := reflect.New(v.Type())
+	if err := json.Unmarshal(data, clone.Interface()); err != nil {
+		panic(fmt.Errorf("cannot deep copy %s: %w", v.Type(), err))
+	}
+
+	return clone.Elem()
+}
@@ -0,0 +1,59 @@
+// Copyright 2020 Lingfei Kong <colin404@foxmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package runtime
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+type deepCopyQuota struct {
+	CPU    int               `json:"cpu"`
+	Limits map[string]string `json:"limits"`
+}
+
+type deepCopyLabels map[string]interface{}
+
+func TestDeepCopyJSONValue(t *testing.T) {
+	now := time.Date(2020, 5, 4, 3, 2, 1, 0, time.UTC)
+	in := map[string]interface{}{
+		"tags":   []string{"a", "b"},
+		"n":      uint64(3),
+		"u8":     uint8(1),
+		"since":  now,
+		"times":  []time.Time{now},
+		"nested": deepCopyLabels{"ports": []int{80}, "none": nil},
+		"pair":   [2]string{"x", "y"},
+		"quota":  &deepCopyQuota{CPU: 2, Limits: map[string]string{"memory": "1Gi"}},
+		"nilPtr": (*deepCopyQuota)(nil),
+		"nilMap": map[string]string(nil),
+	}
+
+	out := DeepCopyJSONValue(in).(map[string]interface{})
+	if !reflect.DeepEqual(in, out) {
+		t.Fatalf("copy differs from the original: %#v", out)
+	}
+
+	out["tags"].([]string)[0] = "z"
+	out["nested"].(deepCopyLabels)["ports"].([]int)[0] = 443
+	out["quota"].(*deepCopyQuota).Limits["memory"] = "2Gi"
+	out["times"].([]time.Time)[0] = now.Add(time.Hour)
+
+	if in["tags"].([]string)[0] != "a" || in["nested"].(deepCopyLabels)["ports"].([]int)[0] != 80 ||
+		in["quota"].(*deepCopyQuota).Limits["memory"] != "1Gi" || !in["times"].([]time.Time)[0].Equal(now) {
+		t.Errorf("modifying the copy changed the original: %#v", in)
+	}
+}
+
+func TestDeepCopyJSONValuePanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected a panic on a value without JSON representation")
+		}
+	}()
+
+	DeepCopyJSONValue(make(chan int))
+}
@@ -0,0 +1,263 @@
+// Copyright 2020 Lingfei Kong <colin404@foxmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package runtime
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// DefaultMaxFrameSize is the frame size limit used when none is given.
+const DefaultMaxFrameSize = 16 * 1024 * 1024
+
+// ErrFrameTooLarge is returned when a frame exceeds the frame size limit of a FrameReader.
+var ErrFrameTooLarge = errors.New("frame exceeds the maximum frame size")
+
+// FrameReader reads the frames of a stream one at a time.
+type FrameReader interface {
+	// ReadFrame returns the next frame. It returns io.EOF when the stream ends
+	// between two frames.
+	ReadFrame() ([]byte, error)
+}
+
+// FrameWriter writes frames to a stream.
+type FrameWriter interface {
+	// WriteFrame writes frame, which must be a single encoded object.
+	WriteFrame(frame []byte) error
+}
+
+// Framer splits a stream into frames, each holding a single encoded object.
+// Framer是用来将流切分为帧的接口，每一帧包含一个编码后的对象。
+type Framer interface {
+	// NewFrameReader returns a FrameReader refusing frames larger than
+	// maxFrameSize bytes. DefaultMaxFrameSize is used when maxFrameSize is not positive.
+	NewFrameReader(r io.Reader, maxFrameSize int) FrameReader
+	// NewFrameWriter returns a FrameWriter writing to w.
+	NewFrameWriter(w io.Writer) FrameWriter
+}
+
+// The framers of this package.
+var (
+	// NewlineFramer frames newline-delimited documents, such as NDJSON. Empty
+	// lines are skipped, so frames must not hold newlines.
+	NewlineFramer Framer = newlineFramer{}
+	// YAMLFramer frames YAML documents separated by "---" lines.
+	YAMLFramer Framer = yamlFramer{}
+	// LengthDelimitedFramer frames binary documents, each preceded by its length
+	// as a 4 bytes big-endian unsigned integer.
+	LengthDelimitedFramer Framer = lengthDelimitedFramer{}
+)
+
+func maxFrameSizeOrDefault(maxFrameSize int) int {
+	if maxFrameSize <= 0 {
+		return DefaultMaxFrameSize
+	}
+
+	return maxFrameSize
+}
+
+// lineReader reads the lines of a stream, refusing lines larger than a limit.
+type lineReader struct {
+	r   *bufio.Reader
+	max int
+}
+
+// readLine returns the next line, with its line feed. The last line of the
+// stream may not end with a line feed.
+func (r *lineReader) readLine() ([]byte, error) {
+	var line []byte
+	for {
+		chunk, err := r.r.ReadSlice('\n')
+		if len(line)+len(chunk) > r.max {
+			return nil, ErrFrameTooLarge
+		}
+		line = append(line, chunk...)
+
+		switch {
+		case errors.Is(err, bufio.ErrBufferFull):
+			continue
+		case errors.Is(err, io.EOF) && len(line) > 0:
+			return line, nil
+		default:
+			return line, err
+		}
+	}
+}
+
+type newlineFramer struct{}
+
+func (newlineFramer) NewFrameReader(r io.Reader, maxFrameSize int) FrameReader {
+	// The line feed is not part of the frame.
+	return &newlineFrameReader{lineReader{r: bufio.NewReader(r), max: maxFrameSizeOrDefault(maxFrameSize) + 1}}
+}
+
+func (newlineFramer) NewFrameWriter(w io.Writer) FrameWriter {
+	return &newlineFrameWriter{w: w}
+}
+
+type newlineFrameReader struct {
+	lineReader
+}
+
+func (r *newlineFrameReader) ReadFrame() ([]byte, error) {
+	for {
+		line, err := r.readLine()
+		if err != nil {
+			return nil, err
+		}
+		if line = bytes.TrimRight(line, "\r\n"); len(bytes.TrimSpace(line)) > 0 {
+			return line, nil
+		}
+	}
+}
+
+type newlineFrameWriter struct {
+	w io.Writer
+}
+
+func (w *newlineFrameWriter) WriteFrame(frame []byte) error {
+	frame = bytes.TrimRight(frame, "\n")
+	if bytes.IndexByte(frame, '\n') >= 0 {
+		return fmt.Errorf("newline-delimited frames can not hold newlines")
+	}
+
+	// The frame is copied, appending to it could overwrite the caller's buffer.
+	buf := make([]byte, 0, len(frame)+1)
+	buf = append(buf, frame...)
+	_, err := w.w.Write(append(buf, '\n'))
+
+	return err
+}
+
+type yamlFramer struct{}
+
+func (yamlFramer) NewFrameReader(r io.Reader, maxFrameSize int) FrameReader {
+	return &yamlFrameReader{lineReader{r: bufio.NewReader(r), max: maxFrameSizeOrDefault(maxFrameSize)}}
+}
+
+func (yamlFramer) NewFrameWriter(w io.Writer) FrameWriter {
+	return &yamlFrameWriter{w: w}
+}
+
+type yamlFrameReader struct {
+	lineReader
+}
+
+// yamlSeparator is the line separating YAML documents.
+const yamlSeparator = "---"
+
+func (r *yamlFrameReader) ReadFrame() ([]byte, error) {
+	var doc []byte
+	for {
+		line, err := r.readLine()
+		if errors.Is(err, io.EOF) {
+			if len(bytes.TrimSpace(doc)) > 0 {
+				return doc, nil
+			}
+
+			return nil, io.EOF
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if sep := bytes.TrimRight(line, " \t\r\n"); bytes.Equal(sep, []byte(yamlSeparator)) {
+			// Empty documents are skipped.
+			if len(bytes.TrimSpace(doc)) > 0 {
+				return doc, nil
+			}
+			doc = doc[:0]
+
+			continue
+		}
+		if len(doc)+len(line) > r.max {
+			return nil, ErrFrameTooLarge
+		}
+		doc = append(doc, line...)
+	}
+}
+
+type yamlFrameWriter struct {
+	w       io.Writer
+	written bool
+}
+
+func (w *yamlFrameWriter) WriteFrame(frame []byte) error {
+	var buf bytes.Buffer
+	if w.written {
+		buf.WriteString(yamlSeparator + "\n")
+	}
+	buf.Write(frame)
+	if len(frame) == 0 || frame[len(frame)-1] != '\n' {
+		buf.WriteByte('\n')
+	}
+
+	if _, err := w.w.Write(buf.Bytes()); err != nil {
+		return err
+	}
+	w.written = true
+
+	return nil
+}
+
+type lengthDelimitedFramer struct{}
+
+func (lengthDelimitedFramer) NewFrameReader(r io.Reader, maxFrameSize int) FrameReader {
+	return &lengthDelimitedFrameReader{r: r, max: maxFrameSizeOrDefault(maxFrameSize)}
+}
+
+func (lengthDelimitedFramer) NewFrameWriter(w io.Writer) FrameWriter {
+	return &lengthDelimitedFrameWriter{w: w}
+}
+
+type lengthDelimitedFrameReader struct {
+	r   io.Reader
+	max int
+}
+
+func (r *lengthDelimitedFrameReader) ReadFrame() ([]byte, error) {
+	var header [4]byte
+	if _, err := io.ReadFull(r.r, header[:]); err != nil {
+		return nil, err
+	}
+
+	// The length is checked before allocating the frame.
+	length := binary.BigEndian.Uint32(header[:])
+	if uint64(length) > uint64(r.max) {
+		return nil, ErrFrameTooLarge
+	}
+
+	frame := make([]byte, length)
+	if _, err := io.ReadFull(r.r, frame); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, io.ErrUnexpectedEOF
+		}
+
+		return nil, err
+	}
+
+	return frame, nil
+}
+
+type lengthDelimitedFrameWriter struct {
+	w io.Writer
+}
+
+func (w *lengthDelimitedFrameWriter) WriteFrame(frame []byte) error {
+	if uint64(len(frame)) > uint64(^uint32(0)) {
+		return ErrFrameTooLarge
+	}
+
+	buf := make([]byte, 4+len(frame))
+	binary.BigEndian.PutUint32(buf, uint32(len(frame)))
+	copy(buf[4:], frame)
+	_, err := w.w.Write(buf)
+
+	return err
+}
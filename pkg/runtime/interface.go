@@ -5,6 +5,8 @@
 // Package runtime defines some functions used to encode/decode object.
 package runtime
 
+import "github.com/HappyLadySauce/component-base/pkg/scheme"
+
 // Encoder writes objects to a serialized form.
 type Encoder interface {
 	// Encode writes an object to a stream. Implementations may return errors if the versions are
@@ -18,9 +20,32 @@ type Decoder interface {
 }
 
 // ClientNegotiator handles turning an HTTP content type into the appropriate encoder.
-// Use NewClientNegotiator or NewVersionedClientNegotiator to create this interface from
-// a NegotiatedSerializer.
+// Use NewClientNegotiator or NewSimpleClientNegotiator to create this interface.
 type ClientNegotiator interface {
 	Encoder() (Encoder, error)
 	Decoder() (Decoder, error)
 }
+
+// ContentTypeNegotiator is a ClientNegotiator which also negotiates the serializers of
+// other content types than the default one of Encoder and Decoder.
+// Use NewClientNegotiator to create this interface from a NegotiatedSerializer.
+type ContentTypeNegotiator interface {
+	ClientNegotiator
+
+	// EncoderFor returns the appropriate encoder for the provided contentType (e.g. application/json)
+	// and any optional mediaType parameters (e.g. pretty=1), or an error. If no serializer is found
+	// a NegotiateError will be returned.
+	EncoderFor(contentType string, params map[string]string) (Encoder, error)
+	// DecoderFor returns the appropriate decoder for the provided contentType (e.g. application/json)
+	// and any optional mediaType parameters (e.g. pretty=1), or an error. If no serializer is found
+	// a NegotiateError will be returned.
+	DecoderFor(contentType string, params map[string]string) (Decoder, error)
+}
+
+// Object interface must be supported by all API types registered with Scheme. Since objects in a scheme are
+// expected to be serialized to the wire, the interface an Object must provide to the Scheme allows
+// serializers to set the kind, version, and group the object is represented as.
+type Object interface {
+	GetObjectKind() scheme.ObjectKind
+	DeepCopyObject() Object
+}
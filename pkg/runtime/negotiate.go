@@ -6,8 +6,12 @@ package runtime
 
 import (
 	"fmt"
+	"mime"
+	"sort"
+	"strconv"
+	"strings"
 
-	"github.com/HappyLadySauce/component-base/pkg/json"
+	"github.com/HappyLadySauce/component-base/pkg/scheme"
 )
 
 // NegotiateError is returned when a ClientNegotiator is unable to locate
@@ -24,32 +28,273 @@ func (e NegotiateError) Error() string {
 	return fmt.Sprintf("no serializers registered for %s", e.ContentType)
 }
 
-type apimachineryClientNegotiator struct{}
+// SerializerInfo contains information about a specific serialization format.
+// SerializerInfo是用来描述一种序列化格式的结构体。
+type SerializerInfo struct {
+	// MediaType is the value that represents this serializer over the wire.
+	MediaType string
+	// EncodesAsText indicates this serializer can be encoded to UTF-8 safely.
+	EncodesAsText bool
+	// Serializer is the individual object serializer for this media type.
+	Serializer Serializer
+	// PrettySerializer, if set, can serialize this object in a form biased towards
+	// readability.
+	PrettySerializer Serializer
+	// StreamSerializer, if set, describes the streaming serialization format
+	// for this media type.
+	StreamSerializer *StreamSerializerInfo
+}
 
-var _ ClientNegotiator = &apimachineryClientNegotiator{}
+// StreamSerializerInfo contains information about a specific stream serialization format.
+// StreamSerializerInfo是用来描述一种流式序列化格式的结构体。
+type StreamSerializerInfo struct {
+	// EncodesAsText indicates this serializer can be encoded to UTF-8 safely.
+	EncodesAsText bool
+	// Serializer is the top level object serializer for this type when streaming.
+	Serializer Serializer
+	// Framer is the factory for retrieving streams that separate objects on the wire.
+	Framer Framer
+}
 
-func (n *apimachineryClientNegotiator) Encoder() (Encoder, error) {
-	return &apimachineryClientNegotiatorSerializer{}, nil
+// MediaTypeOptions are the parameters of a negotiated media type.
+// MediaTypeOptions是协商出的媒体类型的参数。
+type MediaTypeOptions struct {
+	// Pretty is set by the "pretty" parameter, e.g. application/json;pretty=true.
+	Pretty bool
+	// Stream is set by the "stream" parameter, e.g. application/json;stream=watch.
+	Stream bool
 }
 
-func (n *apimachineryClientNegotiator) Decoder() (Decoder, error) {
-	return &apimachineryClientNegotiatorSerializer{}, nil
+// NewMediaTypeOptions parses the parameters of a media type.
+// NewMediaTypeOptions用来解析媒体类型的参数。
+func NewMediaTypeOptions(params map[string]string) MediaTypeOptions {
+	pretty, _ := strconv.ParseBool(params["pretty"])
+
+	return MediaTypeOptions{Pretty: pretty, Stream: len(params["stream"]) > 0}
 }
 
-type apimachineryClientNegotiatorSerializer struct{}
+// SerializerFor returns the serializer of info matching options. Formats
+// without a pretty form fall back to their serializer.
+// SerializerFor用来返回与参数匹配的序列化器。
+func (info SerializerInfo) SerializerFor(options MediaTypeOptions) (Serializer, error) {
+	switch {
+	case options.Stream:
+		if info.StreamSerializer == nil {
+			return nil, NegotiateError{ContentType: info.MediaType, Stream: true}
+		}
+
+		return info.StreamSerializer.Serializer, nil
+	case options.Pretty && info.PrettySerializer != nil:
+		return info.PrettySerializer, nil
+	default:
+		return info.Serializer, nil
+	}
+}
+
+// NegotiatedSerializer is an interface used for obtaining encoders, decoders,
+// and serializers for multiple supported media types.
+// NegotiatedSerializer是用来为多种媒体类型提供序列化器的接口。
+type NegotiatedSerializer interface {
+	// SupportedMediaTypes is the media types supported for reading and writing
+	// single objects, in order of preference.
+	SupportedMediaTypes() []SerializerInfo
+
+	// EncoderForVersion returns an encoder that ensures objects being written to
+	// the provided serializer are in the provided group version.
+	EncoderForVersion(serializer Encoder, gv scheme.GroupVersion) Encoder
+	// DecoderToVersion returns a decoder that ensures objects being read by the
+	// provided serializer are in the provided group version by default.
+	DecoderToVersion(serializer Decoder, gv scheme.GroupVersion) Decoder
+}
 
-var _ Decoder = &apimachineryClientNegotiatorSerializer{}
+// SerializerInfoForMediaType returns the info of the media type, ignoring its parameters.
+// SerializerInfoForMediaType用来返回媒体类型对应的序列化格式。
+func SerializerInfoForMediaType(types []SerializerInfo, mediaType string) (SerializerInfo, bool) {
+	for _, info := range types {
+		if info.MediaType == mediaType {
+			return info, true
+		}
+	}
+
+	return SerializerInfo{}, false
+}
+
+// NegotiateInputSerializer returns the serializer of the body of a request from
+// its Content-Type header. An empty header means JSON.
+// NegotiateInputSerializer用来根据Content-Type请求头选择请求体的序列化器。
+func NegotiateInputSerializer(contentType string, ns NegotiatedSerializer) (SerializerInfo, MediaTypeOptions, error) {
+	if len(contentType) == 0 {
+		contentType = ContentTypeJSON
+	}
+
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return SerializerInfo{}, MediaTypeOptions{}, NegotiateError{ContentType: contentType}
+	}
+	info, ok := SerializerInfoForMediaType(ns.SupportedMediaTypes(), mediaType)
+	if !ok {
+		return SerializerInfo{}, MediaTypeOptions{}, NegotiateError{ContentType: contentType}
+	}
+
+	return info, NewMediaTypeOptions(params), nil
+}
+
+// NegotiateOutputMediaType returns the serializer of a response from the Accept
+// header of the request. The accepted media types are tried by decreasing
+// quality, and wildcards select the first supported media type. An empty header
+// accepts anything.
+// NegotiateOutputMediaType用来根据Accept请求头选择响应的序列化器。
+func NegotiateOutputMediaType(accept string, ns NegotiatedSerializer) (SerializerInfo, MediaTypeOptions, error) {
+	supported := ns.SupportedMediaTypes()
+	if len(strings.TrimSpace(accept)) == 0 {
+		accept = "*/*"
+	}
+
+	for _, clause := range parseAccept(accept) {
+		for _, info := range supported {
+			if mediaTypeMatches(clause.mediaType, info.MediaType) {
+				return info, NewMediaTypeOptions(clause.params), nil
+			}
+		}
+	}
+
+	return SerializerInfo{}, MediaTypeOptions{}, NegotiateError{ContentType: accept}
+}
+
+// acceptClause is a media range of an Accept header.
+type acceptClause struct {
+	mediaType string
+	params    map[string]string
+	quality   float64
+}
+
+// parseAccept returns the acceptable media ranges of an Accept header, by
+// decreasing quality. Invalid and unacceptable (q=0) ranges are dropped.
+func parseAccept(accept string) []acceptClause {
+	var clauses []acceptClause
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+
+		quality := 1.0
+		if q, ok := params["q"]; ok {
+			if quality, err = strconv.ParseFloat(q, 64); err != nil {
+				continue
+			}
+			delete(params, "q")
+		}
+		if quality <= 0 {
+			continue
+		}
+		clauses = append(clauses, acceptClause{mediaType: mediaType, params: params, quality: quality})
+	}
+
+	sort.SliceStable(clauses, func(i, j int) bool {
+		return clauses[i].quality > clauses[j].quality
+	})
+
+	return clauses
+}
+
+// mediaTypeMatches reports whether the media range pattern, such as */* or
+// application/*, matches mediaType.
+func mediaTypeMatches(pattern, mediaType string) bool {
+	if pattern == "*/*" || pattern == mediaType {
+		return true
+	}
+
+	prefix := strings.TrimSuffix(pattern, "*")
+
+	return prefix != pattern && strings.HasSuffix(prefix, "/") && strings.HasPrefix(mediaType, prefix)
+}
+
+// clientNegotiator negotiates the serializers of a client from the media types
+// of a NegotiatedSerializer.
+type clientNegotiator struct {
+	serializer NegotiatedSerializer
+	version    scheme.GroupVersion
+}
+
+var _ ContentTypeNegotiator = &clientNegotiator{}
+
+// NewClientNegotiator returns a ContentTypeNegotiator encoding objects in gv with the
+// media types of serializer. Decoded objects are not converted. Encoder and Decoder
+// negotiate JSON.
+// NewClientNegotiator用来创建ContentTypeNegotiator。
+func NewClientNegotiator(serializer NegotiatedSerializer, gv scheme.GroupVersion) ContentTypeNegotiator {
+	return &clientNegotiator{serializer: serializer, version: gv}
+}
+
+func (n *clientNegotiator) Encoder() (Encoder, error) {
+	return n.EncoderFor(ContentTypeJSON, nil)
+}
+
+func (n *clientNegotiator) Decoder() (Decoder, error) {
+	return n.DecoderFor(ContentTypeJSON, nil)
+}
+
+func (n *clientNegotiator) EncoderFor(contentType string, params map[string]string) (Encoder, error) {
+	s, err := n.negotiate(contentType, params)
+	if err != nil {
+		return nil, err
+	}
+
+	return n.serializer.EncoderForVersion(s, n.version), nil
+}
+
+func (n *clientNegotiator) DecoderFor(contentType string, params map[string]string) (Decoder, error) {
+	s, err := n.negotiate(contentType, params)
+	if err != nil {
+		return nil, err
+	}
+
+	return n.serializer.DecoderToVersion(s, scheme.GroupVersion{}), nil
+}
+
+// negotiate returns the serializer of contentType. The parameters of contentType
+// are merged with params, which take precedence.
+func (n *clientNegotiator) negotiate(contentType string, params map[string]string) (Serializer, error) {
+	mediaType, ctParams, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, NegotiateError{ContentType: contentType}
+	}
+	for k, v := range params {
+		ctParams[k] = v
+	}
+
+	info, ok := SerializerInfoForMediaType(n.serializer.SupportedMediaTypes(), mediaType)
+	if !ok {
+		return nil, NegotiateError{ContentType: contentType, Stream: len(ctParams["stream"]) > 0}
+	}
+
+	return info.SerializerFor(NewMediaTypeOptions(ctParams))
+}
+
+// simpleNegotiatedSerializer supports JSON only and never converts objects.
+type simpleNegotiatedSerializer struct{}
+
+func (simpleNegotiatedSerializer) SupportedMediaTypes() []SerializerInfo {
+	return []SerializerInfo{{
+		MediaType:        ContentTypeJSON,
+		EncodesAsText:    true,
+		Serializer:       NewJSONSerializer(false),
+		PrettySerializer: NewJSONSerializer(true),
+	}}
+}
 
-func (s *apimachineryClientNegotiatorSerializer) Decode(data []byte, v interface{}) error {
-	return json.Unmarshal(data, v)
+func (simpleNegotiatedSerializer) EncoderForVersion(serializer Encoder, gv scheme.GroupVersion) Encoder {
+	return serializer
 }
 
-func (s *apimachineryClientNegotiatorSerializer) Encode(v interface{}) ([]byte, error) {
-	return json.Marshal(v)
+func (simpleNegotiatedSerializer) DecoderToVersion(serializer Decoder, gv scheme.GroupVersion) Decoder {
+	return serializer
 }
 
-// NewSimpleClientNegotiator will negotiate for a single serializer. This should only be used
+// NewSimpleClientNegotiator will negotiate for a single serializer, JSON. This should only be used
 // for testing or when the caller is taking responsibility for setting the GVK on encoded objects.
+// The returned negotiator is also a ContentTypeNegotiator.
 func NewSimpleClientNegotiator() ClientNegotiator {
-	return &apimachineryClientNegotiator{}
+	return NewClientNegotiator(simpleNegotiatedSerializer{}, scheme.GroupVersion{})
 }
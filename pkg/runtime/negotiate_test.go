@@ -0,0 +1,224 @@
+// Copyright 2020 Lingfei Kong <colin404@foxmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package runtime
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/HappyLadySauce/component-base/pkg/json"
+)
+
+func TestSerializers(t *testing.T) {
+	testCases := []struct {
+		name       string
+		serializer Serializer
+		expected   string
+	}{
+		{"json", NewJSONSerializer(false), `{"apiVersion":"iam.example.com/v1","kind":"Secret","name":"foo"}`},
+		{
+			"pretty json",
+			NewJSONSerializer(true),
+			"{\n  \"apiVersion\": \"iam.example.com/v1\",\n  \"kind\": \"Secret\",\n  \"name\": \"foo\"\n}",
+		},
+		{"yaml", NewYAMLSerializer(), "apiVersion: iam.example.com/v1\nkind: Secret\nname: foo\n"},
+		{"cbor", NewCBORSerializer(), ""},
+	}
+
+	in := &Secret{Name: "foo"}
+	in.SetGroupVersionKind(testGroupVersion.WithKind("Secret"))
+	for _, tc := range testCases {
+		data, err := tc.serializer.Encode(in)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.name, err)
+		}
+		if tc.expected != "" && string(data) != tc.expected {
+			t.Errorf("%s: expected %q, got %q", tc.name, tc.expected, data)
+		}
+
+		out := &Secret{}
+		if err := tc.serializer.Decode(data, out); err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.name, err)
+		}
+		if *out != *in {
+			t.Errorf("%s: expected %#v, got %#v", tc.name, in, out)
+		}
+	}
+}
+
+func TestNegotiateOutputMediaType(t *testing.T) {
+	codecs := NewCodecFactory(newTestScheme())
+
+	testCases := []struct {
+		accept    string
+		mediaType string
+		options   MediaTypeOptions
+	}{
+		{"", ContentTypeJSON, MediaTypeOptions{}},
+		{"*/*", ContentTypeJSON, MediaTypeOptions{}},
+		{"application/yaml", ContentTypeYAML, MediaTypeOptions{}},
+		{"text/html, application/cbor;q=0.5, application/yaml;q=0.8", ContentTypeYAML, MediaTypeOptions{}},
+		{"application/json;pretty=1;q=0.5, application/xml", ContentTypeJSON, MediaTypeOptions{Pretty: true}},
+		{"application/cbor;stream=watch", ContentTypeCBOR, MediaTypeOptions{Stream: true}},
+		{"application/json;q=0, application/*", ContentTypeJSON, MediaTypeOptions{}},
+	}
+	for _, tc := range testCases {
+		info, options, err := NegotiateOutputMediaType(tc.accept, codecs)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tc.accept, err)
+
+			continue
+		}
+		if info.MediaType != tc.mediaType || options != tc.options {
+			t.Errorf("%q: expected %s %+v, got %s %+v", tc.accept, tc.mediaType, tc.options, info.MediaType, options)
+		}
+	}
+
+	for _, accept := range []string{"text/html", "application/json;q=0", "image/*"} {
+		_, _, err := NegotiateOutputMediaType(accept, codecs)
+		if !errors.As(err, &NegotiateError{}) {
+			t.Errorf("%q: expected a NegotiateError, got %v", accept, err)
+		}
+	}
+}
+
+func TestNegotiateInputSerializer(t *testing.T) {
+	codecs := NewCodecFactory(newTestScheme())
+
+	for contentType, mediaType := range map[string]string{
+		"":                                ContentTypeJSON,
+		"application/json; charset=utf-8": ContentTypeJSON,
+		"application/yaml":                ContentTypeYAML,
+		"application/cbor":                ContentTypeCBOR,
+	} {
+		info, _, err := NegotiateInputSerializer(contentType, codecs)
+		if err != nil || info.MediaType != mediaType {
+			t.Errorf("%q: expected %s, got %s, %v", contentType, mediaType, info.MediaType, err)
+		}
+	}
+
+	_, _, err := NegotiateInputSerializer("text/plain", codecs)
+	if !errors.As(err, &NegotiateError{}) {
+		t.Errorf("expected a NegotiateError, got %v", err)
+	}
+}
+
+func TestClientNegotiator(t *testing.T) {
+	codecs := NewCodecFactory(newConversionScheme(t))
+	n := NewClientNegotiator(codecs, testGroupVersionV2)
+
+	enc, err := n.EncoderFor(ContentTypeCBOR, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, err := enc.Encode(&Secret{Name: "foo"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	dec, err := n.DecoderFor(ContentTypeCBOR, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out := &secretV2{}
+	if err := dec.Decode(data, out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.Name != "foo" || out.GroupVersionKind() != testGroupVersionV2.WithKind("Secret") {
+		t.Errorf("unexpected object %#v", out)
+	}
+
+	enc, err = n.EncoderFor("application/json;pretty=true", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if data, _ := enc.Encode(&Secret{Name: "foo"}); data[1] != '\n' {
+		t.Errorf("expected a pretty encoding, got %s", data)
+	}
+
+	errorCases := []struct {
+		contentType string
+		params      map[string]string
+		expected    NegotiateError
+	}{
+		{"application/xml", nil, NegotiateError{ContentType: "application/xml"}},
+		{"application/xml;stream=watch", nil, NegotiateError{ContentType: "application/xml;stream=watch", Stream: true}},
+	}
+	for _, tc := range errorCases {
+		_, err := n.EncoderFor(tc.contentType, tc.params)
+		var negotiateErr NegotiateError
+		if !errors.As(err, &negotiateErr) || negotiateErr != tc.expected {
+			t.Errorf("%s: expected %v, got %v", tc.contentType, tc.expected, err)
+		}
+	}
+}
+
+func TestSimpleClientNegotiator(t *testing.T) {
+	n := NewSimpleClientNegotiator().(ContentTypeNegotiator)
+
+	enc, err := n.EncoderFor(ContentTypeJSON, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Objects are encoded as is.
+	if data, _ := enc.Encode(&Secret{Name: "foo"}); string(data) != `{"name":"foo"}` {
+		t.Errorf("unexpected encoding %s", data)
+	}
+
+	if _, err := n.DecoderFor(ContentTypeYAML, nil); !errors.As(err, &NegotiateError{}) {
+		t.Errorf("expected a NegotiateError, got %v", err)
+	}
+	if _, err := n.DecoderFor(ContentTypeJSON, map[string]string{"stream": "watch"}); err == nil {
+		t.Errorf("expected an error for a stream decoder")
+	}
+	if _, err := n.EncoderFor("application/json;pretty=1", nil); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	// Encoder and Decoder negotiate JSON.
+	enc, err = n.Encoder()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, err := enc.Encode(&Secret{Name: "foo"})
+	if err != nil || string(data) != `{"name":"foo"}` {
+		t.Errorf("unexpected encoding %s: %v", data, err)
+	}
+	dec, err := n.Decoder()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out := &Secret{}
+	if err := dec.Decode(data, out); err != nil || out.Name != "foo" {
+		t.Errorf("unexpected decoding %#v: %v", out, err)
+	}
+}
+
+func TestStrictSerializers(t *testing.T) {
+	testCases := []struct {
+		name       string
+		serializer Serializer
+		data       string
+	}{
+		{"json", NewStrictJSONSerializer(json.StrictModeError, nil), `{"name":"foo","Name":"bar"}`},
+		{"yaml", NewStrictYAMLSerializer(json.StrictModeError, nil), "name: foo\nkinds: Secret\n"},
+	}
+
+	for _, tc := range testCases {
+		err := tc.serializer.Decode([]byte(tc.data), &Secret{})
+		if !json.IsStrictDecodingError(err) {
+			t.Errorf("%s: expected a strict decoding error, got %v", tc.name, err)
+		}
+
+		out := &Secret{}
+		if err := tc.serializer.(lenientDecoder).lenient().Decode([]byte(tc.data), out); err != nil {
+			t.Errorf("%s: unexpected error: %v", tc.name, err)
+		}
+		if data, _ := tc.serializer.Encode(out); !strings.Contains(string(data), "name") {
+			t.Errorf("%s: unexpected encoding %s", tc.name, data)
+		}
+	}
+}
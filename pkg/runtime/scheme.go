@@ -0,0 +1,238 @@
+// Copyright 2020 Lingfei Kong <colin404@foxmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package runtime
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/HappyLadySauce/component-base/pkg/scheme"
+)
+
+// Scheme maps the kinds of the API groups, identified by GroupVersionKind, to
+// the Go types which represent them. API groups register their types with
+// AddKnownTypes, usually from an init or AddToScheme function, before the
+// scheme is used. A Scheme is safe for concurrent reads once registration is over.
+// Scheme是用来维护GroupVersionKind和Go类型之间映射关系的注册表。
+type Scheme struct {
+	// gvkToType maps a kind to the Go type representing it.
+	gvkToType map[scheme.GroupVersionKind]reflect.Type
+
+	// typeToGVK maps a Go type to the kinds it represents, in registration order.
+	typeToGVK map[reflect.Type][]scheme.GroupVersionKind
+
+	// observedVersions keeps the registered group versions in registration order.
+	observedVersions []scheme.GroupVersion
+
+	// conversionFuncs maps a pair of types to the function converting between them.
+	conversionFuncs map[typePair]ConversionFunc
+
+	// defaultingFuncs maps a pointer type to the function setting its default values.
+	defaultingFuncs map[reflect.Type]DefaultingFunc
+}
+
+// NewScheme creates an empty Scheme.
+// NewScheme用来创建Scheme。
+func NewScheme() *Scheme {
+	return &Scheme{
+		gvkToType:       map[scheme.GroupVersionKind]reflect.Type{},
+		typeToGVK:       map[reflect.Type][]scheme.GroupVersionKind{},
+		conversionFuncs: map[typePair]ConversionFunc{},
+		defaultingFuncs: map[reflect.Type]DefaultingFunc{},
+	}
+}
+
+// AddKnownTypes registers the types of objs in the group version gv. The kind of
+// each type is the name of its struct. All objs must be pointers to structs.
+// AddKnownTypes用来注册组版本中的类型，类型的名称即为Kind。
+func (s *Scheme) AddKnownTypes(gv scheme.GroupVersion, objs ...Object) {
+	for _, obj := range objs {
+		t := reflect.TypeOf(obj)
+		if t.Kind() != reflect.Ptr || t.Elem().Kind() != reflect.Struct {
+			panic(fmt.Sprintf("all types must be pointers to structs, got %v", t))
+		}
+		s.AddKnownTypeWithName(gv.WithKind(t.Elem().Name()), obj)
+	}
+}
+
+// AddKnownTypeWithName registers the type of obj as the kind gvk. Registering a
+// different type for a kind which is already registered panics.
+// AddKnownTypeWithName用来以指定的Kind注册类型。
+func (s *Scheme) AddKnownTypeWithName(gvk scheme.GroupVersionKind, obj Object) {
+	t := reflect.TypeOf(obj)
+	if t.Kind() != reflect.Ptr || t.Elem().Kind() != reflect.Struct {
+		panic(fmt.Sprintf("all types must be pointers to structs, got %v", t))
+	}
+	if len(gvk.Version) == 0 || len(gvk.Kind) == 0 {
+		panic(fmt.Sprintf("version and kind are required when registering %v, got %v", t, gvk))
+	}
+
+	t = t.Elem()
+	if old, ok := s.gvkToType[gvk]; ok {
+		if old == t {
+			return
+		}
+		panic(fmt.Sprintf("double registration of different types for %v: old=%v, new=%v", gvk, old, t))
+	}
+
+	s.addObservedVersion(gvk.GroupVersion())
+	s.gvkToType[gvk] = t
+	s.typeToGVK[t] = append(s.typeToGVK[t], gvk)
+}
+
+// KnownTypes returns the types registered in the group version gv, by kind.
+// KnownTypes用来返回组版本中注册的类型。
+func (s *Scheme) KnownTypes(gv scheme.GroupVersion) map[string]reflect.Type {
+	types := map[string]reflect.Type{}
+	for gvk, t := range s.gvkToType {
+		if gvk.GroupVersion() == gv {
+			types[gvk.Kind] = t
+		}
+	}
+
+	return types
+}
+
+// AllKnownTypes returns all the registered types, by kind. The returned map must not be modified.
+// AllKnownTypes用来返回所有注册的类型。
+func (s *Scheme) AllKnownTypes() map[scheme.GroupVersionKind]reflect.Type {
+	return s.gvkToType
+}
+
+// PrioritizedVersionsAllGroups returns the registered group versions in registration order.
+// PrioritizedVersionsAllGroups用来按注册顺序返回所有组版本。
+func (s *Scheme) PrioritizedVersionsAllGroups() []scheme.GroupVersion {
+	return append([]scheme.GroupVersion(nil), s.observedVersions...)
+}
+
+// ObjectKinds returns all the kinds obj is registered as. An error is returned
+// if the type of obj is not registered.
+// ObjectKinds用来返回对象注册的所有Kind。
+func (s *Scheme) ObjectKinds(obj Object) ([]scheme.GroupVersionKind, error) {
+	t := reflect.TypeOf(obj)
+	if t == nil || t.Kind() != reflect.Ptr {
+		return nil, fmt.Errorf("%v is not a pointer", t)
+	}
+
+	gvks, ok := s.typeToGVK[t.Elem()]
+	if !ok {
+		return nil, NewNotRegisteredErrForType(t.Elem())
+	}
+
+	return append([]scheme.GroupVersionKind(nil), gvks...), nil
+}
+
+// Recognizes returns true if the scheme can create an object of kind gvk.
+// Recognizes用来判断Kind是否已注册。
+func (s *Scheme) Recognizes(gvk scheme.GroupVersionKind) bool {
+	_, ok := s.gvkToType[gvk]
+
+	return ok
+}
+
+// New returns a new zero object of kind gvk, with its kind set.
+// New用来创建指定Kind的对象。
+func (s *Scheme) New(gvk scheme.GroupVersionKind) (Object, error) {
+	t, ok := s.gvkToType[gvk]
+	if !ok {
+		return nil, NewNotRegisteredErrForKind(gvk)
+	}
+
+	obj, _ := reflect.New(t).Interface().(Object)
+	obj.GetObjectKind().SetGroupVersionKind(gvk)
+
+	return obj, nil
+}
+
+// SetGroupVersionKind sets the kind of obj from the scheme. When obj is
+// registered as several kinds, the kind already set on obj is kept if it is one
+// of them, and the first registered kind is used otherwise.
+// SetGroupVersionKind用来根据Scheme设置对象的类型信息。
+func (s *Scheme) SetGroupVersionKind(obj Object) error {
+	gvk, err := s.objectKind(obj)
+	if err != nil {
+		return err
+	}
+	obj.GetObjectKind().SetGroupVersionKind(gvk)
+
+	return nil
+}
+
+// Encoder returns an Encoder which sets the kind of the registered objects
+// before encoding them with enc. The encoded objects are not modified.
+// Encoder用来返回在编码前设置对象类型信息的Encoder。
+func (s *Scheme) Encoder(enc Encoder) Encoder {
+	return &kindEncoder{scheme: s, encoder: enc}
+}
+
+func (s *Scheme) addObservedVersion(gv scheme.GroupVersion) {
+	for _, observed := range s.observedVersions {
+		if observed == gv {
+			return
+		}
+	}
+	s.observedVersions = append(s.observedVersions, gv)
+}
+
+// kindEncoder sets the kind of objects from a scheme before encoding them.
+type kindEncoder struct {
+	scheme  *Scheme
+	encoder Encoder
+}
+
+var _ Encoder = &kindEncoder{}
+
+func (e *kindEncoder) Encode(v interface{}) ([]byte, error) {
+	obj, ok := v.(Object)
+	if !ok {
+		return e.encoder.Encode(v)
+	}
+
+	obj = obj.DeepCopyObject()
+	if err := e.scheme.SetGroupVersionKind(obj); err != nil {
+		return nil, err
+	}
+
+	return e.encoder.Encode(obj)
+}
+
+// notRegisteredErr is returned when a kind or a type is not registered in a scheme.
+type notRegisteredErr struct {
+	gvk scheme.GroupVersionKind
+	t   reflect.Type
+}
+
+// NewNotRegisteredErrForKind returns the error reported when the kind gvk is not registered.
+// NewNotRegisteredErrForKind用来创建Kind未注册的错误。
+func NewNotRegisteredErrForKind(gvk scheme.GroupVersionKind) error {
+	return &notRegisteredErr{gvk: gvk}
+}
+
+// NewNotRegisteredErrForType returns the error reported when the type t is not registered.
+// NewNotRegisteredErrForType用来创建类型未注册的错误。
+func NewNotRegisteredErrForType(t reflect.Type) error {
+	return &notRegisteredErr{t: t}
+}
+
+func (e *notRegisteredErr) Error() string {
+	if e.t != nil {
+		return fmt.Sprintf("no kind is registered for the type %v", e.t)
+	}
+	if len(e.gvk.Kind) == 0 {
+		return fmt.Sprintf("no version %q has been registered", e.gvk.GroupVersion())
+	}
+
+	return fmt.Sprintf("no kind %q is registered for version %q", e.gvk.Kind, e.gvk.GroupVersion())
+}
+
+// IsNotRegisteredError returns true if the error indicates the provided
+// object or input data is not registered.
+// IsNotRegisteredError用来判断错误是否为类型未注册。
+func IsNotRegisteredError(err error) bool {
+	var e *notRegisteredErr
+
+	return errors.As(err, &e)
+}
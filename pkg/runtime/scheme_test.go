@@ -0,0 +1,171 @@
+// Copyright 2020 Lingfei Kong <colin404@foxmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package runtime
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/HappyLadySauce/component-base/pkg/json"
+	"github.com/HappyLadySauce/component-base/pkg/scheme"
+)
+
+type testTypeMeta struct {
+	APIVersion string `json:"apiVersion,omitempty"`
+	Kind       string `json:"kind,omitempty"`
+}
+
+func (obj *testTypeMeta) GetObjectKind() scheme.ObjectKind { return obj }
+
+func (obj *testTypeMeta) SetGroupVersionKind(gvk scheme.GroupVersionKind) {
+	obj.APIVersion, obj.Kind = gvk.ToAPIVersionAndKind()
+}
+
+func (obj *testTypeMeta) GroupVersionKind() scheme.GroupVersionKind {
+	return scheme.FromAPIVersionAndKind(obj.APIVersion, obj.Kind)
+}
+
+type Secret struct {
+	testTypeMeta `json:",inline"`
+
+	Name string `json:"name,omitempty"`
+}
+
+func (obj *Secret) DeepCopyObject() Object {
+	out := *obj
+
+	return &out
+}
+
+type Policy struct {
+	testTypeMeta `json:",inline"`
+}
+
+func (obj *Policy) DeepCopyObject() Object {
+	out := *obj
+
+	return &out
+}
+
+var (
+	testGroupVersion   = scheme.GroupVersion{Group: "iam.example.com", Version: "v1"}
+	testGroupVersionV2 = scheme.GroupVersion{Group: "iam.example.com", Version: "v2"}
+)
+
+func newTestScheme() *Scheme {
+	s := NewScheme()
+	s.AddKnownTypes(testGroupVersion, &Secret{}, &Policy{})
+	s.AddKnownTypes(testGroupVersionV2, &Secret{})
+
+	return s
+}
+
+func TestSchemeKnownTypes(t *testing.T) {
+	s := newTestScheme()
+
+	if len(s.AllKnownTypes()) != 3 {
+		t.Errorf("expected 3 known types, got %v", s.AllKnownTypes())
+	}
+	expected := map[string]reflect.Type{"Secret": reflect.TypeOf(Secret{})}
+	if types := s.KnownTypes(testGroupVersionV2); !reflect.DeepEqual(types, expected) {
+		t.Errorf("expected %v, got %v", expected, types)
+	}
+	if versions := s.PrioritizedVersionsAllGroups(); !reflect.DeepEqual(
+		versions,
+		[]scheme.GroupVersion{testGroupVersion, testGroupVersionV2},
+	) {
+		t.Errorf("unexpected versions %v", versions)
+	}
+
+	if !s.Recognizes(testGroupVersion.WithKind("Policy")) {
+		t.Errorf("expected Policy to be recognized")
+	}
+	if s.Recognizes(testGroupVersionV2.WithKind("Policy")) {
+		t.Errorf("expected v2 Policy not to be recognized")
+	}
+
+	gvks, err := s.ObjectKinds(&Secret{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectedKinds := []scheme.GroupVersionKind{testGroupVersion.WithKind("Secret"), testGroupVersionV2.WithKind("Secret")}
+	if !reflect.DeepEqual(gvks, expectedKinds) {
+		t.Errorf("expected %v, got %v", expectedKinds, gvks)
+	}
+	if _, err := NewScheme().ObjectKinds(&Policy{}); !IsNotRegisteredError(err) {
+		t.Errorf("expected a not registered error, got %v", err)
+	}
+}
+
+func TestSchemeNew(t *testing.T) {
+	s := newTestScheme()
+
+	obj, err := s.New(testGroupVersionV2.WithKind("Secret"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	secret, ok := obj.(*Secret)
+	if !ok || secret.APIVersion != "iam.example.com/v2" || secret.Kind != "Secret" {
+		t.Errorf("unexpected object %#v", obj)
+	}
+
+	_, err = s.New(testGroupVersionV2.WithKind("Policy"))
+	if !IsNotRegisteredError(err) || err.Error() != `no kind "Policy" is registered for version "iam.example.com/v2"` {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestSchemeRegistration(t *testing.T) {
+	s := NewScheme()
+	s.AddKnownTypes(testGroupVersion, &Secret{})
+	// Registering the same type again is a no-op.
+	s.AddKnownTypes(testGroupVersion, &Secret{})
+
+	for name, register := range map[string]func(){
+		"double registration": func() { s.AddKnownTypeWithName(testGroupVersion.WithKind("Secret"), &Policy{}) },
+		"missing version":     func() { s.AddKnownTypes(scheme.GroupVersion{Group: "iam.example.com"}, &Policy{}) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s: expected a panic", name)
+				}
+			}()
+			register()
+		}()
+	}
+}
+
+func TestSchemeEncoder(t *testing.T) {
+	s := newTestScheme()
+	enc := s.Encoder(NewJSONSerializer(false))
+
+	secret := &Secret{Name: "foo"}
+	data, err := enc.Encode(secret)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(data) != `{"apiVersion":"iam.example.com/v1","kind":"Secret","name":"foo"}` {
+		t.Errorf("unexpected encoding %s", data)
+	}
+	if secret.Kind != "" {
+		t.Errorf("expected the encoded object not to be modified")
+	}
+
+	// A registered kind already set on the object is kept.
+	secret.SetGroupVersionKind(testGroupVersionV2.WithKind("Secret"))
+	data, _ = enc.Encode(secret)
+	var out Secret
+	if err := json.Unmarshal(data, &out); err != nil || out.APIVersion != "iam.example.com/v2" {
+		t.Errorf("unexpected encoding %s", data)
+	}
+
+	if _, err := NewScheme().Encoder(NewJSONSerializer(false)).Encode(&Policy{}); err == nil {
+		t.Errorf("expected an error for an unregistered object")
+	}
+	if data, _ := enc.Encode(map[string]string{"a": "b"}); string(data) != `{"a":"b"}` {
+		t.Errorf("unexpected encoding %s", data)
+	}
+}
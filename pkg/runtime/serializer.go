@@ -0,0 +1,169 @@
+// Copyright 2020 Lingfei Kong <colin404@foxmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package runtime
+
+import (
+	"reflect"
+
+	"github.com/ghodss/yaml"
+	"github.com/ugorji/go/codec"
+
+	"github.com/HappyLadySauce/component-base/pkg/json"
+	"github.com/HappyLadySauce/component-base/pkg/validation/field"
+)
+
+// The media types supported by the serializers of this package.
+const (
+	ContentTypeJSON = "application/json"
+	ContentTypeYAML = "application/yaml"
+	ContentTypeCBOR = "application/cbor"
+)
+
+// Serializer is the core interface for transforming objects into a serialized
+// format and back.
+// Serializer是用来序列化和反序列化对象的接口。
+type Serializer interface {
+	Encoder
+	Decoder
+}
+
+// jsonSerializer encodes and decodes JSON, optionally indented.
+type jsonSerializer struct {
+	pretty bool
+}
+
+// NewJSONSerializer returns a JSON Serializer. A pretty serializer indents its output.
+// NewJSONSerializer用来创建JSON序列化器。
+func NewJSONSerializer(pretty bool) Serializer {
+	return &jsonSerializer{pretty: pretty}
+}
+
+func (s *jsonSerializer) Encode(v interface{}) ([]byte, error) {
+	if s.pretty {
+		return json.MarshalIndent(v, "", "  ")
+	}
+
+	return json.Marshal(v)
+}
+
+func (s *jsonSerializer) Decode(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+// WarningHandler receives the issues found by a strict decoding in json.StrictModeWarn.
+type WarningHandler func(errs field.ErrorList)
+
+// lenientDecoder is implemented by the strict decoders, which can decode data
+// leniently, such as when only its type information is needed.
+type lenientDecoder interface {
+	lenient() Decoder
+}
+
+// strictJSONSerializer decodes JSON strictly, reporting unknown fields, duplicate
+// keys and case-mismatched keys.
+type strictJSONSerializer struct {
+	jsonSerializer
+	mode json.StrictMode
+	warn WarningHandler
+}
+
+// NewStrictJSONSerializer returns a JSON Serializer which decodes strictly. In
+// json.StrictModeError decoding fails with a json.StrictDecodingError when issues
+// are found; in json.StrictModeWarn the issues are passed to warn, which may be nil.
+// NewStrictJSONSerializer用来创建严格解码的JSON序列化器。
+func NewStrictJSONSerializer(mode json.StrictMode, warn WarningHandler) Serializer {
+	return &strictJSONSerializer{mode: mode, warn: warn}
+}
+
+func (s *strictJSONSerializer) Decode(data []byte, v interface{}) error {
+	errs, err := json.UnmarshalStrict(data, v, s.mode)
+	if err != nil {
+		return err
+	}
+	if len(errs) > 0 && s.warn != nil {
+		s.warn(errs)
+	}
+
+	return nil
+}
+
+func (s *strictJSONSerializer) lenient() Decoder {
+	return &s.jsonSerializer
+}
+
+// yamlSerializer encodes and decodes YAML through the JSON field names of the values.
+type yamlSerializer struct{}
+
+// NewYAMLSerializer returns a YAML Serializer, which uses the json struct tags.
+// NewYAMLSerializer用来创建YAML序列化器。
+func NewYAMLSerializer() Serializer {
+	return &yamlSerializer{}
+}
+
+func (s *yamlSerializer) Encode(v interface{}) ([]byte, error) {
+	return yaml.Marshal(v)
+}
+
+func (s *yamlSerializer) Decode(data []byte, v interface{}) error {
+	return yaml.Unmarshal(data, v)
+}
+
+// strictYAMLSerializer decodes YAML strictly, through its JSON representation.
+type strictYAMLSerializer struct {
+	yamlSerializer
+	strict strictJSONSerializer
+}
+
+// NewStrictYAMLSerializer returns a YAML Serializer which decodes strictly, like
+// the serializers returned by NewStrictJSONSerializer.
+// NewStrictYAMLSerializer用来创建严格解码的YAML序列化器。
+func NewStrictYAMLSerializer(mode json.StrictMode, warn WarningHandler) Serializer {
+	return &strictYAMLSerializer{strict: strictJSONSerializer{mode: mode, warn: warn}}
+}
+
+func (s *strictYAMLSerializer) Decode(data []byte, v interface{}) error {
+	js, err := yaml.YAMLToJSON(data)
+	if err != nil {
+		return err
+	}
+
+	return s.strict.Decode(js, v)
+}
+
+func (s *strictYAMLSerializer) lenient() Decoder {
+	return &s.yamlSerializer
+}
+
+// cborHandle is shared by the CBOR serializers. Maps are decoded into
+// map[string]interface{}, as with JSON.
+var cborHandle = func() *codec.CborHandle {
+	h := &codec.CborHandle{TimeRFC3339: true}
+	h.MapType = reflect.TypeOf(map[string]interface{}(nil))
+
+	return h
+}()
+
+// cborSerializer encodes and decodes CBOR (RFC 7049).
+type cborSerializer struct{}
+
+// NewCBORSerializer returns a CBOR Serializer, a compact binary format which
+// uses the json struct tags.
+// NewCBORSerializer用来创建CBOR序列化器。
+func NewCBORSerializer() Serializer {
+	return &cborSerializer{}
+}
+
+func (s *cborSerializer) Encode(v interface{}) ([]byte, error) {
+	var out []byte
+	if err := codec.NewEncoderBytes(&out, cborHandle).Encode(v); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+func (s *cborSerializer) Decode(data []byte, v interface{}) error {
+	return codec.NewDecoderBytes(data, cborHandle).Decode(v)
+}
@@ -0,0 +1,90 @@
+// Copyright 2020 Lingfei Kong <colin404@foxmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package runtime
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/HappyLadySauce/component-base/pkg/scheme"
+)
+
+// ObjectDecoder decodes objects whose kind is declared by the serialized data.
+// Codec implements it.
+type ObjectDecoder interface {
+	// DecodeObject decodes data into a new object of the kind it declares, and
+	// returns the kind along with the object.
+	DecodeObject(data []byte) (Object, *scheme.GroupVersionKind, error)
+}
+
+// StreamDecoder decodes the objects of a stream one at a time.
+// StreamDecoder是用来从流中逐个解码对象的解码器。
+type StreamDecoder struct {
+	reader  FrameReader
+	decoder Decoder
+}
+
+// NewStreamDecoder returns a StreamDecoder reading the frames of r with framer
+// and decoding them with dec. Frames larger than maxFrameSize bytes are refused,
+// DefaultMaxFrameSize is used when maxFrameSize is not positive.
+// NewStreamDecoder用来创建StreamDecoder。
+func NewStreamDecoder(r io.Reader, framer Framer, dec Decoder, maxFrameSize int) *StreamDecoder {
+	return &StreamDecoder{reader: framer.NewFrameReader(r, maxFrameSize), decoder: dec}
+}
+
+// Decode decodes the next object of the stream into v. It returns io.EOF when
+// the stream ends.
+// Decode用来将流中的下一个对象解码到v中。
+func (d *StreamDecoder) Decode(v interface{}) error {
+	frame, err := d.reader.ReadFrame()
+	if err != nil {
+		return err
+	}
+
+	return d.decoder.Decode(frame, v)
+}
+
+// DecodeObject decodes the next object of the stream into a new object of the
+// kind it declares. The decoder of the stream must be an ObjectDecoder. It
+// returns io.EOF when the stream ends.
+// DecodeObject用来按照声明的类型解码流中的下一个对象。
+func (d *StreamDecoder) DecodeObject() (Object, *scheme.GroupVersionKind, error) {
+	dec, ok := d.decoder.(ObjectDecoder)
+	if !ok {
+		return nil, nil, fmt.Errorf("decoder %T can not decode objects of unknown kinds", d.decoder)
+	}
+
+	frame, err := d.reader.ReadFrame()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return dec.DecodeObject(frame)
+}
+
+// StreamEncoder encodes objects to a stream one at a time.
+// StreamEncoder是用来将对象逐个编码到流中的编码器。
+type StreamEncoder struct {
+	writer  FrameWriter
+	encoder Encoder
+}
+
+// NewStreamEncoder returns a StreamEncoder encoding objects with enc and writing
+// them to w with framer.
+// NewStreamEncoder用来创建StreamEncoder。
+func NewStreamEncoder(w io.Writer, framer Framer, enc Encoder) *StreamEncoder {
+	return &StreamEncoder{writer: framer.NewFrameWriter(w), encoder: enc}
+}
+
+// Encode encodes v and writes it to the stream as a single frame.
+// Encode用来将v编码为一帧并写入流中。
+func (e *StreamEncoder) Encode(v interface{}) error {
+	data, err := e.encoder.Encode(v)
+	if err != nil {
+		return err
+	}
+
+	return e.writer.WriteFrame(data)
+}
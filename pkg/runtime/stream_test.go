@@ -0,0 +1,139 @@
+// Copyright 2020 Lingfei Kong <colin404@foxmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package runtime
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func readFrames(t *testing.T, r FrameReader) ([]string, error) {
+	t.Helper()
+
+	var frames []string
+	for {
+		frame, err := r.ReadFrame()
+		if errors.Is(err, io.EOF) {
+			return frames, nil
+		}
+		if err != nil {
+			return frames, err
+		}
+		frames = append(frames, string(frame))
+	}
+}
+
+func TestFrameReaders(t *testing.T) {
+	testCases := []struct {
+		name     string
+		framer   Framer
+		input    string
+		expected []string
+	}{
+		{"newline", NewlineFramer, "{}\n\n{\"b\":2}\r\n  \n{\"c\":3}", []string{`{}`, `{"b":2}`, `{"c":3}`}},
+		{"yaml", YAMLFramer, "a: 1\n---\n---  \nb: 2\nc: 3\n---\n", []string{"a: 1\n", "b: 2\nc: 3\n"}},
+		{"yaml leading separator", YAMLFramer, "---\na: 1\n--- \n\nb: 2", []string{"a: 1\n", "\nb: 2"}},
+		{
+			"length delimited",
+			LengthDelimitedFramer,
+			"\x00\x00\x00\x02ab\x00\x00\x00\x00\x00\x00\x00\x01c",
+			[]string{"ab", "", "c"},
+		},
+	}
+
+	for _, tc := range testCases {
+		frames, err := readFrames(t, tc.framer.NewFrameReader(strings.NewReader(tc.input), 0))
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tc.name, err)
+
+			continue
+		}
+		if !reflect.DeepEqual(frames, tc.expected) {
+			t.Errorf("%s: expected %q, got %q", tc.name, tc.expected, frames)
+		}
+	}
+}
+
+func TestFrameReadersLimits(t *testing.T) {
+	testCases := []struct {
+		name     string
+		framer   Framer
+		input    string
+		expected error
+	}{
+		{"newline", NewlineFramer, "12345\n123456\n", ErrFrameTooLarge},
+		{"long newline", NewlineFramer, "1234\n" + strings.Repeat("x", 10000), ErrFrameTooLarge},
+		{"yaml", YAMLFramer, "a: 1\n---\nb: 2\nc: 3\n", ErrFrameTooLarge},
+		{"length delimited", LengthDelimitedFramer, "\x00\x00\x00\x02ab\xff\xff\xff\xff", ErrFrameTooLarge},
+		{"truncated length delimited", LengthDelimitedFramer, "\x00\x00\x00\x01a\x00\x00\x00\x04ab", io.ErrUnexpectedEOF},
+	}
+
+	for _, tc := range testCases {
+		frames, err := readFrames(t, tc.framer.NewFrameReader(strings.NewReader(tc.input), 5))
+		if !errors.Is(err, tc.expected) {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.expected, err)
+		}
+		if len(frames) != 1 {
+			t.Errorf("%s: expected the first frame to be read, got %q", tc.name, frames)
+		}
+	}
+}
+
+func TestNewlineFrameWriter(t *testing.T) {
+	// The frame has spare capacity, appending the newline to it would overwrite
+	// the data following it in the caller's buffer.
+	data := []byte("{}{}")
+	var buf bytes.Buffer
+	if err := NewlineFramer.NewFrameWriter(&buf).WriteFrame(data[:2]); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if buf.String() != "{}\n" || string(data) != "{}{}" {
+		t.Errorf("unexpected output %q and buffer %q", buf.String(), data)
+	}
+}
+
+func TestStream(t *testing.T) {
+	codecs := NewCodecFactory(newConversionScheme(t))
+
+	for _, info := range codecs.SupportedMediaTypes() {
+		stream := info.StreamSerializer
+		var buf bytes.Buffer
+		enc := NewStreamEncoder(&buf, stream.Framer, codecs.EncoderForVersion(stream.Serializer, testGroupVersionV2))
+		for _, name := range []string{"foo", "bar"} {
+			if err := enc.Encode(&Secret{Name: name}); err != nil {
+				t.Fatalf("%s: unexpected error: %v", info.MediaType, err)
+			}
+		}
+
+		dec := NewStreamDecoder(&buf, stream.Framer, codecs.DecoderToVersion(stream.Serializer, testGroupVersion), 0)
+		obj, gvk, err := dec.DecodeObject()
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", info.MediaType, err)
+		}
+		if secret, ok := obj.(*Secret); !ok || secret.Name != "foo" || gvk.Version != "v2" {
+			t.Errorf("%s: unexpected object %#v from %v", info.MediaType, obj, gvk)
+		}
+		secret := &Secret{}
+		if err := dec.Decode(secret); err != nil || secret.Name != "bar" {
+			t.Errorf("%s: unexpected object %#v, %v", info.MediaType, secret, err)
+		}
+		if _, _, err := dec.DecodeObject(); !errors.Is(err, io.EOF) {
+			t.Errorf("%s: expected io.EOF, got %v", info.MediaType, err)
+		}
+	}
+
+	dec := NewStreamDecoder(strings.NewReader("{}\n"), NewlineFramer, NewJSONSerializer(false), 0)
+	if _, _, err := dec.DecodeObject(); err == nil {
+		t.Errorf("expected an error for a decoder which is not an ObjectDecoder")
+	}
+	enc := NewStreamEncoder(io.Discard, NewlineFramer, NewJSONSerializer(true))
+	if err := enc.Encode(&Secret{Name: "foo"}); err == nil {
+		t.Errorf("expected an error for a frame holding newlines")
+	}
+}
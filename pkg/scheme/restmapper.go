@@ -0,0 +1,498 @@
+// Copyright 2020 Lingfei Kong <colin404@foxmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package scheme
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// RESTMapping contains the information needed to deal with objects of a specific
+// resource and kind in a RESTful manner.
+// RESTMapping是用来描述资源和Kind之间映射关系的结构体。
+type RESTMapping struct {
+	// Resource is the GroupVersionResource used for REST calls.
+	Resource GroupVersionResource
+	// GroupVersionKind is the kind served by the resource.
+	GroupVersionKind GroupVersionKind
+}
+
+// RESTMapper allows clients to map resources to kinds, and back. Partial
+// resources, which leave their group or version empty, match any group or
+// version. Resources are matched by their plural or singular name, or by one of
+// their short names.
+// RESTMapper是用来在资源和Kind之间进行映射的接口。
+type RESTMapper interface {
+	// KindFor takes a partial resource and returns the single match. It returns an
+	// error if there are multiple matches.
+	KindFor(resource GroupVersionResource) (GroupVersionKind, error)
+	// KindsFor takes a partial resource and returns the list of potential kinds in priority order.
+	KindsFor(resource GroupVersionResource) ([]GroupVersionKind, error)
+	// ResourceFor takes a partial resource and returns the single match. It returns an
+	// error if there are multiple matches.
+	ResourceFor(input GroupVersionResource) (GroupVersionResource, error)
+	// ResourcesFor takes a partial resource and returns the list of potential resources in priority order.
+	ResourcesFor(input GroupVersionResource) ([]GroupVersionResource, error)
+	// RESTMapping identifies a preferred resource mapping for the provided group kind.
+	// The versions are tried in order, the versions of the mapper are used when none is given.
+	RESTMapping(gk GroupKind, versions ...string) (*RESTMapping, error)
+	// ResourceSingularizer returns the singular name of a plural resource.
+	ResourceSingularizer(resource string) (singular string, err error)
+}
+
+// CategoryExpander maps a category, such as "all", to a list of resources.
+// CategoryExpander是用来将分类展开为资源列表的接口。
+type CategoryExpander interface {
+	// Expand returns the resources of the category, in the order they were added.
+	Expand(category string) ([]GroupResource, bool)
+}
+
+// DefaultRESTMapper is a RESTMapper built from a static list of kinds.
+// DefaultRESTMapper是根据Kind列表构建的RESTMapper。
+type DefaultRESTMapper struct {
+	// defaultGroupVersions are the preferred group versions, most preferred first.
+	defaultGroupVersions []GroupVersion
+
+	// resources are the plural resources, in the order they were added.
+	resources []GroupVersionResource
+
+	resourceToKind       map[GroupVersionResource]GroupVersionKind
+	kindToPluralResource map[GroupVersionKind]GroupVersionResource
+	pluralToSingular     map[GroupVersionResource]GroupVersionResource
+
+	shortNames map[string][]GroupResource
+	categories map[string][]GroupResource
+}
+
+var (
+	_ RESTMapper       = &DefaultRESTMapper{}
+	_ CategoryExpander = &DefaultRESTMapper{}
+)
+
+// NewDefaultRESTMapper creates a DefaultRESTMapper. Matches in defaultGroupVersions
+// are preferred in the given order, over the matches in other group versions.
+// NewDefaultRESTMapper用来创建DefaultRESTMapper。
+func NewDefaultRESTMapper(defaultGroupVersions []GroupVersion) *DefaultRESTMapper {
+	return &DefaultRESTMapper{
+		defaultGroupVersions: defaultGroupVersions,
+		resourceToKind:       map[GroupVersionResource]GroupVersionKind{},
+		kindToPluralResource: map[GroupVersionKind]GroupVersionResource{},
+		pluralToSingular:     map[GroupVersionResource]GroupVersionResource{},
+		shortNames:           map[string][]GroupResource{},
+		categories:           map[string][]GroupResource{},
+	}
+}
+
+// Add adds kind, with the plural and singular resource names guessed from its name.
+// Add用来添加Kind，资源名称根据Kind的名称推测。
+func (m *DefaultRESTMapper) Add(kind GroupVersionKind) {
+	plural, singular := UnsafeGuessKindToResource(kind)
+	m.AddSpecific(kind, plural, singular)
+}
+
+// AddSpecific adds kind, served by the plural and singular resources.
+// AddSpecific用来添加Kind及其复数和单数形式的资源。
+func (m *DefaultRESTMapper) AddSpecific(kind GroupVersionKind, plural, singular GroupVersionResource) {
+	if _, ok := m.resourceToKind[plural]; !ok {
+		m.resources = append(m.resources, plural)
+	}
+
+	m.pluralToSingular[plural] = singular
+	m.resourceToKind[singular] = kind
+	m.resourceToKind[plural] = kind
+	m.kindToPluralResource[kind] = plural
+}
+
+// AddShortNames adds short names, such as "sec", for the resource.
+// AddShortNames用来为资源添加简称。
+func (m *DefaultRESTMapper) AddShortNames(resource GroupResource, shortNames ...string) {
+	for _, name := range shortNames {
+		name = strings.ToLower(name)
+		m.shortNames[name] = append(m.shortNames[name], resource)
+	}
+}
+
+// AddCategories adds the resource to the categories.
+// AddCategories用来将资源添加到分类中。
+func (m *DefaultRESTMapper) AddCategories(resource GroupResource, categories ...string) {
+	for _, category := range categories {
+		category = strings.ToLower(category)
+		m.categories[category] = append(m.categories[category], resource)
+	}
+}
+
+// Expand implements the CategoryExpander interface.
+func (m *DefaultRESTMapper) Expand(category string) ([]GroupResource, bool) {
+	resources, ok := m.categories[strings.ToLower(category)]
+
+	return append([]GroupResource(nil), resources...), ok
+}
+
+// ResourceSingularizer implements the RESTMapper interface.
+func (m *DefaultRESTMapper) ResourceSingularizer(resource string) (string, error) {
+	resource = strings.ToLower(resource)
+
+	// The plurals are sorted, so the same singular is returned when several groups
+	// or versions hold the resource.
+	plurals := make([]GroupVersionResource, 0, len(m.pluralToSingular))
+	for plural := range m.pluralToSingular {
+		plurals = append(plurals, plural)
+	}
+	sort.Slice(plurals, func(i, j int) bool {
+		a, b := plurals[i], plurals[j]
+		if a.Group != b.Group {
+			return a.Group < b.Group
+		}
+		if a.Version != b.Version {
+			return compareVersionPriority(a.Version, b.Version) > 0
+		}
+
+		return a.Resource < b.Resource
+	})
+	for _, plural := range plurals {
+		if plural.Resource == resource {
+			return m.pluralToSingular[plural].Resource, nil
+		}
+	}
+
+	return resource, &NoResourceMatchError{PartialResource: GroupVersionResource{Resource: resource}}
+}
+
+// ResourcesFor implements the RESTMapper interface.
+func (m *DefaultRESTMapper) ResourcesFor(input GroupVersionResource) ([]GroupVersionResource, error) {
+	resource := coerceResourceForMatching(input)
+
+	var matches []GroupVersionResource
+	for _, plural := range m.resources {
+		if matchesResource(resource, plural) || matchesResource(resource, m.pluralToSingular[plural]) {
+			matches = append(matches, plural)
+		}
+	}
+	// Short names are only looked at when no name matches.
+	if len(matches) == 0 {
+		for _, gr := range m.shortNames[resource.Resource] {
+			if len(resource.Group) > 0 && resource.Group != gr.Group {
+				continue
+			}
+			for _, plural := range m.resources {
+				if plural.GroupResource() == gr && (len(resource.Version) == 0 || resource.Version == plural.Version) {
+					matches = append(matches, plural)
+				}
+			}
+		}
+	}
+	if len(matches) == 0 {
+		return nil, &NoResourceMatchError{PartialResource: resource}
+	}
+
+	m.sortByPriority(matches)
+
+	return matches, nil
+}
+
+// ResourceFor implements the RESTMapper interface. When the matches only differ
+// by version, the preferred version is returned.
+func (m *DefaultRESTMapper) ResourceFor(input GroupVersionResource) (GroupVersionResource, error) {
+	resources, err := m.ResourcesFor(input)
+	if err != nil {
+		return GroupVersionResource{}, err
+	}
+
+	for _, resource := range resources[1:] {
+		if resource.GroupResource() != resources[0].GroupResource() {
+			return GroupVersionResource{}, &AmbiguousResourceError{PartialResource: input, MatchingResources: resources}
+		}
+	}
+
+	return resources[0], nil
+}
+
+// KindsFor implements the RESTMapper interface.
+func (m *DefaultRESTMapper) KindsFor(input GroupVersionResource) ([]GroupVersionKind, error) {
+	resources, err := m.ResourcesFor(input)
+	if err != nil {
+		return nil, err
+	}
+
+	kinds := make([]GroupVersionKind, 0, len(resources))
+	for _, resource := range resources {
+		kinds = append(kinds, m.resourceToKind[resource])
+	}
+
+	return kinds, nil
+}
+
+// KindFor implements the RESTMapper interface. When the matches only differ by
+// version, the kind of the preferred version is returned.
+func (m *DefaultRESTMapper) KindFor(input GroupVersionResource) (GroupVersionKind, error) {
+	resource, err := m.ResourceFor(input)
+	if err != nil {
+		var ambiguous *AmbiguousResourceError
+		if errors.As(err, &ambiguous) {
+			for _, r := range ambiguous.MatchingResources {
+				ambiguous.MatchingKinds = append(ambiguous.MatchingKinds, m.resourceToKind[r])
+			}
+		}
+
+		return GroupVersionKind{}, err
+	}
+
+	return m.resourceToKind[resource], nil
+}
+
+// RESTMapping implements the RESTMapper interface. Without versions, the
+// versions of the group in the default group versions are tried first, then any
+// registered version of the kind with the highest priority, see compareVersionPriority.
+func (m *DefaultRESTMapper) RESTMapping(gk GroupKind, versions ...string) (*RESTMapping, error) {
+	explicit := len(versions) > 0
+	if !explicit {
+		for _, gv := range m.defaultGroupVersions {
+			if gv.Group == gk.Group {
+				versions = append(versions, gv.Version)
+			}
+		}
+	}
+
+	for _, version := range versions {
+		if mapping, ok := m.mappingFor(gk.WithVersion(version)); ok {
+			return mapping, nil
+		}
+	}
+
+	if !explicit {
+		var kinds []GroupVersionKind
+		for kind := range m.kindToPluralResource {
+			if kind.GroupKind() == gk {
+				kinds = append(kinds, kind)
+			}
+		}
+		if len(kinds) > 0 {
+			sort.Slice(kinds, func(i, j int) bool {
+				return compareVersionPriority(kinds[i].Version, kinds[j].Version) > 0
+			})
+			mapping, _ := m.mappingFor(kinds[0])
+
+			return mapping, nil
+		}
+	}
+
+	return nil, &NoKindMatchError{GroupKind: gk, SearchedVersions: versions}
+}
+
+func (m *DefaultRESTMapper) mappingFor(kind GroupVersionKind) (*RESTMapping, bool) {
+	resource, ok := m.kindToPluralResource[kind]
+	if !ok {
+		return nil, false
+	}
+
+	return &RESTMapping{Resource: resource, GroupVersionKind: kind}, true
+}
+
+// sortByPriority sorts resources by the priority of their group version. Resources
+// outside the default group versions come last, in the order they were added.
+func (m *DefaultRESTMapper) sortByPriority(resources []GroupVersionResource) {
+	priority := func(resource GroupVersionResource) int {
+		for i, gv := range m.defaultGroupVersions {
+			if gv == resource.GroupVersion() {
+				return i
+			}
+		}
+
+		return len(m.defaultGroupVersions)
+	}
+
+	sort.SliceStable(resources, func(i, j int) bool {
+		return priority(resources[i]) < priority(resources[j])
+	})
+}
+
+// versionStability is the stability level of a Kubernetes-like version.
+type versionStability int
+
+const (
+	// nonKubeVersion is a version not looking like v1, v2beta1 or v1alpha2.
+	nonKubeVersion versionStability = iota
+	alphaVersion
+	betaVersion
+	gaVersion
+)
+
+// parseKubeVersion returns the stability, major and minor numbers of a Kubernetes-like
+// version, such as 1, 2 and 1 for v2beta1.
+func parseKubeVersion(v string) (versionStability, int, int) {
+	if !strings.HasPrefix(v, "v") {
+		return nonKubeVersion, 0, 0
+	}
+	v = v[1:]
+	end := strings.IndexFunc(v, func(r rune) bool { return r < '0' || r > '9' })
+	if end < 0 {
+		end = len(v)
+	}
+	major, err := strconv.Atoi(v[:end])
+	if err != nil || major == 0 || v[0] == '0' {
+		return nonKubeVersion, 0, 0
+	}
+	if end == len(v) {
+		return gaVersion, major, 0
+	}
+
+	stability := betaVersion
+	rest := strings.TrimPrefix(v[end:], "beta")
+	if rest == v[end:] {
+		stability = alphaVersion
+		rest = strings.TrimPrefix(v[end:], "alpha")
+	}
+	minor, err := strconv.Atoi(rest)
+	if rest == v[end:] || err != nil || minor == 0 || rest[0] == '0' {
+		return nonKubeVersion, 0, 0
+	}
+
+	return stability, major, minor
+}
+
+// compareVersionPriority compares the priority of two versions the way Kubernetes does.
+// It returns a positive number if a comes first: GA versions come before beta versions,
+// which come before alpha versions, with higher numbers first, so v2 > v1 > v1beta2 >
+// v1beta1 > v1alpha1. Versions not looking like these come last, in lexical order.
+func compareVersionPriority(a, b string) int {
+	sa, majorA, minorA := parseKubeVersion(a)
+	sb, majorB, minorB := parseKubeVersion(b)
+
+	switch {
+	case sa != sb:
+		return int(sa) - int(sb)
+	case sa == nonKubeVersion:
+		return strings.Compare(b, a)
+	case majorA != majorB:
+		return majorA - majorB
+	}
+
+	return minorA - minorB
+}
+
+// MapResourceArg resolves a command line resource argument, such as "sec",
+// "secrets.iam.example.com" or "secrets.v1.iam.example.com", with mapper.
+// MapResourceArg用来解析命令行中的资源参数。
+func MapResourceArg(mapper RESTMapper, arg string) (GroupVersionResource, error) {
+	fullySpecified, groupResource := ParseResourceArg(strings.ToLower(arg))
+	if fullySpecified != nil {
+		if resource, err := mapper.ResourceFor(*fullySpecified); err == nil {
+			return resource, nil
+		}
+	}
+
+	return mapper.ResourceFor(groupResource.WithVersion(""))
+}
+
+// UnsafeGuessKindToResource converts Kind to a resource name. Broken. This
+// method only "sort of" works when used outside of this package.
+// UnsafeGuessKindToResource用来根据Kind推测资源的复数和单数名称。
+func UnsafeGuessKindToResource(kind GroupVersionKind) (plural, singular GroupVersionResource) {
+	kindName := kind.Kind
+	if len(kindName) == 0 {
+		return GroupVersionResource{}, GroupVersionResource{}
+	}
+	singularName := strings.ToLower(kindName)
+	singular = kind.GroupVersion().WithResource(singularName)
+
+	switch string(singularName[len(singularName)-1]) {
+	case "s":
+		return kind.GroupVersion().WithResource(singularName + "es"), singular
+	case "y":
+		if len(singularName) > 1 && !strings.ContainsRune("aeiou", rune(singularName[len(singularName)-2])) {
+			return kind.GroupVersion().WithResource(strings.TrimSuffix(singularName, "y") + "ies"), singular
+		}
+	}
+
+	return kind.GroupVersion().WithResource(singularName + "s"), singular
+}
+
+// coerceResourceForMatching makes the resource lower case and converts
+// internal versions to unspecified (legacy behavior).
+func coerceResourceForMatching(resource GroupVersionResource) GroupVersionResource {
+	resource.Resource = strings.ToLower(resource.Resource)
+	if resource.Version == "__internal" {
+		resource.Version = ""
+	}
+
+	return resource
+}
+
+// matchesResource reports whether the partial resource pattern matches resource.
+func matchesResource(pattern, resource GroupVersionResource) bool {
+	return pattern.Resource == resource.Resource &&
+		(len(pattern.Group) == 0 || pattern.Group == resource.Group) &&
+		(len(pattern.Version) == 0 || pattern.Version == resource.Version)
+}
+
+// NoResourceMatchError is returned when no resource matches a partial resource.
+// NoResourceMatchError表示没有资源匹配。
+type NoResourceMatchError struct {
+	PartialResource GroupVersionResource
+}
+
+func (e *NoResourceMatchError) Error() string {
+	return fmt.Sprintf("no matches for %v", e.PartialResource)
+}
+
+// NoKindMatchError is returned when no mapping is found for a group kind.
+// NoKindMatchError表示没有Kind匹配。
+type NoKindMatchError struct {
+	GroupKind        GroupKind
+	SearchedVersions []string
+}
+
+func (e *NoKindMatchError) Error() string {
+	searchedVersions := strings.Join(e.SearchedVersions, ", ")
+	if len(searchedVersions) == 0 {
+		return fmt.Sprintf("no matches for kind %q in group %q", e.GroupKind.Kind, e.GroupKind.Group)
+	}
+
+	return fmt.Sprintf(
+		"no matches for kind %q in versions %q of group %q",
+		e.GroupKind.Kind,
+		searchedVersions,
+		e.GroupKind.Group,
+	)
+}
+
+// AmbiguousResourceError is returned if the RESTMapper finds multiple matches
+// for a partial resource.
+// AmbiguousResourceError表示匹配到了多个资源。
+type AmbiguousResourceError struct {
+	PartialResource GroupVersionResource
+
+	MatchingResources []GroupVersionResource
+	MatchingKinds     []GroupVersionKind
+}
+
+func (e *AmbiguousResourceError) Error() string {
+	matches := make([]string, 0, len(e.MatchingResources))
+	for _, resource := range e.MatchingResources {
+		matches = append(matches, resource.String())
+	}
+
+	return fmt.Sprintf("%v matches multiple resources: %s", e.PartialResource, strings.Join(matches, ", "))
+}
+
+// IsNoMatchError returns true if err indicates that the RESTMapper found no match.
+// IsNoMatchError用来判断错误是否为没有匹配。
+func IsNoMatchError(err error) bool {
+	var resourceErr *NoResourceMatchError
+	var kindErr *NoKindMatchError
+
+	return errors.As(err, &resourceErr) || errors.As(err, &kindErr)
+}
+
+// IsAmbiguousError returns true if err indicates that the RESTMapper found multiple matches.
+// IsAmbiguousError用来判断错误是否为匹配到了多个资源。
+func IsAmbiguousError(err error) bool {
+	var ambiguousErr *AmbiguousResourceError
+
+	return errors.As(err, &ambiguousErr)
+}
@@ -0,0 +1,235 @@
+// Copyright 2020 Lingfei Kong <colin404@foxmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package scheme
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var (
+	iamV1      = GroupVersion{Group: "iam.example.com", Version: "v1"}
+	iamV2      = GroupVersion{Group: "iam.example.com", Version: "v2"}
+	coreV1     = GroupVersion{Group: "", Version: "v1"}
+	policiesGR = GroupResource{Group: "iam.example.com", Resource: "policies"}
+	secretsGR  = GroupResource{Group: "iam.example.com", Resource: "secrets"}
+)
+
+func newTestRESTMapper() *DefaultRESTMapper {
+	m := NewDefaultRESTMapper([]GroupVersion{iamV2, iamV1})
+	m.Add(iamV1.WithKind("Secret"))
+	m.Add(iamV2.WithKind("Secret"))
+	m.Add(iamV1.WithKind("Policy"))
+	m.AddSpecific(coreV1.WithKind("Secret"), coreV1.WithResource("secrets"), coreV1.WithResource("secret"))
+	m.AddShortNames(secretsGR, "sec")
+	m.AddShortNames(policiesGR, "pol")
+	m.AddCategories(secretsGR, "all")
+	m.AddCategories(policiesGR, "all", "authz")
+
+	return m
+}
+
+func TestUnsafeGuessKindToResource(t *testing.T) {
+	for kind, expected := range map[string]string{
+		"Secret": "secrets",
+		"Policy": "policies",
+		"Key":    "keys",
+		"Status": "statuses",
+		"":       "",
+	} {
+		plural, singular := UnsafeGuessKindToResource(iamV1.WithKind(kind))
+		if plural.Resource != expected {
+			t.Errorf("%q: expected plural %q, got %q", kind, expected, plural.Resource)
+		}
+		if singular.Resource != strings.ToLower(kind) {
+			t.Errorf("%q: unexpected singular %q", kind, singular.Resource)
+		}
+	}
+}
+
+func TestResourceFor(t *testing.T) {
+	m := newTestRESTMapper()
+
+	testCases := []struct {
+		input    GroupVersionResource
+		expected GroupVersionResource
+	}{
+		{GroupVersionResource{Resource: "policies"}, iamV1.WithResource("policies")},
+		{GroupVersionResource{Resource: "Policy"}, iamV1.WithResource("policies")},
+		{GroupVersionResource{Resource: "pol"}, iamV1.WithResource("policies")},
+		{GroupVersionResource{Resource: "sec"}, iamV2.WithResource("secrets")},
+		{GroupVersionResource{Group: "iam.example.com", Resource: "secret"}, iamV2.WithResource("secrets")},
+		{GroupVersionResource{Group: "iam.example.com", Version: "v1", Resource: "sec"}, iamV1.WithResource("secrets")},
+		{GroupVersionResource{Version: "v1", Resource: "policy"}, iamV1.WithResource("policies")},
+	}
+	for _, tc := range testCases {
+		resource, err := m.ResourceFor(tc.input)
+		if err != nil {
+			t.Errorf("%v: unexpected error: %v", tc.input, err)
+
+			continue
+		}
+		if resource != tc.expected {
+			t.Errorf("%v: expected %v, got %v", tc.input, tc.expected, resource)
+		}
+	}
+
+	for _, input := range []GroupVersionResource{
+		{Resource: "keys"},
+		{Group: "", Version: "v2", Resource: "policies"},
+		{Group: "apps", Resource: "sec"},
+	} {
+		if _, err := m.ResourceFor(input); !IsNoMatchError(err) {
+			t.Errorf("%v: expected a no match error, got %v", input, err)
+		}
+	}
+}
+
+func TestAmbiguousResource(t *testing.T) {
+	m := newTestRESTMapper()
+
+	resources, err := m.ResourcesFor(GroupVersionResource{Resource: "secrets"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []GroupVersionResource{
+		iamV2.WithResource("secrets"),
+		iamV1.WithResource("secrets"),
+		coreV1.WithResource("secrets"),
+	}
+	if !reflect.DeepEqual(resources, expected) {
+		t.Errorf("expected %v, got %v", expected, resources)
+	}
+
+	if _, err := m.ResourceFor(GroupVersionResource{Resource: "secrets"}); !IsAmbiguousError(err) {
+		t.Errorf("expected an ambiguous error, got %v", err)
+	}
+	_, err = m.KindFor(GroupVersionResource{Resource: "secret"})
+	ambiguous, ok := err.(*AmbiguousResourceError)
+	if !ok || len(ambiguous.MatchingKinds) != 3 || ambiguous.MatchingKinds[2] != coreV1.WithKind("Secret") {
+		t.Errorf("expected an ambiguous error with the matching kinds, got %#v", err)
+	}
+
+	kind, err := m.KindFor(GroupVersionResource{Version: "v1", Resource: "secret"})
+	if !IsAmbiguousError(err) {
+		t.Errorf("expected an ambiguous error, got %v, %v", kind, err)
+	}
+	kind, err = m.KindFor(GroupVersionResource{Group: "", Version: "v1", Resource: "sec"})
+	if err != nil || kind != iamV1.WithKind("Secret") {
+		t.Errorf("unexpected kind %v, %v", kind, err)
+	}
+}
+
+func TestRESTMapping(t *testing.T) {
+	m := newTestRESTMapper()
+
+	testCases := []struct {
+		gk       GroupKind
+		versions []string
+		expected GroupVersionResource
+	}{
+		{GroupKind{Group: "iam.example.com", Kind: "Secret"}, nil, iamV2.WithResource("secrets")},
+		{GroupKind{Group: "iam.example.com", Kind: "Secret"}, []string{"v3", "v1"}, iamV1.WithResource("secrets")},
+		{GroupKind{Group: "iam.example.com", Kind: "Policy"}, nil, iamV1.WithResource("policies")},
+		{GroupKind{Group: "", Kind: "Secret"}, nil, coreV1.WithResource("secrets")},
+	}
+	for _, tc := range testCases {
+		mapping, err := m.RESTMapping(tc.gk, tc.versions...)
+		if err != nil {
+			t.Errorf("%v: unexpected error: %v", tc.gk, err)
+
+			continue
+		}
+		if mapping.Resource != tc.expected || mapping.GroupVersionKind.GroupKind() != tc.gk {
+			t.Errorf("%v: expected %v, got %+v", tc.gk, tc.expected, mapping)
+		}
+	}
+
+	if _, err := m.RESTMapping(GroupKind{Group: "iam.example.com", Kind: "Policy"}, "v2"); !IsNoMatchError(err) {
+		t.Errorf("expected a no match error, got %v", err)
+	}
+	if _, err := m.RESTMapping(GroupKind{Group: "iam.example.com", Kind: "Key"}); !IsNoMatchError(err) {
+		t.Errorf("expected a no match error, got %v", err)
+	}
+}
+
+func TestRESTMappingVersionPriority(t *testing.T) {
+	m := NewDefaultRESTMapper(nil)
+	gk := GroupKind{Group: "iam.example.com", Kind: "Secret"}
+	for _, version := range []string{"v1alpha1", "v10", "v2beta1", "v9", "v2beta2", "foo"} {
+		m.Add(gk.WithVersion(version))
+	}
+
+	// Without default versions, the GA version with the highest number is picked,
+	// not the lexically smallest one.
+	mapping, err := m.RESTMapping(gk)
+	if err != nil || mapping.GroupVersionKind.Version != "v10" {
+		t.Errorf("expected v10, got %+v, %v", mapping, err)
+	}
+}
+
+func TestCompareVersionPriority(t *testing.T) {
+	// From the highest priority to the lowest.
+	versions := []string{"v10", "v2", "v1", "v11beta2", "v10beta3", "v3beta1", "v12alpha1", "v11alpha2", "foo1", "foo10"}
+	for i := range versions {
+		for j := range versions {
+			cmp := compareVersionPriority(versions[i], versions[j])
+			if (i < j && cmp <= 0) || (i > j && cmp >= 0) || (i == j && cmp != 0) {
+				t.Errorf("unexpected comparison %d of %s and %s", cmp, versions[i], versions[j])
+			}
+		}
+	}
+}
+
+func TestCategoriesAndSingularizer(t *testing.T) {
+	m := newTestRESTMapper()
+
+	resources, ok := m.Expand("ALL")
+	if !ok || !reflect.DeepEqual(resources, []GroupResource{secretsGR, policiesGR}) {
+		t.Errorf("unexpected resources %v", resources)
+	}
+	if _, ok := m.Expand("unknown"); ok {
+		t.Errorf("expected an unknown category")
+	}
+
+	if singular, err := m.ResourceSingularizer("policies"); err != nil || singular != "policy" {
+		t.Errorf("unexpected singular %q, %v", singular, err)
+	}
+	// The same singular is picked on every call, whatever the iteration order of the map.
+	m.AddSpecific(
+		iamV2.WithKind("Policy"),
+		iamV2.WithResource("policies"),
+		iamV2.WithResource("accesspolicy"),
+	)
+	for i := 0; i < 20; i++ {
+		if singular, err := m.ResourceSingularizer("policies"); err != nil || singular != "accesspolicy" {
+			t.Fatalf("unexpected singular %q, %v", singular, err)
+		}
+	}
+	if _, err := m.ResourceSingularizer("keys"); !IsNoMatchError(err) {
+		t.Errorf("expected a no match error, got %v", err)
+	}
+}
+
+func TestMapResourceArg(t *testing.T) {
+	m := newTestRESTMapper()
+
+	for arg, expected := range map[string]GroupVersionResource{
+		"sec":                        iamV2.WithResource("secrets"),
+		"secrets.iam.example.com":    iamV2.WithResource("secrets"),
+		"secrets.v1.iam.example.com": iamV1.WithResource("secrets"),
+		"Policy":                     iamV1.WithResource("policies"),
+	} {
+		resource, err := MapResourceArg(m, arg)
+		if err != nil || resource != expected {
+			t.Errorf("%q: expected %v, got %v, %v", arg, expected, resource, err)
+		}
+	}
+
+	if _, err := MapResourceArg(m, "secrets"); !IsAmbiguousError(err) {
+		t.Errorf("expected an ambiguous error, got %v", err)
+	}
+}
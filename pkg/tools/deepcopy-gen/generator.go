@@ -0,0 +1,773 @@
+// Copyright 2020 Lingfei Kong <colin404@foxmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	markerPrefix     = "+deepcopy-gen="
+	interfacesPrefix = "+deepcopy-gen:interfaces="
+	runtimePackage   = "github.com/HappyLadySauce/component-base/pkg/runtime"
+)
+
+const deepCopyIntoComment = "// DeepCopyInto is an autogenerated deepcopy function, " +
+	"copying the receiver, writing into out. in must be non-nil.\n"
+
+// basicTypes are the predeclared types which are copied by assignment.
+var basicTypes = map[string]bool{
+	"bool": true, "string": true, "byte": true, "rune": true,
+	"int": true, "int8": true, "int16": true, "int32": true, "int64": true,
+	"uint": true, "uint8": true, "uint16": true, "uint32": true, "uint64": true, "uintptr": true,
+	"float32": true, "float64": true, "complex64": true, "complex128": true,
+}
+
+// knownShallowTypes are types from outside the module which are safe to copy by assignment.
+var knownShallowTypes = map[string]bool{
+	"time.Time":     true,
+	"time.Duration": true,
+	"time.Month":    true,
+	"time.Weekday":  true,
+}
+
+// typeInfo is a named type declared in a package.
+type typeInfo struct {
+	name       string
+	expr       ast.Expr
+	file       *ast.File
+	marker     string
+	interfaces []string
+}
+
+// packageInfo is a parsed package.
+type packageInfo struct {
+	path    string
+	dir     string
+	name    string
+	files   []*ast.File
+	enabled bool
+	types   map[string]*typeInfo
+	// copiers holds the types with a hand written or previously generated DeepCopyInto.
+	copiers map[string]bool
+}
+
+// typeContext locates a type expression: its package and the file it appears in.
+type typeContext struct {
+	pkg  *packageInfo
+	file *ast.File
+}
+
+// generator generates deep copy functions for the packages of a Go module.
+type generator struct {
+	fset       *token.FileSet
+	modulePath string
+	moduleRoot string
+	outputBase string
+	header     []byte
+	packages   map[string]*packageInfo
+}
+
+func newGenerator(dir, outputBase string, header []byte) (*generator, error) {
+	root, modulePath, err := findModule(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	return &generator{
+		fset:       token.NewFileSet(),
+		modulePath: modulePath,
+		moduleRoot: root,
+		outputBase: outputBase,
+		header:     header,
+		packages:   map[string]*packageInfo{},
+	}, nil
+}
+
+// findModule returns the root directory and the path of the module containing dir.
+func findModule(dir string) (string, string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", "", err
+	}
+
+	for d := dir; ; d = filepath.Dir(d) {
+		data, err := ioutil.ReadFile(filepath.Join(d, "go.mod"))
+		if err == nil {
+			scanner := bufio.NewScanner(bytes.NewReader(data))
+			for scanner.Scan() {
+				fields := strings.Fields(scanner.Text())
+				if len(fields) == 2 && fields[0] == "module" {
+					return d, strings.Trim(fields[1], `"`), nil
+				}
+			}
+
+			return "", "", fmt.Errorf("%s/go.mod has no module directive", d)
+		}
+		if filepath.Dir(d) == d {
+			return "", "", fmt.Errorf("no go.mod found for %s", dir)
+		}
+	}
+}
+
+// importPath returns the import path of the package in dir.
+func (g *generator) importPath(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	rel, err := filepath.Rel(g.moduleRoot, dir)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return "", fmt.Errorf("%s is not in module %s", dir, g.modulePath)
+	}
+	if rel == "." {
+		return g.modulePath, nil
+	}
+
+	return g.modulePath + "/" + filepath.ToSlash(rel), nil
+}
+
+// load parses the package with the given import path, which must belong to the module.
+func (g *generator) load(path string) (*packageInfo, error) {
+	if pkg, ok := g.packages[path]; ok {
+		return pkg, nil
+	}
+
+	dir := filepath.Join(g.moduleRoot, filepath.FromSlash(strings.TrimPrefix(path, g.modulePath)))
+	pkg := &packageInfo{path: path, dir: dir, types: map[string]*typeInfo{}, copiers: map[string]bool{}}
+	g.packages[path] = pkg
+
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, info := range infos {
+		name := info.Name()
+		if info.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(g.fset, filepath.Join(dir, name), nil, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		pkg.name = file.Name.Name
+		pkg.files = append(pkg.files, file)
+		// The output file of the package is regenerated, so what it declares does not count.
+		if name != g.outputBase+".go" {
+			g.collect(pkg, file)
+		}
+	}
+
+	return pkg, nil
+}
+
+// collect records the markers, types and DeepCopyInto methods declared in file.
+func (g *generator) collect(pkg *packageInfo, file *ast.File) {
+	for _, group := range file.Comments {
+		if group.Pos() < file.Package && markerValue(group, markerPrefix) == "package" {
+			pkg.enabled = true
+		}
+	}
+
+	for _, decl := range file.Decls {
+		switch decl := decl.(type) {
+		case *ast.GenDecl:
+			if decl.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range decl.Specs {
+				spec := spec.(*ast.TypeSpec)
+				if spec.Assign.IsValid() {
+					continue
+				}
+				doc := spec.Doc
+				if doc == nil && len(decl.Specs) == 1 {
+					doc = decl.Doc
+				}
+				pkg.types[spec.Name.Name] = &typeInfo{
+					name:       spec.Name.Name,
+					expr:       spec.Type,
+					file:       file,
+					marker:     markerValue(doc, markerPrefix),
+					interfaces: markerValues(doc, interfacesPrefix),
+				}
+			}
+		case *ast.FuncDecl:
+			if decl.Recv == nil || decl.Name.Name != "DeepCopyInto" || len(decl.Recv.List) != 1 {
+				continue
+			}
+			recv := decl.Recv.List[0].Type
+			if star, ok := recv.(*ast.StarExpr); ok {
+				recv = star.X
+			}
+			if ident, ok := recv.(*ast.Ident); ok {
+				pkg.copiers[ident.Name] = true
+			}
+		}
+	}
+}
+
+func markerValues(doc *ast.CommentGroup, prefix string) []string {
+	if doc == nil {
+		return nil
+	}
+
+	var values []string
+	for _, comment := range doc.List {
+		line := strings.TrimSpace(strings.TrimPrefix(comment.Text, "//"))
+		if strings.HasPrefix(line, prefix) {
+			values = append(values, strings.TrimSpace(strings.TrimPrefix(line, prefix)))
+		}
+	}
+
+	return values
+}
+
+func markerValue(doc *ast.CommentGroup, prefix string) string {
+	values := markerValues(doc, prefix)
+	if len(values) == 0 {
+		return ""
+	}
+
+	return values[len(values)-1]
+}
+
+// generated returns true if deep copy functions are generated for t.
+func (pkg *packageInfo) generated(t *typeInfo) bool {
+	switch t.expr.(type) {
+	case *ast.StructType, *ast.MapType:
+	case *ast.ArrayType:
+		if t.expr.(*ast.ArrayType).Len != nil {
+			return false
+		}
+	default:
+		return false
+	}
+
+	switch t.marker {
+	case "true":
+		return true
+	case "false":
+		return false
+	default:
+		return pkg.enabled
+	}
+}
+
+// hasDeepCopyInto returns true if the named type has a DeepCopyInto method.
+func (pkg *packageInfo) hasDeepCopyInto(t *typeInfo) bool {
+	return pkg.copiers[t.name] || pkg.generated(t)
+}
+
+// lookup resolves a named type expression to its declaration. It returns nil
+// for predeclared types and types from outside the module.
+func (g *generator) lookup(ctx typeContext, expr ast.Expr) (*typeInfo, typeContext, error) {
+	switch expr := expr.(type) {
+	case *ast.Ident:
+		if t, ok := ctx.pkg.types[expr.Name]; ok {
+			return t, typeContext{pkg: ctx.pkg, file: t.file}, nil
+		}
+
+		return nil, ctx, nil
+	case *ast.SelectorExpr:
+		path, err := importOf(ctx.file, expr)
+		if err != nil {
+			return nil, ctx, err
+		}
+		if path != g.modulePath && !strings.HasPrefix(path, g.modulePath+"/") {
+			return nil, ctx, nil
+		}
+		pkg, err := g.load(path)
+		if err != nil {
+			return nil, ctx, err
+		}
+		t, ok := pkg.types[expr.Sel.Name]
+		if !ok {
+			return nil, ctx, fmt.Errorf("type %s not found in %s", expr.Sel.Name, path)
+		}
+
+		return t, typeContext{pkg: pkg, file: t.file}, nil
+	default:
+		return nil, ctx, nil
+	}
+}
+
+// importOf returns the import path of the package qualifying expr.
+func importOf(file *ast.File, expr *ast.SelectorExpr) (string, error) {
+	ident, ok := expr.X.(*ast.Ident)
+	if !ok {
+		return "", fmt.Errorf("unsupported type %s", types.ExprString(expr))
+	}
+	for _, spec := range file.Imports {
+		path, _ := strconv.Unquote(spec.Path.Value)
+		name := filepath.Base(path)
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		if name == ident.Name {
+			return path, nil
+		}
+	}
+
+	return "", fmt.Errorf("unknown package %s", ident.Name)
+}
+
+// shallow returns true if values of type expr can be copied by assignment.
+func (g *generator) shallow(ctx typeContext, expr ast.Expr) (bool, error) {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		if basicTypes[e.Name] {
+			return true, nil
+		}
+		t, tctx, err := g.lookup(ctx, e)
+		if err != nil || t == nil {
+			return false, err
+		}
+
+		return g.shallow(tctx, t.expr)
+	case *ast.SelectorExpr:
+		if path, err := importOf(ctx.file, e); err == nil && knownShallowTypes[path+"."+e.Sel.Name] {
+			return true, nil
+		}
+		t, tctx, err := g.lookup(ctx, e)
+		if err != nil || t == nil {
+			return false, err
+		}
+
+		return g.shallow(tctx, t.expr)
+	case *ast.ArrayType:
+		if e.Len == nil {
+			return false, nil
+		}
+
+		return g.shallow(ctx, e.Elt)
+	case *ast.StructType:
+		for _, f := range e.Fields.List {
+			ok, err := g.shallow(ctx, f.Type)
+			if err != nil || !ok {
+				return false, err
+			}
+		}
+
+		return true, nil
+	default:
+		return false, nil
+	}
+}
+
+func isEmptyInterface(expr ast.Expr) bool {
+	if ident, ok := expr.(*ast.Ident); ok && ident.Name == "any" {
+		return true
+	}
+	iface, ok := expr.(*ast.InterfaceType)
+
+	return ok && len(iface.Methods.List) == 0
+}
+
+// file accumulates the generated code of a package.
+type file struct {
+	g       *generator
+	pkg     *packageInfo
+	buf     bytes.Buffer
+	imports map[string]string
+}
+
+func (f *file) printf(format string, args ...interface{}) {
+	fmt.Fprintf(&f.buf, format, args...)
+}
+
+// typeString prints expr, recording the imports it needs.
+func (f *file) typeString(ctx typeContext, expr ast.Expr) (string, error) {
+	var err error
+	ast.Inspect(expr, func(n ast.Node) bool {
+		sel, ok := n.(*ast.SelectorExpr)
+		if !ok || err != nil {
+			return err == nil
+		}
+		var path string
+		path, err = importOf(ctx.file, sel)
+		if err == nil {
+			err = f.addImport(sel.X.(*ast.Ident).Name, path)
+		}
+
+		return false
+	})
+
+	return types.ExprString(expr), err
+}
+
+func (f *file) addImport(name, path string) error {
+	if existing, ok := f.imports[name]; ok && existing != path {
+		return fmt.Errorf("package name %s refers to both %s and %s", name, existing, path)
+	}
+	f.imports[name] = path
+
+	return nil
+}
+
+// qualified returns the name of the symbol from package path, importing it if needed.
+func (f *file) qualified(path, symbol string) (string, error) {
+	if path == f.pkg.path {
+		return symbol, nil
+	}
+	name := filepath.Base(path)
+	if err := f.addImport(name, path); err != nil {
+		return "", err
+	}
+
+	return name + "." + symbol, nil
+}
+
+// emit writes the statements deep copying in into out. Both are addressable
+// expressions of type expr, and out already holds a shallow copy of in.
+func (f *file) emit(ctx typeContext, expr ast.Expr, in, out string) error {
+	ok, err := f.g.shallow(ctx, expr)
+	if err != nil || ok {
+		return err
+	}
+
+	switch e := expr.(type) {
+	case *ast.Ident, *ast.SelectorExpr:
+		t, tctx, err := f.g.lookup(ctx, e)
+		if err != nil {
+			return err
+		}
+		if t == nil {
+			if _, ok := e.(*ast.Ident); ok && isEmptyInterface(e) {
+				return f.emitInterface(in, out)
+			}
+
+			return fmt.Errorf("can not deep copy %s: it has no DeepCopyInto method", types.ExprString(e))
+		}
+		if tctx.pkg.hasDeepCopyInto(t) {
+			if _, isStruct := t.expr.(*ast.StructType); isStruct {
+				f.printf("%s.DeepCopyInto(&%s)\n", in, out)
+			} else {
+				// Keep nil maps and slices nil.
+				f.printf("if %s != nil {\n%s.DeepCopyInto(&%s)\n}\n", in, in, out)
+			}
+
+			return nil
+		}
+		if _, isStruct := t.expr.(*ast.StructType); isStruct || tctx.pkg != f.pkg {
+			return fmt.Errorf("can not deep copy %s: it has no DeepCopyInto method", types.ExprString(e))
+		}
+
+		return f.emit(tctx, t.expr, in, out)
+	case *ast.StarExpr:
+		f.printf("if %s != nil {\nin, out := &%s, &%s\n", in, in, out)
+		elem, err := f.typeString(ctx, e.X)
+		if err != nil {
+			return err
+		}
+		f.printf("*out = new(%s)\n", elem)
+		ok, err := f.g.shallow(ctx, e.X)
+		if err != nil {
+			return err
+		}
+		if ok {
+			f.printf("**out = **in\n")
+		} else if t, tctx, _ := f.g.lookup(ctx, e.X); t != nil && tctx.pkg.hasDeepCopyInto(t) {
+			f.printf("(*in).DeepCopyInto(*out)\n")
+		} else {
+			f.printf("**out = **in\n")
+			if err := f.emit(ctx, e.X, "(**in)", "(**out)"); err != nil {
+				return err
+			}
+		}
+		f.printf("}\n")
+
+		return nil
+	case *ast.ArrayType, *ast.MapType:
+		f.printf("if %s != nil {\nin, out := &%s, &%s\n", in, in, out)
+		if err := f.emitContainer(ctx, expr, expr); err != nil {
+			return err
+		}
+		f.printf("}\n")
+
+		return nil
+	default:
+		if isEmptyInterface(expr) {
+			return f.emitInterface(in, out)
+		}
+
+		return fmt.Errorf("can not deep copy %s", types.ExprString(expr))
+	}
+}
+
+func (f *file) emitInterface(in, out string) error {
+	copier, err := f.qualified(runtimePackage, "DeepCopyJSONValue")
+	if err != nil {
+		return err
+	}
+	f.printf("%s = %s(%s)\n", out, copier, in)
+
+	return nil
+}
+
+// emitContainer writes the statements deep copying the slice or map *in into *out.
+// typ is the type used to make *out.
+func (f *file) emitContainer(ctx typeContext, expr, typ ast.Expr) error {
+	typeName, err := f.typeString(ctx, typ)
+	if err != nil {
+		return err
+	}
+
+	switch e := expr.(type) {
+	case *ast.ArrayType:
+		if e.Len != nil {
+			return fmt.Errorf("can not deep copy array %s", types.ExprString(e))
+		}
+		f.printf("*out = make(%s, len(*in))\ncopy(*out, *in)\n", typeName)
+		ok, err := f.g.shallow(ctx, e.Elt)
+		if err != nil || ok {
+			return err
+		}
+		f.printf("for i := range *in {\n")
+		if err := f.emit(ctx, e.Elt, "(*in)[i]", "(*out)[i]"); err != nil {
+			return err
+		}
+		f.printf("}\n")
+	case *ast.MapType:
+		if ok, err := f.g.shallow(ctx, e.Key); err != nil || !ok {
+			return fmt.Errorf("can not deep copy map key %s", types.ExprString(e.Key))
+		}
+		f.printf("*out = make(%s, len(*in))\nfor key, val := range *in {\n", typeName)
+		ok, err := f.g.shallow(ctx, e.Value)
+		switch {
+		case err != nil:
+			return err
+		case ok:
+			f.printf("(*out)[key] = val\n")
+		case isEmptyInterface(e.Value):
+			if err := f.emitInterface("val", "(*out)[key]"); err != nil {
+				return err
+			}
+		default:
+			f.printf("outVal := val\n")
+			if err := f.emit(ctx, e.Value, "val", "outVal"); err != nil {
+				return err
+			}
+			f.printf("(*out)[key] = outVal\n")
+		}
+		f.printf("}\n")
+	}
+
+	return nil
+}
+
+// generateType writes the deep copy functions of t.
+func (f *file) generateType(t *typeInfo) error {
+	ctx := typeContext{pkg: f.pkg, file: t.file}
+	name := t.name
+
+	switch e := t.expr.(type) {
+	case *ast.StructType:
+		f.printf(deepCopyIntoComment)
+		f.printf("func (in *%s) DeepCopyInto(out *%s) {\n*out = *in\n", name, name)
+		for _, field := range e.Fields.List {
+			names := field.Names
+			if len(names) == 0 {
+				names = []*ast.Ident{embeddedName(field.Type)}
+			}
+			for _, n := range names {
+				if err := f.emit(ctx, field.Type, "in."+n.Name, "out."+n.Name); err != nil {
+					return fmt.Errorf("%s.%s: %w", name, n.Name, err)
+				}
+			}
+		}
+		f.printf("return\n}\n\n")
+
+		f.printf("// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new %s.\n", name)
+		f.printf("func (in *%s) DeepCopy() *%s {\nif in == nil {\nreturn nil\n}\n", name, name)
+		f.printf("out := new(%s)\nin.DeepCopyInto(out)\nreturn out\n}\n\n", name)
+
+		for _, iface := range t.interfaces {
+			if err := f.generateInterface(name, iface); err != nil {
+				return err
+			}
+		}
+	default:
+		f.printf(deepCopyIntoComment)
+		f.printf("func (in %s) DeepCopyInto(out *%s) {\n{\nin := &in\n", name, name)
+		if err := f.emitContainer(ctx, e, ast.NewIdent(name)); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		f.printf("return\n}\n}\n\n")
+
+		f.printf("// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new %s.\n", name)
+		f.printf("func (in %s) DeepCopy() %s {\nif in == nil {\nreturn nil\n}\n", name, name)
+		f.printf("out := new(%s)\nin.DeepCopyInto(out)\nreturn *out\n}\n\n", name)
+	}
+
+	return nil
+}
+
+// generateInterface writes the DeepCopy<Interface> method of type name. iface is
+// the import path of the interface package, a dot, and the interface name.
+func (f *file) generateInterface(name, iface string) error {
+	i := strings.LastIndex(iface, ".")
+	if i < 0 {
+		return fmt.Errorf("%s: invalid interface %q", name, iface)
+	}
+	typ, err := f.qualified(iface[:i], iface[i+1:])
+	if err != nil {
+		return err
+	}
+
+	f.printf("// DeepCopy%s is an autogenerated deepcopy function, copying the receiver, creating a new %s.\n",
+		iface[i+1:], typ)
+	f.printf("func (in *%s) DeepCopy%s() %s {\nif c := in.DeepCopy(); c != nil {\nreturn c\n}\nreturn nil\n}\n\n",
+		name, iface[i+1:], typ)
+
+	return nil
+}
+
+func embeddedName(expr ast.Expr) *ast.Ident {
+	switch e := expr.(type) {
+	case *ast.StarExpr:
+		return embeddedName(e.X)
+	case *ast.SelectorExpr:
+		return e.Sel
+	case *ast.Ident:
+		return e
+	default:
+		return ast.NewIdent("_")
+	}
+}
+
+// Generate returns the content of the output file of the package in dir, or nil
+// if no deep copy function has to be generated for it.
+func (g *generator) Generate(dir string) ([]byte, error) {
+	path, err := g.importPath(dir)
+	if err != nil {
+		return nil, err
+	}
+	pkg, err := g.load(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for name, t := range pkg.types {
+		if pkg.generated(t) {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return nil, nil
+	}
+	sort.Strings(names)
+
+	f := &file{g: g, pkg: pkg, imports: map[string]string{}}
+	for _, name := range names {
+		if err := f.generateType(pkg.types[name]); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+
+	var out bytes.Buffer
+	out.Write(g.header)
+	out.WriteString("\n// Code generated by deepcopy-gen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&out, "package %s\n\n", pkg.name)
+	if len(f.imports) > 0 {
+		// Standard library imports go first, in their own group.
+		var std, others bytes.Buffer
+		for _, name := range sortedKeys(f.imports) {
+			path := f.imports[name]
+			w := &others
+			if !strings.Contains(strings.Split(path, "/")[0], ".") {
+				w = &std
+			}
+			if filepath.Base(path) == name {
+				fmt.Fprintf(w, "%q\n", path)
+			} else {
+				fmt.Fprintf(w, "%s %q\n", name, path)
+			}
+		}
+		out.WriteString("import (\n")
+		out.Write(std.Bytes())
+		if std.Len() > 0 && others.Len() > 0 {
+			out.WriteString("\n")
+		}
+		out.Write(others.Bytes())
+		out.WriteString(")\n\n")
+	}
+	out.Write(f.buf.Bytes())
+
+	src, err := format.Source(out.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("%s: formatting generated code: %w", path, err)
+	}
+
+	return src, nil
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// runResult is the outcome of Run for a package.
+type runResult int
+
+const (
+	// upToDate means the output file is up to date.
+	upToDate runResult = iota
+	// changed means the output file was, or in verify mode would be, changed.
+	changed
+	// notGenerated means nothing is generated for the package, and the existing output
+	// file, likely written by hand, is left alone.
+	notGenerated
+)
+
+// Run generates the output file of the package in dir. In verify mode, it only
+// checks the output file is up to date. An existing output file is never removed:
+// when no type of the package is marked, it is left alone.
+func (g *generator) Run(dir string, verify bool) (runResult, error) {
+	src, err := g.Generate(dir)
+	if err != nil {
+		return upToDate, err
+	}
+
+	output := filepath.Join(dir, g.outputBase+".go")
+	existing, err := ioutil.ReadFile(output)
+	if err != nil && !os.IsNotExist(err) {
+		return upToDate, err
+	}
+	if src == nil {
+		if existing == nil {
+			return upToDate, nil
+		}
+
+		return notGenerated, nil
+	}
+	if bytes.Equal(existing, src) {
+		return upToDate, nil
+	}
+	if verify {
+		return changed, nil
+	}
+
+	return changed, ioutil.WriteFile(output, src, 0o644)
+}
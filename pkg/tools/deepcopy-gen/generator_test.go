@@ -0,0 +1,121 @@
+// Copyright 2020 Lingfei Kong <colin404@foxmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"flag"
+	"go/ast"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+func TestGenerate(t *testing.T) {
+	dir := filepath.Join("testdata", "example")
+	golden := filepath.Join(dir, "zz_generated.deepcopy.go.golden")
+
+	g, err := newGenerator(dir, "zz_generated.deepcopy", []byte(defaultHeader))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	src, err := g.Generate(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if *update {
+		if err := ioutil.WriteFile(golden, src, 0o644); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	expected, err := ioutil.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Equal(src, expected) {
+		t.Errorf("generated code does not match %s, run the test with -update:\n%s", golden, src)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	// The packages of the module must be up to date, and checking them must not change anything.
+	for _, dir := range []string{"../../labels", "../../meta/v1"} {
+		g, err := newGenerator(dir, "zz_generated.deepcopy", []byte(defaultHeader))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		result, err := g.Run(dir, true)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result == changed {
+			t.Errorf("%s/zz_generated.deepcopy.go is out of date", dir)
+		}
+	}
+}
+
+func TestRunNotGenerated(t *testing.T) {
+	dir := filepath.Join("testdata", "handwritten")
+	output := filepath.Join(dir, "zz_generated.deepcopy.go")
+	expected, err := ioutil.ReadFile(output)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	g, err := newGenerator(dir, "zz_generated.deepcopy", []byte(defaultHeader))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Without marked types, the hand-written file is reported but left alone, in both modes.
+	for _, verify := range []bool{true, false} {
+		result, err := g.Run(dir, verify)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result != notGenerated {
+			t.Errorf("verify=%v: expected notGenerated, got %v", verify, result)
+		}
+		src, err := ioutil.ReadFile(output)
+		if err != nil {
+			t.Fatalf("verify=%v: unexpected error: %v", verify, err)
+		}
+		if !bytes.Equal(src, expected) {
+			t.Errorf("verify=%v: %s was changed", verify, output)
+		}
+	}
+}
+
+func TestUnsupportedType(t *testing.T) {
+	g, err := newGenerator(".", "zz_generated.deepcopy", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	pkg, err := g.load(g.modulePath + "/pkg/tools/deepcopy-gen/testdata/example")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Skipped has no DeepCopyInto method, so a type holding it by pointer can not be copied.
+	pkg.types["Holder"] = &typeInfo{
+		name:   "Holder",
+		file:   pkg.types["Secret"].file,
+		marker: "true",
+		expr:   pkg.types["Skipped"].expr,
+	}
+	f := &file{g: g, pkg: pkg, imports: map[string]string{}}
+	if err := f.generateType(pkg.types["Holder"]); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	pkg.types["Skipped"].marker = "false"
+	pkg.types["Holder"].expr = &ast.StructType{Fields: &ast.FieldList{List: []*ast.Field{
+		{Names: []*ast.Ident{ast.NewIdent("S")}, Type: &ast.StarExpr{X: ast.NewIdent("Skipped")}},
+	}}}
+	if err := f.generateType(pkg.types["Holder"]); err == nil {
+		t.Errorf("expected an error for a type without DeepCopyInto")
+	}
+}
@@ -0,0 +1,175 @@
+// Copyright 2020 Lingfei Kong <colin404@foxmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// This program generates DeepCopy, DeepCopyInto and DeepCopy<Interface> methods.
+// See usage with "deepcopy-gen -h".
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+const helpText = `Usage: deepcopy-gen [flags] dir [dir ...]
+
+The program generates deep copy functions for the types of the packages in
+the given directories. A directory ending with "/..." also selects all the
+directories below it.
+
+Types are selected with marker comments:
+
+  // +deepcopy-gen=package      in the package doc comment, selects every
+                                struct, map and slice type of the package.
+  // +deepcopy-gen=true         in a type doc comment, selects the type.
+  // +deepcopy-gen=false        in a type doc comment, skips the type.
+  // +deepcopy-gen:interfaces=github.com/HappyLadySauce/component-base/pkg/runtime.Object
+                                in a type doc comment, also generates a
+                                DeepCopyObject method returning the interface.
+
+Flags:
+`
+
+const defaultHeader = `// Copyright 2020 Lingfei Kong <colin404@foxmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+`
+
+var (
+	outputBase = pflag.StringP(
+		"output-file-base",
+		"O",
+		"zz_generated.deepcopy",
+		"base name (without .go suffix) for output files",
+	)
+	headerFile = pflag.StringP("go-header-file", "", "", "file containing the boilerplate header of generated files")
+	verify     = pflag.BoolP(
+		"verify",
+		"",
+		false,
+		"verify only mode: exit with non-zero code if an output file is missing or out of date",
+	)
+	help = pflag.BoolP("help", "h", false, "show this help message")
+)
+
+func usage() {
+	fmt.Print(helpText)
+	pflag.PrintDefaults()
+}
+
+func main() {
+	pflag.Usage = usage
+	pflag.Parse()
+
+	if *help || pflag.NArg() == 0 {
+		pflag.Usage()
+		os.Exit(1)
+	}
+
+	header := []byte(defaultHeader)
+	if *headerFile != "" {
+		var err error
+		if header, err = ioutil.ReadFile(*headerFile); err != nil {
+			fmt.Printf("header file: %v\n", err)
+			os.Exit(1)
+		}
+		header = commentHeader(header)
+	}
+
+	dirs, err := expandDirs(pflag.Args())
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(1)
+	}
+
+	g, err := newGenerator(dirs[0], *outputBase, header)
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(1)
+	}
+
+	failed := false
+	for _, dir := range dirs {
+		result, err := g.Run(dir, *verify)
+		if err != nil {
+			fmt.Println(err.Error())
+			os.Exit(1)
+		}
+		output := filepath.Join(dir, *outputBase+".go")
+		switch {
+		case result == notGenerated:
+			fmt.Printf("%s left alone, no type of the package is marked\n", output)
+		case result == changed && *verify:
+			fmt.Printf("%s is out of date\n", output)
+			failed = true
+		case result == changed:
+			fmt.Printf("%s updated\n", output)
+		}
+	}
+
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// commentHeader turns a plain text header, such as boilerplate.txt, into Go comments.
+func commentHeader(header []byte) []byte {
+	text := strings.TrimSpace(string(header))
+	if strings.HasPrefix(text, "//") || strings.HasPrefix(text, "/*") {
+		return []byte(text + "\n")
+	}
+
+	var b strings.Builder
+	for _, line := range strings.Split(text, "\n") {
+		b.WriteString(strings.TrimSpace("// " + line))
+		b.WriteString("\n")
+	}
+
+	return []byte(b.String())
+}
+
+// expandDirs expands the directories ending with "/..." to the directories
+// below them which contain Go files.
+func expandDirs(args []string) ([]string, error) {
+	var dirs []string
+	for _, arg := range args {
+		root := strings.TrimSuffix(arg, "...")
+		if root == arg {
+			dirs = append(dirs, arg)
+
+			continue
+		}
+
+		err := filepath.Walk(filepath.Clean(root), func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if !info.IsDir() {
+				return nil
+			}
+			if name := info.Name(); path != filepath.Clean(root) &&
+				(name == "testdata" || name == "vendor" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")) {
+				return filepath.SkipDir
+			}
+			if _, err := os.Stat(filepath.Join(path, "go.mod")); err == nil && path != filepath.Clean(root) {
+				// Nested modules are generated separately.
+				return filepath.SkipDir
+			}
+			if matches, _ := filepath.Glob(filepath.Join(path, "*.go")); len(matches) > 0 {
+				dirs = append(dirs, path)
+			}
+
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return dirs, nil
+}
@@ -0,0 +1,50 @@
+// Copyright 2020 Lingfei Kong <colin404@foxmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package example
+
+import (
+	"time"
+
+	metav1 "github.com/HappyLadySauce/component-base/pkg/meta/v1"
+)
+
+// Secret is a generated type.
+// +deepcopy-gen=true
+// +deepcopy-gen:interfaces=github.com/HappyLadySauce/component-base/pkg/runtime.Object
+type Secret struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Data      map[string][]byte
+	Expires   *time.Time
+	Children  []*Secret
+	Parent    *Secret
+	Nested    map[string]map[string]int
+	Policies  Policies
+	Raw       interface{}
+	Attempts  [3]int
+	Rotations int64
+}
+
+// Policies is a generated named slice.
+// +deepcopy-gen=true
+type Policies []Policy
+
+// Policy is a generated type.
+// +deepcopy-gen=true
+type Policy struct {
+	Name  string
+	Rules map[string]*Rule
+}
+
+// Rule is not generated and is copied by assignment.
+type Rule struct {
+	Verb string
+}
+
+// Skipped is not generated.
+type Skipped struct {
+	Data map[string]string
+}
@@ -0,0 +1,11 @@
+// Copyright 2020 Lingfei Kong <colin404@foxmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// Package handwritten has no marked type, its deep copy functions are written by hand.
+package handwritten
+
+// Pair is not marked.
+type Pair struct {
+	Values []string
+}
@@ -0,0 +1,14 @@
+// Copyright 2020 Lingfei Kong <colin404@foxmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package handwritten
+
+// DeepCopyInto copies the receiver into out.
+func (in *Pair) DeepCopyInto(out *Pair) {
+	*out = *in
+	if in.Values != nil {
+		out.Values = make([]string, len(in.Values))
+		copy(out.Values, in.Values)
+	}
+}
@@ -221,6 +221,37 @@ func InternalError(field *Path, err error) *Error {
 // we can keep it simple and leave ErrorList here.
 type ErrorList []*Error
 
+// ExternalError is a field-level validation error of a package which cannot
+// depend on this one, such as the *SchemaError of yunion.io/x/jsonutils.
+type ExternalError interface {
+	error
+	// FieldErrorType returns one of the ErrorType values.
+	FieldErrorType() string
+	// FieldPath returns the path of the field, such as spec.ports[0].name.
+	FieldPath() string
+	// FieldBadValue returns the invalid value, or nil.
+	FieldBadValue() interface{}
+	// FieldDetail returns the detail of the error.
+	FieldDetail() string
+}
+
+// FromExternalErrors converts errs to an ErrorList. An ExternalError is
+// converted field by field; any other error is an InternalError of the root.
+func FromExternalErrors(errs []error) ErrorList {
+	if len(errs) == 0 {
+		return nil
+	}
+	list := make(ErrorList, 0, len(errs))
+	for _, err := range errs {
+		if e, ok := err.(ExternalError); ok {
+			list = append(list, &Error{ErrorType(e.FieldErrorType()), e.FieldPath(), e.FieldBadValue(), e.FieldDetail()})
+			continue
+		}
+		list = append(list, InternalError(nil, err))
+	}
+	return list
+}
+
 // NewErrorTypeMatcher returns an errors.Matcher that returns true
 // if the provided error is a Error and has the provided ErrorType.
 func NewErrorTypeMatcher(t ErrorType) utilerrors.Matcher {
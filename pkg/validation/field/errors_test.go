@@ -161,3 +161,60 @@ func TestNotSupported(t *testing.T) {
 		t.Errorf("Expected: %s\n, but got: %s\n", expected, notSupported.ErrorBody())
 	}
 }
+
+// schemaError mimics the *SchemaError of jsonutils.
+type schemaError struct {
+	typ, path, detail string
+	value             interface{}
+}
+
+func (e *schemaError) Error() string              { return e.path + ": " + e.detail }
+func (e *schemaError) FieldErrorType() string     { return e.typ }
+func (e *schemaError) FieldPath() string          { return e.path }
+func (e *schemaError) FieldBadValue() interface{} { return e.value }
+func (e *schemaError) FieldDetail() string        { return e.detail }
+
+func TestFromExternalErrors(t *testing.T) {
+	if list := FromExternalErrors(nil); list != nil {
+		t.Errorf("expected nil, got %v", list)
+	}
+
+	list := FromExternalErrors([]error{
+		&schemaError{"FieldValueRequired", "spec.name", "", nil},
+		&schemaError{"FieldValueInvalid", "spec.ports[0]", "must be at most 65535", int64(70000)},
+		fmt.Errorf("e"),
+	})
+	expected := ErrorList{
+		{ErrorTypeRequired, "spec.name", nil, ""},
+		Invalid(NewPath("spec", "ports").Index(0), int64(70000), "must be at most 65535"),
+		InternalError(nil, fmt.Errorf("e")),
+	}
+	if len(list) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, list)
+	}
+	for i := range expected {
+		if *list[i] != *expected[i] {
+			t.Errorf("[%d] expected %#v, got %#v", i, expected[i], list[i])
+		}
+	}
+	if list[1].Error() != `spec.ports[0]: Invalid value: 70000: must be at most 65535` {
+		t.Errorf("unexpected message %q", list[1].Error())
+	}
+}
+
+func TestSchemaErrorTypes(t *testing.T) {
+	// The values of SchemaErrorType in yunion.io/x/jsonutils, which cannot be
+	// imported here; FromExternalErrors keeps them as they are.
+	schemaErrorTypes := map[string]ErrorType{
+		"FieldValueRequired":     ErrorTypeRequired,
+		"FieldValueInvalid":      ErrorTypeInvalid,
+		"FieldValueNotSupported": ErrorTypeNotSupported,
+		"FieldValueForbidden":    ErrorTypeForbidden,
+	}
+	for value, expected := range schemaErrorTypes {
+		list := FromExternalErrors([]error{&schemaError{value, "spec", "", nil}})
+		if list[0].Type != expected || list[0].Type.String() == value {
+			t.Errorf("%s: expected %s, got %s", value, expected, list[0].Type)
+		}
+	}
+}
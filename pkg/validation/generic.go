@@ -15,17 +15,19 @@ import (
 )
 
 const (
-	qnameCharFmt     string = "[A-Za-z0-9]"
-	qnameExtCharFmt  string = "[-A-Za-z0-9_.]"
-	qualifiedNameFmt string = "(" + qnameCharFmt + qnameExtCharFmt + "*)?" + qnameCharFmt
+	qnameCharFmt    string = "[A-Za-z0-9]"
+	qnameExtCharFmt string = "[-A-Za-z0-9_.]"
+	// QualifiedNameFmt is the format of the name part of a qualified name.
+	QualifiedNameFmt string = "(" + qnameCharFmt + qnameExtCharFmt + "*)?" + qnameCharFmt
 )
 
 const (
-	qualifiedNameErrMsg    string = "must consist of alphanumeric characters, '-', '_' or '.', and must start and end with an alphanumeric character"
-	qualifiedNameMaxLength int    = 63
+	qualifiedNameErrMsg string = "must consist of alphanumeric characters, '-', '_' or '.', and must start and end with an alphanumeric character"
+	// QualifiedNameMaxLength is the max length of the name part of a qualified name.
+	QualifiedNameMaxLength int = 63
 )
 
-var qualifiedNameRegexp = regexp.MustCompile("^" + qualifiedNameFmt + "$")
+var qualifiedNameRegexp = regexp.MustCompile("^" + QualifiedNameFmt + "$")
 
 // IsQualifiedName tests whether the value passed is what IAM calls a
 // "qualified name". This is a format used in various places throughout the
@@ -52,7 +54,7 @@ func IsQualifiedName(value string) []string {
 			errs,
 			"a qualified name "+RegexError(
 				qualifiedNameErrMsg,
-				qualifiedNameFmt,
+				QualifiedNameFmt,
 				"MyName",
 				"my.name",
 				"123-abc",
@@ -62,19 +64,19 @@ func IsQualifiedName(value string) []string {
 
 	if len(name) == 0 {
 		errs = append(errs, "name part "+EmptyError())
-	} else if len(name) > qualifiedNameMaxLength {
-		errs = append(errs, "name part "+MaxLenError(qualifiedNameMaxLength))
+	} else if len(name) > QualifiedNameMaxLength {
+		errs = append(errs, "name part "+MaxLenError(QualifiedNameMaxLength))
 	}
 	if !qualifiedNameRegexp.MatchString(name) {
 		errs = append(
 			errs,
-			"name part "+RegexError(qualifiedNameErrMsg, qualifiedNameFmt, "MyName", "my.name", "123-abc"),
+			"name part "+RegexError(qualifiedNameErrMsg, QualifiedNameFmt, "MyName", "my.name", "123-abc"),
 		)
 	}
 	return errs
 }
 
-const labelValueFmt string = "(" + qualifiedNameFmt + ")?"
+const labelValueFmt string = "(" + QualifiedNameFmt + ")?"
 
 const labelValueErrMsg string = "a valid label must be an empty string or consist of alphanumeric characters, '-', '_' or '.', and must start and end with an alphanumeric character"
 
@@ -119,14 +121,15 @@ func IsDNS1123Label(value string) []string {
 	return errs
 }
 
-const dns1123SubdomainFmt string = dns1123LabelFmt + "(\\." + dns1123LabelFmt + ")*"
+// DNS1123SubdomainFmt is the format of a subdomain in DNS (RFC 1123).
+const DNS1123SubdomainFmt string = dns1123LabelFmt + "(\\." + dns1123LabelFmt + ")*"
 
 const dns1123SubdomainErrorMsg string = "a DNS-1123 subdomain must consist of lower case alphanumeric characters, '-' or '.', and   must start and end with an alphanumeric character"
 
 // DNS1123SubdomainMaxLength is a subdomain's max length in DNS (RFC 1123).
 const DNS1123SubdomainMaxLength int = 253
 
-var dns1123SubdomainRegexp = regexp.MustCompile("^" + dns1123SubdomainFmt + "$")
+var dns1123SubdomainRegexp = regexp.MustCompile("^" + DNS1123SubdomainFmt + "$")
 
 // IsDNS1123Subdomain tests for a string that conforms to the definition of a
 // subdomain in DNS (RFC 1123).
@@ -136,7 +139,7 @@ func IsDNS1123Subdomain(value string) []string {
 		errs = append(errs, MaxLenError(DNS1123SubdomainMaxLength))
 	}
 	if !dns1123SubdomainRegexp.MatchString(value) {
-		errs = append(errs, RegexError(dns1123SubdomainErrorMsg, dns1123SubdomainFmt, "example.com"))
+		errs = append(errs, RegexError(dns1123SubdomainErrorMsg, DNS1123SubdomainFmt, "example.com"))
 	}
 	return errs
 }
@@ -0,0 +1,21 @@
+// Copyright 2020 Lingfei Kong <colin404@foxmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package validation
+
+import (
+	"github.com/HappyLadySauce/component-base/pkg/validation/field"
+)
+
+// JSONSchema is a compiled JSON schema, such as the *JSONSchema of yunion.io/x/jsonutils.
+type JSONSchema interface {
+	// ValidateErrors validates obj and returns the errors, which are converted
+	// field by field when they implement field.ExternalError.
+	ValidateErrors(obj interface{}) []error
+}
+
+// ValidateJSONSchema validates obj against schema and returns the errors as a field.ErrorList.
+func ValidateJSONSchema(schema JSONSchema, obj interface{}) field.ErrorList {
+	return field.FromExternalErrors(schema.ValidateErrors(obj))
+}
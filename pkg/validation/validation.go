@@ -14,12 +14,13 @@ import (
 	"github.com/go-playground/validator/v10"
 	"github.com/go-playground/validator/v10/translations/en"
 
+	metav1 "github.com/HappyLadySauce/component-base/pkg/meta/v1"
 	"github.com/HappyLadySauce/component-base/pkg/validation/field"
 )
 
-const (
-	maxDescriptionLength = 255
-)
+// MaxDescriptionLength is the max length of a description, checked by the
+// description validator.
+const MaxDescriptionLength = 255
 
 // Validator is a custom validator for configs.
 type Validator struct {
@@ -62,7 +63,7 @@ func NewValidator(data interface{}) *Validator {
 		},
 		{
 			tag:         "description",
-			translation: fmt.Sprintf("must be less than %d", maxDescriptionLength),
+			translation: fmt.Sprintf("must be less than %d", MaxDescriptionLength),
 		},
 		{
 			tag:         "name",
@@ -109,6 +110,10 @@ func (v *Validator) Validate() field.ErrorList {
 	// validate policy
 	err := v.val.Struct(v.data)
 	if err == nil {
+		if errs := validateExtend(v.data); len(errs) > 0 {
+			return errs
+		}
+
 		return nil
 	}
 
@@ -127,7 +132,33 @@ func (v *Validator) Validate() field.ErrorList {
 		allErrs = append(allErrs, field.Invalid(field.NewPath(vErr.Namespace()), vErr.Translate(v.trans), ""))
 	}
 
-	return allErrs
+	return append(allErrs, validateExtend(v.data)...)
+}
+
+// validateExtend checks the extend fields of an object against the schema
+// registered for its kind. The kind is taken from TypeMeta when it is set and
+// falls back to the name of the Go type.
+func validateExtend(data interface{}) field.ErrorList {
+	accessor, ok := data.(metav1.ObjectMetaAccessor)
+	if !ok {
+		return nil
+	}
+	meta, ok := accessor.GetObjectMeta().(*metav1.ObjectMeta)
+	if !ok {
+		return nil
+	}
+
+	kind := reflect.Indirect(reflect.ValueOf(data)).Type().Name()
+	if t, ok := data.(metav1.Type); ok && t.GetKind() != "" {
+		kind = t.GetKind()
+	}
+
+	schema, ok := metav1.ExtendSchemaFor(kind)
+	if !ok {
+		return nil
+	}
+
+	return schema.Validate(meta.Extend, field.NewPath("metadata", "extend"))
 }
 
 // validateDir checks if a given string is an existing directory.
@@ -154,7 +185,7 @@ func validateFile(fl validator.FieldLevel) bool {
 func validateDescription(fl validator.FieldLevel) bool {
 	description := fl.Field().String()
 
-	return len(description) <= maxDescriptionLength
+	return len(description) <= MaxDescriptionLength
 }
 
 // validateName checks if a given name is illegal.
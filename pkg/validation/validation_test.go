@@ -5,11 +5,15 @@
 package validation
 
 import (
+	"errors"
 	"io/ioutil"
 	"os"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	metav1 "github.com/HappyLadySauce/component-base/pkg/meta/v1"
+	"github.com/HappyLadySauce/component-base/pkg/validation/field"
 )
 
 // Base is the interface for all configs used in Aptomi (e.g. client config, server config).
@@ -160,3 +164,47 @@ func TestConfigValidation(t *testing.T) {
 		}
 	}
 }
+
+type extendedResource struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+}
+
+func TestExtendSchemaValidation(t *testing.T) {
+	metav1.RegisterExtendSchema("extendedResource", &metav1.ExtendSchema{
+		Fields: map[string]metav1.ExtendField{
+			"owner": {Type: metav1.ExtendFieldString, Required: true},
+		},
+		RejectUnknown: true,
+	})
+
+	valid := &extendedResource{ObjectMeta: metav1.ObjectMeta{Name: "foo", Extend: metav1.Extend{"owner": "colin"}}}
+	assert.Nil(t, NewValidator(valid).Validate())
+
+	invalid := &extendedResource{ObjectMeta: metav1.ObjectMeta{Name: "foo", Extend: metav1.Extend{"junk": 1}}}
+	errs := NewValidator(invalid).Validate()
+	assert.Len(t, errs, 2)
+	assert.Equal(t, "metadata.extend[owner]", errs[0].Field)
+	assert.Equal(t, "metadata.extend[junk]", errs[1].Field)
+
+	// The kind in TypeMeta takes precedence over the Go type name.
+	other := &extendedResource{
+		TypeMeta:   metav1.TypeMeta{Kind: "Other"},
+		ObjectMeta: metav1.ObjectMeta{Name: "foo", Extend: metav1.Extend{"junk": 1}},
+	}
+	assert.Nil(t, NewValidator(other).Validate())
+}
+
+// fakeSchema returns the same errors for any object.
+type fakeSchema []error
+
+func (s fakeSchema) ValidateErrors(obj interface{}) []error {
+	return s
+}
+
+func TestValidateJSONSchema(t *testing.T) {
+	assert.Nil(t, ValidateJSONSchema(fakeSchema(nil), map[string]interface{}{}))
+
+	errs := ValidateJSONSchema(fakeSchema{errors.New("e")}, nil)
+	assert.Equal(t, field.ErrorList{field.InternalError(nil, errors.New("e"))}, errs)
+}
@@ -0,0 +1,217 @@
+// Copyright 2020 Lingfei Kong <colin404@foxmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package watch
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+
+	metav1 "github.com/HappyLadySauce/component-base/pkg/meta/v1"
+)
+
+// FullChannelBehavior controls how the Broadcaster reacts if a watcher's buffer is full.
+// FullChannelBehavior决定监听者缓冲区满时Broadcaster的行为。
+type FullChannelBehavior int
+
+const (
+	// WaitIfChannelFull blocks the Broadcaster until the watcher reads its events.
+	WaitIfChannelFull FullChannelBehavior = iota
+	// DropIfChannelFull drops the events of the watchers which are too slow.
+	DropIfChannelFull
+)
+
+var (
+	// ErrBroadcasterStopped is returned when using a Broadcaster after Shutdown.
+	ErrBroadcasterStopped = errors.New("broadcaster already stopped")
+
+	// ErrTooOldResourceVersion is returned when a watch starts from a resource
+	// version which is no longer in the history of the Broadcaster.
+	ErrTooOldResourceVersion = errors.New("too old resource version")
+)
+
+// Broadcaster distributes event notifications among any number of watchers.
+// Every event gets a resource version, and the last events are kept so that
+// watchers can resume from a resource version they have already seen.
+// Broadcaster用来将事件分发给多个监听者。
+type Broadcaster struct {
+	lock sync.Mutex
+
+	watchers    map[int64]*broadcasterWatcher
+	nextWatcher int64
+	stopped     bool
+
+	resourceVersion uint64
+	// history holds the last events, in resource version order.
+	history       []Event
+	historyLength int
+
+	queueLength         int
+	fullChannelBehavior FullChannelBehavior
+}
+
+// NewBroadcaster creates a new Broadcaster. queueLength is the maximum number of
+// queued events per watcher, historyLength the number of events kept to resume
+// watches, and fullChannelBehavior controls what happens when a watcher's queue is full.
+// NewBroadcaster用来创建Broadcaster。
+func NewBroadcaster(queueLength, historyLength int, fullChannelBehavior FullChannelBehavior) *Broadcaster {
+	return &Broadcaster{
+		watchers:            map[int64]*broadcasterWatcher{},
+		historyLength:       historyLength,
+		queueLength:         queueLength,
+		fullChannelBehavior: fullChannelBehavior,
+	}
+}
+
+// Watch starts watching all the future events.
+// Watch用来监听之后的所有事件。
+func (b *Broadcaster) Watch() (Interface, error) {
+	return b.WatchWithOptions(metav1.WatchOptions{})
+}
+
+// WatchWithOptions starts watching the events matching the selectors of opts.
+// If opts.ResourceVersion is set, the events after that version are replayed
+// first; ErrTooOldResourceVersion is returned if some of them are gone.
+// WatchWithOptions用来监听符合opts的事件。
+func (b *Broadcaster) WatchWithOptions(opts metav1.WatchOptions) (Interface, error) {
+	p, err := newPredicate(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if b.stopped {
+		return nil, ErrBroadcasterStopped
+	}
+
+	var replay []Event
+	if p.replay {
+		if p.since+uint64(len(b.history)) < b.resourceVersion {
+			return nil, ErrTooOldResourceVersion
+		}
+		for _, e := range b.history {
+			if p.matches(e, resourceVersionOf(e)) {
+				replay = append(replay, e)
+			}
+		}
+	} else {
+		p.since = b.resourceVersion
+	}
+
+	w := &broadcasterWatcher{
+		result:  make(chan Event, len(replay)+b.queueLength),
+		stopped: make(chan struct{}),
+		id:      b.nextWatcher,
+		m:       b,
+		p:       p,
+	}
+	for _, e := range replay {
+		w.result <- e
+	}
+	b.watchers[w.id] = w
+	b.nextWatcher++
+
+	return w, nil
+}
+
+// Action distributes the given event among all the watchers.
+// Action用来将事件分发给所有的监听者。
+func (b *Broadcaster) Action(action EventType, obj interface{}) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if b.stopped {
+		return ErrBroadcasterStopped
+	}
+
+	b.resourceVersion++
+	e := Event{Type: action, Object: obj, ResourceVersion: strconv.FormatUint(b.resourceVersion, 10)}
+	if b.historyLength > 0 {
+		if len(b.history) == b.historyLength {
+			b.history = b.history[1:]
+		}
+		b.history = append(b.history, e)
+	}
+
+	for _, w := range b.watchers {
+		if w.p.matches(e, b.resourceVersion) {
+			b.send(w, e)
+		}
+	}
+
+	return nil
+}
+
+func (b *Broadcaster) send(w *broadcasterWatcher, e Event) {
+	if b.fullChannelBehavior == DropIfChannelFull {
+		select {
+		case w.result <- e:
+		case <-w.stopped:
+		default:
+		}
+
+		return
+	}
+
+	select {
+	case w.result <- e:
+	case <-w.stopped:
+	}
+}
+
+// Shutdown disconnects all watchers. Any further call to Watch or Action fails.
+// Shutdown用来断开所有的监听者。
+func (b *Broadcaster) Shutdown() {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	for id, w := range b.watchers {
+		delete(b.watchers, id)
+		close(w.result)
+	}
+	b.stopped = true
+}
+
+// stopWatching stops the given watcher and removes it from the list.
+func (b *Broadcaster) stopWatching(id int64) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if w, ok := b.watchers[id]; ok {
+		delete(b.watchers, id)
+		close(w.result)
+	}
+}
+
+// broadcasterWatcher handles a single watcher of a Broadcaster.
+type broadcasterWatcher struct {
+	result  chan Event
+	stopped chan struct{}
+	stop    sync.Once
+	id      int64
+	m       *Broadcaster
+	p       *predicate
+}
+
+// ResultChan returns a channel to use for waiting on events.
+func (w *broadcasterWatcher) ResultChan() <-chan Event {
+	return w.result
+}
+
+// Stop stops watching and removes w from the Broadcaster.
+func (w *broadcasterWatcher) Stop() {
+	w.stop.Do(func() {
+		close(w.stopped)
+		w.m.stopWatching(w.id)
+	})
+}
+
+func resourceVersionOf(e Event) uint64 {
+	rv, _ := strconv.ParseUint(e.ResourceVersion, 10, 64)
+
+	return rv
+}
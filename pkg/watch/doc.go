@@ -0,0 +1,7 @@
+// Copyright 2020 Lingfei Kong <colin404@foxmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// Package watch contains a generic watchable interface, and an in-process
+// broadcaster which fans events out to many watchers.
+package watch // import "github.com/HappyLadySauce/component-base/pkg/watch"
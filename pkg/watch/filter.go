@@ -0,0 +1,99 @@
+// Copyright 2020 Lingfei Kong <colin404@foxmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package watch
+
+import (
+	"strconv"
+
+	"github.com/HappyLadySauce/component-base/pkg/fields"
+	"github.com/HappyLadySauce/component-base/pkg/labels"
+	metav1 "github.com/HappyLadySauce/component-base/pkg/meta/v1"
+	"github.com/HappyLadySauce/component-base/pkg/validation/field"
+)
+
+// predicate selects the events a watcher receives.
+type predicate struct {
+	label labels.Selector
+	field fields.Selector
+	// since is the resource version after which events are sent.
+	since uint64
+	// replay is true if events before the start of the watch must be replayed.
+	replay bool
+}
+
+// newPredicate parses the selectors and the resource version of opts.
+func newPredicate(opts metav1.WatchOptions) (*predicate, error) {
+	allErrs := field.ErrorList{}
+	p := &predicate{label: labels.Everything(), field: fields.Everything()}
+
+	if opts.LabelSelector != "" {
+		selector, err := labels.Parse(opts.LabelSelector)
+		if err != nil {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("labelSelector"), opts.LabelSelector, err.Error()))
+		} else {
+			p.label = selector
+		}
+	}
+	if opts.FieldSelector != "" {
+		selector, err := fields.ParseSelector(opts.FieldSelector)
+		if err != nil {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("fieldSelector"), opts.FieldSelector, err.Error()))
+		} else {
+			p.field = selector
+		}
+	}
+	if opts.ResourceVersion != "" && opts.ResourceVersion != "0" {
+		rv, err := strconv.ParseUint(opts.ResourceVersion, 10, 64)
+		if err != nil {
+			allErrs = append(
+				allErrs,
+				field.Invalid(field.NewPath("resourceVersion"), opts.ResourceVersion, "must be an unsigned integer"),
+			)
+		}
+		p.since, p.replay = rv, true
+	}
+	if len(allErrs) > 0 {
+		return nil, allErrs.ToAggregate()
+	}
+
+	return p, nil
+}
+
+// matches returns true if e must be sent to the watcher. Error events always match.
+func (p *predicate) matches(e Event, rv uint64) bool {
+	if rv <= p.since {
+		return false
+	}
+	if e.Type == Error || (p.label.Empty() && p.field.Empty()) {
+		return true
+	}
+
+	obj := objectMeta(e.Object)
+	if obj == nil {
+		return false
+	}
+
+	return p.label.Matches(labels.Set(obj.GetLabels())) && p.field.Matches(ObjectMetaFields(obj))
+}
+
+// ObjectMetaFields returns the fields of obj which can be used in field selectors.
+// ObjectMetaFields用来获取可用于字段选择器的对象字段。
+func ObjectMetaFields(obj metav1.Object) fields.Set {
+	return fields.Set{
+		"metadata.name":       obj.GetName(),
+		"metadata.instanceID": obj.GetInstanceID(),
+	}
+}
+
+func objectMeta(obj interface{}) metav1.Object {
+	switch o := obj.(type) {
+	case metav1.ObjectMetaAccessor:
+		return o.GetObjectMeta()
+	case metav1.Object:
+		return o
+	default:
+		return nil
+	}
+}
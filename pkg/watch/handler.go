@@ -0,0 +1,80 @@
+// Copyright 2020 Lingfei Kong <colin404@foxmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package watch
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/HappyLadySauce/component-base/pkg/core"
+	"github.com/HappyLadySauce/component-base/pkg/json"
+	metav1 "github.com/HappyLadySauce/component-base/pkg/meta/v1"
+)
+
+// ContentTypeNDJSON is the content type of watch streams: one JSON encoded Event per line.
+const ContentTypeNDJSON = "application/x-ndjson"
+
+// Handler returns a gin handler which streams the events of b as newline
+// delimited JSON. The watch is configured by the WatchOptions in the query string.
+// Handler用来以NDJSON格式推送Broadcaster的事件。
+func Handler(b *Broadcaster) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var opts metav1.WatchOptions
+		if err := c.ShouldBindQuery(&opts); err != nil {
+			writeError(c, http.StatusBadRequest, err)
+
+			return
+		}
+
+		w, err := b.WatchWithOptions(opts)
+		if err != nil {
+			switch {
+			case errors.Is(err, ErrTooOldResourceVersion):
+				writeError(c, http.StatusGone, err)
+			case errors.Is(err, ErrBroadcasterStopped):
+				writeError(c, http.StatusServiceUnavailable, err)
+			default:
+				writeError(c, http.StatusBadRequest, err)
+			}
+
+			return
+		}
+		defer w.Stop()
+
+		var timeout <-chan time.Time
+		if opts.TimeoutSeconds != nil && *opts.TimeoutSeconds > 0 {
+			timer := time.NewTimer(time.Duration(*opts.TimeoutSeconds) * time.Second)
+			defer timer.Stop()
+			timeout = timer.C
+		}
+
+		c.Header("Content-Type", ContentTypeNDJSON)
+		c.Status(http.StatusOK)
+		c.Writer.Flush()
+
+		c.Stream(func(out io.Writer) bool {
+			select {
+			case e, ok := <-w.ResultChan():
+				if !ok {
+					return false
+				}
+
+				return json.NewEncoder(out).Encode(e) == nil
+			case <-timeout:
+				return false
+			case <-c.Request.Context().Done():
+				return false
+			}
+		})
+	}
+}
+
+func writeError(c *gin.Context, status int, err error) {
+	c.JSON(status, core.ErrResponse{Code: status, Message: err.Error()})
+}
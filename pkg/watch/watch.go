@@ -0,0 +1,61 @@
+// Copyright 2020 Lingfei Kong <colin404@foxmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package watch
+
+// Interface can be implemented by anything that knows how to watch and report changes.
+// Interface是可以监听并报告变化的接口。
+type Interface interface {
+	// Stop stops watching. Will close the channel returned by ResultChan(). Releases
+	// any resources used by the watch.
+	Stop()
+
+	// ResultChan returns a chan which will receive all the events. If an error occurs
+	// or Stop() is called, the implementation will close this channel and
+	// release any resources used by the watch.
+	ResultChan() <-chan Event
+}
+
+// EventType defines the possible types of events.
+// EventType是事件的类型。
+type EventType string
+
+const (
+	// Added is sent when an object is created.
+	Added EventType = "ADDED"
+	// Modified is sent when an object is updated.
+	Modified EventType = "MODIFIED"
+	// Deleted is sent when an object is deleted.
+	Deleted EventType = "DELETED"
+	// Error is sent when the watch can not go on; Object describes the error.
+	Error EventType = "ERROR"
+)
+
+// Event represents a single event to a watched resource.
+// Event是被监听资源的一次变化。
+type Event struct {
+	Type EventType `json:"type"`
+
+	// Object is:
+	//  * If Type is Added or Modified: the new state of the object.
+	//  * If Type is Deleted: the state of the object immediately before deletion.
+	//  * If Type is Error: the description of the error.
+	Object interface{} `json:"object"`
+
+	// ResourceVersion identifies the event within its source. It can be passed
+	// back in WatchOptions to resume a watch after this event.
+	ResourceVersion string `json:"resourceVersion,omitempty"`
+}
+
+// ErrorObject is the Object of an Error event.
+// ErrorObject是错误事件携带的对象。
+type ErrorObject struct {
+	Message string `json:"message"`
+}
+
+// NewErrorEvent creates an Error event describing err.
+// NewErrorEvent用来创建描述err的错误事件。
+func NewErrorEvent(err error) Event {
+	return Event{Type: Error, Object: &ErrorObject{Message: err.Error()}}
+}
@@ -0,0 +1,232 @@
+// Copyright 2020 Lingfei Kong <colin404@foxmail.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package watch
+
+import (
+	"bufio"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/HappyLadySauce/component-base/pkg/json"
+	metav1 "github.com/HappyLadySauce/component-base/pkg/meta/v1"
+)
+
+type testObject struct {
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+}
+
+func newObject(name string, labels map[string]string) *testObject {
+	return &testObject{ObjectMeta: metav1.ObjectMeta{InstanceID: name, Name: name, Labels: labels}}
+}
+
+func receive(t *testing.T, w Interface) Event {
+	t.Helper()
+	select {
+	case e, ok := <-w.ResultChan():
+		if !ok {
+			t.Fatalf("result channel closed")
+		}
+
+		return e
+	case <-time.After(time.Second):
+		t.Fatalf("timed out waiting for an event")
+	}
+
+	return Event{}
+}
+
+func expectNothing(t *testing.T, w Interface) {
+	t.Helper()
+	select {
+	case e, ok := <-w.ResultChan():
+		if ok {
+			t.Fatalf("unexpected event %v", e)
+		}
+	default:
+	}
+}
+
+func TestBroadcasterFanOut(t *testing.T) {
+	b := NewBroadcaster(10, 0, WaitIfChannelFull)
+	w1, _ := b.Watch()
+	w2, _ := b.Watch()
+
+	obj := newObject("app-1", nil)
+	if err := b.Action(Added, obj); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, w := range []Interface{w1, w2} {
+		if e := receive(t, w); e.Type != Added || e.Object != obj || e.ResourceVersion != "1" {
+			t.Errorf("unexpected event %v", e)
+		}
+	}
+
+	w1.Stop()
+	if _, ok := <-w1.ResultChan(); ok {
+		t.Errorf("expected the result channel to be closed")
+	}
+	_ = b.Action(Deleted, obj)
+	if e := receive(t, w2); e.Type != Deleted {
+		t.Errorf("unexpected event %v", e)
+	}
+
+	b.Shutdown()
+	if _, ok := <-w2.ResultChan(); ok {
+		t.Errorf("expected the result channel to be closed")
+	}
+	w2.Stop()
+	if err := b.Action(Added, obj); !errors.Is(err, ErrBroadcasterStopped) {
+		t.Errorf("expected ErrBroadcasterStopped, got %v", err)
+	}
+}
+
+func TestBroadcasterFullChannel(t *testing.T) {
+	b := NewBroadcaster(1, 0, DropIfChannelFull)
+	w, _ := b.Watch()
+	_ = b.Action(Added, newObject("app-1", nil))
+	_ = b.Action(Added, newObject("app-2", nil))
+	if e := receive(t, w); e.ResourceVersion != "1" {
+		t.Errorf("unexpected event %v", e)
+	}
+	expectNothing(t, w)
+
+	b = NewBroadcaster(1, 0, WaitIfChannelFull)
+	w, _ = b.Watch()
+	done := make(chan struct{})
+	go func() {
+		_ = b.Action(Added, newObject("app-1", nil))
+		_ = b.Action(Added, newObject("app-2", nil))
+		close(done)
+	}()
+	if e := receive(t, w); e.ResourceVersion != "1" {
+		t.Errorf("unexpected event %v", e)
+	}
+	if e := receive(t, w); e.ResourceVersion != "2" {
+		t.Errorf("unexpected event %v", e)
+	}
+	<-done
+
+	// Stopping a watcher must unblock a pending Action.
+	_ = b.Action(Added, newObject("app-3", nil))
+	done = make(chan struct{})
+	go func() {
+		_ = b.Action(Added, newObject("app-4", nil))
+		close(done)
+	}()
+	time.Sleep(10 * time.Millisecond)
+	w.Stop()
+	<-done
+}
+
+func TestBroadcasterSelectors(t *testing.T) {
+	b := NewBroadcaster(10, 0, WaitIfChannelFull)
+	w, err := b.WatchWithOptions(metav1.WatchOptions{
+		LabelSelector: "app=web",
+		FieldSelector: "metadata.name!=app-2",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_ = b.Action(Added, newObject("app-1", map[string]string{"app": "web"}))
+	_ = b.Action(Added, newObject("app-2", map[string]string{"app": "web"}))
+	_ = b.Action(Added, newObject("app-3", map[string]string{"app": "db"}))
+	_ = b.Action(Error, &ErrorObject{Message: "boom"})
+
+	if e := receive(t, w); e.Object.(*testObject).Name != "app-1" {
+		t.Errorf("unexpected event %v", e)
+	}
+	if e := receive(t, w); e.Type != Error {
+		t.Errorf("unexpected event %v", e)
+	}
+	expectNothing(t, w)
+
+	if _, err := b.WatchWithOptions(metav1.WatchOptions{LabelSelector: "app in (", ResourceVersion: "x"}); err == nil {
+		t.Errorf("expected an error for invalid options")
+	}
+}
+
+func TestBroadcasterResourceVersion(t *testing.T) {
+	b := NewBroadcaster(10, 2, WaitIfChannelFull)
+	for _, name := range []string{"app-1", "app-2", "app-3"} {
+		_ = b.Action(Added, newObject(name, nil))
+	}
+
+	w, err := b.WatchWithOptions(metav1.WatchOptions{ResourceVersion: "1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, rv := range []string{"2", "3"} {
+		if e := receive(t, w); e.ResourceVersion != rv {
+			t.Errorf("expected resource version %s, got %v", rv, e)
+		}
+	}
+	_ = b.Action(Modified, newObject("app-1", nil))
+	if e := receive(t, w); e.Type != Modified || e.ResourceVersion != "4" {
+		t.Errorf("unexpected event %v", e)
+	}
+
+	if _, err := b.WatchWithOptions(metav1.WatchOptions{ResourceVersion: "1"}); !errors.Is(err, ErrTooOldResourceVersion) {
+		t.Errorf("expected ErrTooOldResourceVersion, got %v", err)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	b := NewBroadcaster(10, 10, WaitIfChannelFull)
+	_ = b.Action(Added, newObject("app-1", map[string]string{"app": "web"}))
+	_ = b.Action(Added, newObject("app-2", map[string]string{"app": "db"}))
+
+	router := gin.New()
+	router.GET("/watch", Handler(b))
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/watch?resourceVersion=0&labelSelector=app%3Dweb&timeoutSeconds=1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != ContentTypeNDJSON {
+		t.Fatalf("unexpected response: %d %s", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+
+	// resourceVersion=0 only streams future events.
+	_ = b.Action(Modified, newObject("app-2", map[string]string{"app": "db"}))
+	_ = b.Action(Modified, newObject("app-1", map[string]string{"app": "web"}))
+
+	scanner := bufio.NewScanner(resp.Body)
+	var lines []string
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	if len(lines) != 1 {
+		t.Fatalf("expected one event, got %v", lines)
+	}
+	var e struct {
+		Type   EventType  `json:"type"`
+		Object testObject `json:"object"`
+	}
+	if err := json.Unmarshal([]byte(lines[0]), &e); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if e.Type != Modified || e.Object.Name != "app-1" {
+		t.Errorf("unexpected event %s", lines[0])
+	}
+
+	resp, err = http.Get(server.URL + "/watch?labelSelector=app+in+(")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected status 400, got %d", resp.StatusCode)
+	}
+}